@@ -28,15 +28,20 @@ func parseAuthorizationCodeFlags(name string, args []string, oidcConf *oidc.Conf
 	flags.StringVar(&oidcConf.DiscoveryEndpoint, "discovery-url", oidcConf.DiscoveryEndpoint, "override discovery url")
 	flags.StringVar(&oidcConf.AuthorizationEndpoint, "authorization-url", "", "override authorization url")
 	flags.StringVar(&oidcConf.TokenEndpoint, "token-url", "", "override token url")
+	flags.StringVar(&oidcConf.RevocationEndpoint, "revocation-url", "", "override revocation url")
 	flags.StringVar(&oidcConf.ClientID, "client-id", oidcConf.ClientID, "set client ID (required)")
-	flags.StringVar(&oidcConf.ClientSecret, "client-secret", oidcConf.ClientSecret, "set client secret (required if not using PKCE)")
+	flags.StringVar(&oidcConf.ClientSecret, "client-secret", oidcConf.ClientSecret, "set client secret (required if not using PKCE or another client credential)")
 	flags.BoolVar(&oidcConf.SkipTLSVerify, "skip-tls-verify", oidcConf.SkipTLSVerify, "skip TLS certificate verification")
-	flags.Var(&oidcConf.AuthMethod, "auth-method", "auth method to use (client_secret_basic or client_secret_post)")
-	flags.StringVar(&oidcConf.PrivateKeyFile, "private-key", "", "file to read private key from (eg. for DPoP)")
+	addClientAuthFlags(flags, oidcConf)
+	flags.StringVar(&oidcConf.PrivateKeyFile, "private-key", "", "file to read private key from (eg. for DPoP or private_key_jwt)")
 	flags.StringVar(&oidcConf.PublicKeyFile, "public-key", "", "file to read public key from (eg. for DPoP)")
 
 	var flowConf oidc.AuthorizationCodeFlowConfig
 	flags.StringVar(&flowConf.Scopes, "scopes", "openid", "set scopes as a space separated list")
+	var resources StringsFlag
+	flags.Var(&resources, "resource", "URI of the target resource (RFC 8707), argument can be given multiple times")
+	flags.StringVar(&flowConf.ResponseType, "response-type", "", "set response_type as a space separated list of code, id_token and token (default: code)")
+	flags.StringVar(&flowConf.ResponseMode, "response-mode", "", "set response_mode parameter to query, fragment, form_post or a JARM mode (jwt, query.jwt, fragment.jwt, form_post.jwt)")
 	flags.StringVar(&flowConf.CallbackURI, "callback-uri", "http://localhost:9555/callback",
 		"set callback uri (default: http://localhost:9555/callback), this will also be used as the redirect_uri in the authorization request unless overridden by -redirect-uri")
 	flags.StringVar(&flowConf.RedirectURI, "redirect-uri", "", "set the redirect_uri parameter")
@@ -45,12 +50,20 @@ func parseAuthorizationCodeFlags(name string, args []string, oidcConf *oidc.Conf
 	flags.StringVar(&flowConf.LoginHint, "login-hint", "", "set login_hint parameter")
 	flags.StringVar(&flowConf.MaxAge, "max-age", "", "set max_age parameter")
 	flags.StringVar(&flowConf.UILocales, "ui-locales", "", "set ui_locales parameter")
-	flags.StringVar(&flowConf.State, "state", "", "set state parameter")
+	var claimArgs StringsFlag
+	flags.Var(&claimArgs, "claim", "request a claim as target:name[:essential][=value], target is id_token or userinfo, argument can be given multiple times")
+	flags.StringVar(&flowConf.AuthorizationDetails, "authorization-details", "", "set authorization_details as a JSON array (RAR), '-' reads it from stdin, '@file' from a file")
+	flags.StringVar(&flowConf.State, "state", "", "set state parameter (default: random value, always verified on callback)")
+	flags.StringVar(&flowConf.Nonce, "nonce", "", "set nonce parameter (default: random value, always verified in the ID token)")
+	flags.DurationVar(&flowConf.ClockSkew, "clock-skew", oidc.DefaultClockSkew, "allowed clock skew when validating the ID token")
 	var customArgs CustomArgsFlag
 	flags.Var(&customArgs, "custom", "custom authorization parameters, argument can be given multiple times")
 	flags.BoolVar(&flowConf.PKCE, "pkce", false, "use proof-key for code exchange (PKCE)")
 	flags.BoolVar(&flowConf.PAR, "par", false, "use pushed authorization requests")
 	flags.BoolVar(&flowConf.DPoP, "dpop", false, "use dpop-protected access tokens")
+	flags.BoolVar(&flowConf.RequestObject, "request-object", false, "send the authorization parameters in a request object signed with the private key (JAR)")
+	flags.BoolVar(&flowConf.EncryptRequestObject, "encrypt-request-object", false, "encrypt the request object to the issuer's encryption key")
+	flags.BoolVar(&flowConf.RevokeOnExit, "revoke-on-exit", false, "revoke the refresh token after printing the token response")
 
 	runner = &oidc.AuthorizationCodeFlow{
 		Config:     oidcConf,
@@ -61,6 +74,18 @@ func parseAuthorizationCodeFlags(name string, args []string, oidcConf *oidc.Conf
 	if err != nil {
 		return nil, buf.String(), flag.ErrHelp
 	}
+	flowConf.Resources = resources
+	oidcConf.PrivateKeyForDPoP = flowConf.DPoP
+
+	// build the claims request parameter
+	if len(claimArgs) > 0 {
+		flowConf.Claims = oidc.ClaimsRequest{}
+		for _, arg := range claimArgs {
+			if err := flowConf.Claims.Add(arg); err != nil {
+				return nil, err.Error(), flag.ErrHelp
+			}
+		}
+	}
 
 	// populate custom args
 	if len(customArgs) > 0 {
@@ -88,8 +113,8 @@ func parseAuthorizationCodeFlags(name string, args []string, oidcConf *oidc.Conf
 			"client-id is required",
 		},
 		{
-			oidcConf.ClientSecret == "" && !flowConf.PKCE,
-			"client-secret is required unless using PKCE",
+			!hasClientCredentials(oidcConf) && !flowConf.PKCE && oidc.ResponseTypeIncludes(flowConf.ResponseType, "code"),
+			"client-secret, private-key, client-cert, client-assertion-file or client-assertion-cmd is required unless using PKCE",
 		},
 		{
 			flowConf.Scopes == "",
@@ -103,6 +128,14 @@ func parseAuthorizationCodeFlags(name string, args []string, oidcConf *oidc.Conf
 			flowConf.DPoP && (oidcConf.PrivateKeyFile == "" || oidcConf.PublicKeyFile == ""),
 			"private-key and public-key are required when using DPoP",
 		},
+		{
+			flowConf.RequestObject && oidcConf.PrivateKeyFile == "",
+			"private-key is required when using a request object",
+		},
+		{
+			flowConf.EncryptRequestObject && !flowConf.RequestObject,
+			"encrypt-request-object requires request-object",
+		},
 	}
 
 	for _, check := range invalidArgsChecks {
@@ -110,6 +143,19 @@ func parseAuthorizationCodeFlags(name string, args []string, oidcConf *oidc.Conf
 			return nil, check.message, flag.ErrHelp
 		}
 	}
+	if message := checkClientAuthArgs(oidcConf); message != "" {
+		return nil, message, flag.ErrHelp
+	}
+
+	if err := oidc.ValidateResponseType(flowConf.ResponseType); err != nil {
+		return nil, err.Error(), flag.ErrHelp
+	}
+	if err := oidc.ValidateResponseMode(flowConf.ResponseMode, flowConf.ResponseType); err != nil {
+		return nil, err.Error(), flag.ErrHelp
+	}
+	if flowConf.AuthorizationDetails, err = parseAuthorizationDetailsArg(flowConf.AuthorizationDetails); err != nil {
+		return nil, err.Error(), flag.ErrHelp
+	}
 
 	return runner, buf.String(), nil
 }
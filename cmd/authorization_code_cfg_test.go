@@ -3,6 +3,7 @@ package cmd
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/jentz/oidc-cli/httpclient"
 	"github.com/jentz/oidc-cli/oidc"
@@ -22,6 +23,7 @@ func TestParseAuthorizationCodeFlagsResult(t *testing.T) {
 				"--discovery-url", "https://example.com/.well-known/openid-configuration",
 				"--authorization-url", "https://example.com/authorize",
 				"--token-url", "https://example.com/token",
+				"--revocation-url", "https://example.com/revoke",
 				"--skip-tls-verify",
 				"--client-id", "client-id",
 				"--client-secret", "client-secret",
@@ -33,11 +35,14 @@ func TestParseAuthorizationCodeFlagsResult(t *testing.T) {
 				"--max-age", "max_age",
 				"--ui-locales", "ui_locales",
 				"--state", "state",
+				"--nonce", "nonce",
+				"--clock-skew", "30s",
 				"--custom", "custom1=value1",
 				"--custom", "custom2=value2",
 				"--pkce",
 				"--par",
 				"--dpop",
+				"--revoke-on-exit",
 				"--private-key", "path/to/private-key.pem",
 				"--public-key", "path/to/public-key.pem",
 			},
@@ -46,28 +51,33 @@ func TestParseAuthorizationCodeFlagsResult(t *testing.T) {
 				DiscoveryEndpoint:     "https://example.com/.well-known/openid-configuration",
 				AuthorizationEndpoint: "https://example.com/authorize",
 				TokenEndpoint:         "https://example.com/token",
+				RevocationEndpoint:    "https://example.com/revoke",
 				ClientID:              "client-id",
 				ClientSecret:          "client-secret",
 				SkipTLSVerify:         true,
 				PrivateKeyFile:        "path/to/private-key.pem",
+				PrivateKeyForDPoP:     true,
 				PublicKeyFile:         "path/to/public-key.pem",
 			},
 			oidc.AuthorizationCodeFlowConfig{
 				Scopes:      "openid profile email",
 				CallbackURI: "http://localhost:8080/callback",
+				ClockSkew:   30 * time.Second,
 				Prompt:      "login",
 				AcrValues:   "acr_values",
 				LoginHint:   "login_hint",
 				MaxAge:      "max_age",
 				UILocales:   "ui_locales",
 				State:       "state",
+				Nonce:       "nonce",
 				CustomArgs: &httpclient.CustomArgs{
 					"custom1": "value1",
 					"custom2": "value2",
 				},
-				PKCE: true,
-				PAR:  true,
-				DPoP: true,
+				PKCE:         true,
+				PAR:          true,
+				DPoP:         true,
+				RevokeOnExit: true,
 			},
 		},
 		{
@@ -90,6 +100,7 @@ func TestParseAuthorizationCodeFlagsResult(t *testing.T) {
 			oidc.AuthorizationCodeFlowConfig{
 				Scopes:      "openid profile email",
 				CallbackURI: "http://localhost:8080/callback",
+				ClockSkew:   oidc.DefaultClockSkew,
 				PKCE:        false,
 				PAR:         false,
 				DPoP:        false,
@@ -114,6 +125,7 @@ func TestParseAuthorizationCodeFlagsResult(t *testing.T) {
 			oidc.AuthorizationCodeFlowConfig{
 				Scopes:      "openid",
 				CallbackURI: "http://localhost:8080/callback",
+				ClockSkew:   oidc.DefaultClockSkew,
 				PKCE:        false,
 				PAR:         false,
 				DPoP:        false,
@@ -138,6 +150,7 @@ func TestParseAuthorizationCodeFlagsResult(t *testing.T) {
 			oidc.AuthorizationCodeFlowConfig{
 				Scopes:      "openid profile email",
 				CallbackURI: "http://localhost:9555/callback",
+				ClockSkew:   oidc.DefaultClockSkew,
 				PKCE:        false,
 				PAR:         false,
 				DPoP:        false,
@@ -163,6 +176,7 @@ func TestParseAuthorizationCodeFlagsResult(t *testing.T) {
 			oidc.AuthorizationCodeFlowConfig{
 				Scopes:      "openid profile email",
 				CallbackURI: "http://localhost:9555/callback",
+				ClockSkew:   oidc.DefaultClockSkew,
 				PKCE:        true,
 				PAR:         false,
 				DPoP:        false,
@@ -187,6 +201,7 @@ func TestParseAuthorizationCodeFlagsResult(t *testing.T) {
 			oidc.AuthorizationCodeFlowConfig{
 				Scopes:      "openid profile email",
 				CallbackURI: "http://localhost:9555/callback",
+				ClockSkew:   oidc.DefaultClockSkew,
 				PKCE:        true,
 				PAR:         false,
 				DPoP:        false,
@@ -211,16 +226,132 @@ func TestParseAuthorizationCodeFlagsResult(t *testing.T) {
 				ClientID:              "client-id",
 				ClientSecret:          "client-secret",
 				PrivateKeyFile:        "path/to/private-key.pem",
+				PrivateKeyForDPoP:     true,
 				PublicKeyFile:         "path/to/public-key.pem",
 			},
 			oidc.AuthorizationCodeFlowConfig{
 				Scopes:      "openid profile email",
 				CallbackURI: "http://localhost:9555/callback",
+				ClockSkew:   oidc.DefaultClockSkew,
 				PKCE:        false,
 				PAR:         false,
 				DPoP:        true,
 			},
 		},
+		{
+			"public client with pkce and dpop",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--scopes", "openid",
+				"--pkce",
+				"--dpop",
+				"--private-key", "path/to/private-key.pem",
+				"--public-key", "path/to/public-key.pem",
+			},
+			oidc.Config{
+				IssuerURL:         "https://example.com",
+				ClientID:          "client-id",
+				PrivateKeyFile:    "path/to/private-key.pem",
+				PrivateKeyForDPoP: true,
+				PublicKeyFile:     "path/to/public-key.pem",
+			},
+			oidc.AuthorizationCodeFlowConfig{
+				Scopes:      "openid",
+				CallbackURI: "http://localhost:9555/callback",
+				ClockSkew:   oidc.DefaultClockSkew,
+				PKCE:        true,
+				DPoP:        true,
+			},
+		},
+		{
+			"resources",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--client-secret", "client-secret",
+				"--resource", "https://api.example.com",
+			},
+			oidc.Config{
+				IssuerURL:    "https://example.com",
+				ClientID:     "client-id",
+				ClientSecret: "client-secret",
+			},
+			oidc.AuthorizationCodeFlowConfig{
+				Scopes:      "openid",
+				CallbackURI: "http://localhost:9555/callback",
+				ClockSkew:   oidc.DefaultClockSkew,
+				Resources:   []string{"https://api.example.com"},
+			},
+		},
+		{
+			"claims",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--client-secret", "client-secret",
+				"--claim", "id_token:email:essential",
+				"--claim", "userinfo:given_name",
+			},
+			oidc.Config{
+				IssuerURL:    "https://example.com",
+				ClientID:     "client-id",
+				ClientSecret: "client-secret",
+			},
+			oidc.AuthorizationCodeFlowConfig{
+				Scopes:      "openid",
+				CallbackURI: "http://localhost:9555/callback",
+				ClockSkew:   oidc.DefaultClockSkew,
+				Claims: oidc.ClaimsRequest{
+					"id_token": {"email": {Essential: true}},
+					"userinfo": {"given_name": nil},
+				},
+			},
+		},
+		{
+			"encrypted request object with par",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--private-key", "path/to/private-key.pem",
+				"--par",
+				"--request-object",
+				"--encrypt-request-object",
+			},
+			oidc.Config{
+				IssuerURL:      "https://example.com",
+				ClientID:       "client-id",
+				PrivateKeyFile: "path/to/private-key.pem",
+			},
+			oidc.AuthorizationCodeFlowConfig{
+				Scopes:               "openid",
+				CallbackURI:          "http://localhost:9555/callback",
+				ClockSkew:            oidc.DefaultClockSkew,
+				PAR:                  true,
+				RequestObject:        true,
+				EncryptRequestObject: true,
+			},
+		},
+		{
+			"implicit flow with public client",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--response-type", "id_token token",
+				"--response-mode", "form_post",
+			},
+			oidc.Config{
+				IssuerURL: "https://example.com",
+				ClientID:  "client-id",
+			},
+			oidc.AuthorizationCodeFlowConfig{
+				Scopes:       "openid",
+				ResponseType: "id_token token",
+				ResponseMode: "form_post",
+				CallbackURI:  "http://localhost:9555/callback",
+				ClockSkew:    oidc.DefaultClockSkew,
+			},
+		},
 		{
 			"flags after non-flag argument",
 			[]string{
@@ -242,6 +373,7 @@ func TestParseAuthorizationCodeFlagsResult(t *testing.T) {
 			oidc.AuthorizationCodeFlowConfig{
 				Scopes:      "openid",                         // expecting default value as argument is not parsed
 				CallbackURI: "http://localhost:9555/callback", // expecting default value as argument is not parsed
+				ClockSkew:   oidc.DefaultClockSkew,
 				PKCE:        false,
 				PAR:         false,
 				DPoP:        false,
@@ -286,6 +418,25 @@ func TestParseAuthorizationCodeFlagsError(t *testing.T) {
 				"--callback-uri", "http://localhost:8080/callback",
 			},
 		},
+		{
+			"dpop key is not a client credential",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--dpop",
+				"--private-key", "path/to/private-key.pem",
+				"--public-key", "path/to/public-key.pem",
+			},
+		},
+		{
+			"client-assertion-cmd with another auth method",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--client-assertion-cmd", "cat token",
+				"--auth-method", "client_secret_post",
+			},
+		},
 		{
 			"missing client-secret and pkce",
 			[]string{
@@ -317,6 +468,60 @@ func TestParseAuthorizationCodeFlagsError(t *testing.T) {
 				"--private-key", "path/to/private-key.pem",
 			},
 		},
+		{
+			"invalid response type",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--client-secret", "client-secret",
+				"--response-type", "code code",
+			},
+		},
+		{
+			"tokens in the query",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--client-secret", "client-secret",
+				"--response-type", "code id_token",
+				"--response-mode", "query",
+			},
+		},
+		{
+			"request object without private-key",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--client-secret", "client-secret",
+				"--request-object",
+			},
+		},
+		{
+			"encrypt-request-object without request-object",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--private-key", "path/to/private-key.pem",
+				"--encrypt-request-object",
+			},
+		},
+		{
+			"claim with unknown target",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--client-secret", "client-secret",
+				"--claim", "access_token:email",
+			},
+		},
+		{
+			"hybrid flow without client credentials",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--response-type", "code id_token",
+			},
+		},
 	}
 
 	for _, tt := range tests {
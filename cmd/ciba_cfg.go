@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"bytes"
+	"flag"
+
+	"github.com/jentz/oidc-cli/httpclient"
+	"github.com/jentz/oidc-cli/oidc"
+)
+
+func parseCIBAFlags(name string, args []string, oidcConf *oidc.Config) (runner CommandRunner, output string, err error) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	var buf bytes.Buffer
+	flags.SetOutput(&buf)
+
+	flags.StringVar(&oidcConf.IssuerURL, "issuer", oidcConf.IssuerURL, "set issuer url (required)")
+	flags.StringVar(&oidcConf.DiscoveryEndpoint, "discovery-url", oidcConf.DiscoveryEndpoint, "override discovery url")
+	flags.StringVar(&oidcConf.BackchannelAuthenticationEndpoint, "backchannel-authentication-url", "", "override backchannel authentication url")
+	flags.StringVar(&oidcConf.TokenEndpoint, "token-url", "", "override token url")
+	flags.StringVar(&oidcConf.ClientID, "client-id", oidcConf.ClientID, "set client ID (required)")
+	flags.StringVar(&oidcConf.ClientSecret, "client-secret", oidcConf.ClientSecret, "set client secret (required unless another client credential is provided)")
+	addClientAuthFlags(flags, oidcConf)
+	flags.StringVar(&oidcConf.PrivateKeyFile, "private-key", "", "file to read private key from (eg. for private_key_jwt)")
+
+	var flowConf oidc.CIBAFlowConfig
+	flags.StringVar(&flowConf.Scopes, "scopes", "openid", "set scopes as a space separated list")
+	var resources StringsFlag
+	flags.Var(&resources, "resource", "URI of the target resource (RFC 8707), argument can be given multiple times")
+	flags.StringVar(&flowConf.LoginHint, "login-hint", "", "identify the user with a login hint, eg. an email address or phone number")
+	flags.StringVar(&flowConf.IDTokenHint, "id-token-hint", "", "identify the user with a previously issued ID token, '-' reads it from stdin, '@file' from a file")
+	flags.StringVar(&flowConf.LoginHintToken, "login-hint-token", "", "identify the user with a login hint token, '-' reads it from stdin, '@file' from a file")
+	flags.StringVar(&flowConf.BindingMessage, "binding-message", "", "message to show on both the consumption and authentication devices")
+	flags.IntVar(&flowConf.RequestedExpiry, "requested-expiry", 0, "requested lifetime of the authentication request in seconds")
+	flags.StringVar(&flowConf.Mode, "mode", oidc.CIBAModePoll, "token delivery mode, poll or ping")
+	flags.StringVar(&flowConf.NotificationEndpoint, "notification-url", "", "client notification endpoint for ping mode, eg. http://localhost:9556/ciba")
+	flags.StringVar(&flowConf.NotificationListen, "notification-listen", "", "local address for the notification endpoint, defaults to the host of notification-url")
+	flags.StringVar(&flowConf.NotificationToken, "notification-token", "", "client notification token for ping mode, generated if omitted")
+	var customArgs CustomArgsFlag
+	flags.Var(&customArgs, "custom", "custom authentication request parameters, argument can be given multiple times")
+
+	runner = &oidc.CIBAFlow{
+		Config:     oidcConf,
+		FlowConfig: &flowConf,
+	}
+
+	err = flags.Parse(args)
+	if err != nil {
+		return nil, buf.String(), err
+	}
+	flowConf.Resources = resources
+
+	// populate custom args
+	if len(customArgs) > 0 {
+		if flowConf.CustomArgs == nil {
+			flowConf.CustomArgs = &httpclient.CustomArgs{}
+		}
+		for _, arg := range customArgs {
+			err := flowConf.CustomArgs.Set(arg)
+			if err != nil {
+				return nil, buf.String(), err
+			}
+		}
+	}
+
+	hints := 0
+	for _, hint := range []string{flowConf.LoginHint, flowConf.IDTokenHint, flowConf.LoginHintToken} {
+		if hint != "" {
+			hints++
+		}
+	}
+
+	var invalidArgsChecks = []struct {
+		condition bool
+		message   string
+	}{
+		{
+			oidcConf.IssuerURL == "",
+			"issuer is required",
+		},
+		{
+			oidcConf.ClientID == "",
+			"client-id is required",
+		},
+		{
+			!hasClientCredentials(oidcConf),
+			"client-secret, private-key, client-cert, client-assertion-file or client-assertion-cmd is required",
+		},
+		{
+			hints != 1,
+			"exactly one of login-hint, id-token-hint and login-hint-token is required",
+		},
+		{
+			flowConf.RequestedExpiry < 0,
+			"requested-expiry must not be negative",
+		},
+		{
+			flowConf.Mode != oidc.CIBAModePoll && flowConf.Mode != oidc.CIBAModePing,
+			"mode must be poll or ping",
+		},
+		{
+			flowConf.Mode == oidc.CIBAModePing && flowConf.NotificationEndpoint == "",
+			"notification-url is required in ping mode",
+		},
+	}
+
+	for _, check := range invalidArgsChecks {
+		if check.condition {
+			return nil, check.message, flag.ErrHelp
+		}
+	}
+	if message := checkClientAuthArgs(oidcConf); message != "" {
+		return nil, message, flag.ErrHelp
+	}
+
+	// Read token hints from stdin or a file
+	if flowConf.IDTokenHint, err = readValueArg(flowConf.IDTokenHint); err != nil {
+		return nil, buf.String(), err
+	}
+	if flowConf.LoginHintToken, err = readValueArg(flowConf.LoginHintToken); err != nil {
+		return nil, buf.String(), err
+	}
+
+	return runner, buf.String(), nil
+}
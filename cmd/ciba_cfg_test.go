@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jentz/oidc-cli/httpclient"
+	"github.com/jentz/oidc-cli/oidc"
+)
+
+func TestParseCIBAFlagsResult(t *testing.T) {
+	var tests = []struct {
+		name     string
+		args     []string
+		oidcConf oidc.Config
+		flowConf oidc.CIBAFlowConfig
+	}{
+		{
+			"poll mode",
+			[]string{
+				"--issuer", "https://example.com",
+				"--backchannel-authentication-url", "https://example.com/bc-authorize",
+				"--token-url", "https://example.com/token",
+				"--client-id", "client-id",
+				"--client-secret", "client-secret",
+				"--login-hint", "alice@example.com",
+				"--binding-message", "W4SCT",
+				"--requested-expiry", "120",
+				"--custom", "acr_values=urn:mfa",
+			},
+			oidc.Config{
+				IssuerURL:                         "https://example.com",
+				BackchannelAuthenticationEndpoint: "https://example.com/bc-authorize",
+				TokenEndpoint:                     "https://example.com/token",
+				ClientID:                          "client-id",
+				ClientSecret:                      "client-secret",
+			},
+			oidc.CIBAFlowConfig{
+				Scopes:          "openid",
+				LoginHint:       "alice@example.com",
+				BindingMessage:  "W4SCT",
+				RequestedExpiry: 120,
+				Mode:            oidc.CIBAModePoll,
+				CustomArgs: &httpclient.CustomArgs{
+					"acr_values": "urn:mfa",
+				},
+			},
+		},
+		{
+			"ping mode",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--private-key", "private.pem",
+				"--id-token-hint", "id-token",
+				"--scopes", "openid profile",
+				"--mode", "ping",
+				"--notification-url", "https://client.example.com/ciba",
+				"--notification-listen", "localhost:9556",
+				"--notification-token", "notification-token",
+			},
+			oidc.Config{
+				IssuerURL:      "https://example.com",
+				ClientID:       "client-id",
+				PrivateKeyFile: "private.pem",
+			},
+			oidc.CIBAFlowConfig{
+				Scopes:               "openid profile",
+				IDTokenHint:          "id-token",
+				Mode:                 oidc.CIBAModePing,
+				NotificationEndpoint: "https://client.example.com/ciba",
+				NotificationListen:   "localhost:9556",
+				NotificationToken:    "notification-token",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner, output, err := parseCIBAFlags("ciba", tt.args, &oidc.Config{})
+			if err != nil {
+				t.Errorf("err got %v, want nil", err)
+			}
+			if output != "" {
+				t.Errorf("output got %q, want empty", output)
+			}
+			f, ok := runner.(*oidc.CIBAFlow)
+			if !ok {
+				t.Fatalf("unexpected runner type: %T", runner)
+			}
+			if !reflect.DeepEqual(*f.Config, tt.oidcConf) {
+				t.Errorf("Config got %+v, want %+v", *f.Config, tt.oidcConf)
+			}
+			if !reflect.DeepEqual(*f.FlowConfig, tt.flowConf) {
+				t.Errorf("FlowConfig got %+v, want %+v", *f.FlowConfig, tt.flowConf)
+			}
+		})
+	}
+}
+
+func TestParseCIBAFlagsError(t *testing.T) {
+	var tests = []struct {
+		name string
+		args []string
+	}{
+		{
+			"missing client credentials",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--login-hint", "alice",
+			},
+		},
+		{
+			"missing hint",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--client-secret", "client-secret",
+			},
+		},
+		{
+			"multiple hints",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--client-secret", "client-secret",
+				"--login-hint", "alice",
+				"--id-token-hint", "id-token",
+			},
+		},
+		{
+			"unsupported mode",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--client-secret", "client-secret",
+				"--login-hint", "alice",
+				"--mode", "push",
+			},
+		},
+		{
+			"ping mode without notification url",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--client-secret", "client-secret",
+				"--login-hint", "alice",
+				"--mode", "ping",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := parseCIBAFlags("ciba", tt.args, &oidc.Config{})
+			if err == nil {
+				t.Errorf("err got nil, want error")
+			}
+		})
+	}
+}
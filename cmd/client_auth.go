@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"flag"
+
+	"github.com/jentz/oidc-cli/httpclient"
+	"github.com/jentz/oidc-cli/oidc"
+)
+
+// addClientAuthFlags registers the flags that configure how the client authenticates to the
+// authorization server. The private key for private_key_jwt is read from --private-key.
+func addClientAuthFlags(flags *flag.FlagSet, oidcConf *oidc.Config) {
+	flags.Var(&oidcConf.AuthMethod, "auth-method", "auth method to use (client_secret_basic, client_secret_post, client_secret_jwt, private_key_jwt, tls_client_auth, self_signed_tls_client_auth or none)")
+	flags.StringVar(&oidcConf.KeyID, "key-id", "", "key ID to set as kid in client assertions")
+	flags.StringVar(&oidcConf.ClientAssertionAlg, "client-assertion-alg", "", "signing algorithm for client assertions (default derived from the private key, HS256 for client_secret_jwt)")
+	flags.StringVar(&oidcConf.ClientAssertionAudience, "client-assertion-aud", "", "audience of client assertions (default is the endpoint called)")
+	flags.StringVar(&oidcConf.ClientAssertionFile, "client-assertion-file", "", "file to read a federated client assertion from for every request (eg. a projected service account token)")
+	flags.StringVar(&oidcConf.ClientAssertionCmd, "client-assertion-cmd", "", "command that prints a federated client assertion, run for every request")
+	addClientCertificateFlags(flags, oidcConf)
+}
+
+// checkClientAuthArgs returns the usage message for conflicting client auth flags, or an empty
+// string. Federated client assertions are only sent with private_key_jwt.
+func checkClientAuthArgs(oidcConf *oidc.Config) string {
+	hasAssertionSource := oidcConf.ClientAssertionFile != "" || oidcConf.ClientAssertionCmd != ""
+	switch {
+	case oidcConf.ClientAssertionFile != "" && oidcConf.ClientAssertionCmd != "":
+		return "only one of client-assertion-file and client-assertion-cmd can be given"
+	case hasAssertionSource && oidcConf.AuthMethod != "" && oidcConf.AuthMethod != httpclient.AuthMethodPrivateKeyJWT:
+		return "client-assertion-file and client-assertion-cmd require auth-method private_key_jwt"
+	}
+	return ""
+}
+
+// hasClientCredentials reports whether any client credential has been given. A DPoP key is
+// only used for client authentication if private_key_jwt is requested explicitly.
+func hasClientCredentials(oidcConf *oidc.Config) bool {
+	return oidcConf.ClientSecret != "" ||
+		(oidcConf.PrivateKeyFile != "" && (!oidcConf.PrivateKeyForDPoP || oidcConf.AuthMethod == httpclient.AuthMethodPrivateKeyJWT)) ||
+		oidcConf.ClientCertificateFile != "" ||
+		oidcConf.ClientAssertionFile != "" ||
+		oidcConf.ClientAssertionCmd != ""
+}
+
+// addClientCertificateFlags registers the flags for the client certificate presented with mutual TLS
+func addClientCertificateFlags(flags *flag.FlagSet, oidcConf *oidc.Config) {
+	flags.StringVar(&oidcConf.ClientCertificateFile, "client-cert", "", "file to read the PEM client certificate for mutual TLS from")
+	flags.StringVar(&oidcConf.ClientKeyFile, "client-key", "", "file to read the PEM client certificate key from (default is the client-cert file)")
+}
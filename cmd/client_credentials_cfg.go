@@ -16,11 +16,15 @@ func parseClientCredentialsFlags(name string, args []string, oidcConf *oidc.Conf
 	flags.StringVar(&oidcConf.DiscoveryEndpoint, "discovery-url", oidcConf.DiscoveryEndpoint, "override discovery url")
 	flags.StringVar(&oidcConf.TokenEndpoint, "token-url", "", "override token url")
 	flags.StringVar(&oidcConf.ClientID, "client-id", oidcConf.ClientID, "set client ID (required)")
-	flags.StringVar(&oidcConf.ClientSecret, "client-secret", oidcConf.ClientSecret, "set client secret (required)")
-	flags.Var(&oidcConf.AuthMethod, "auth-method", "auth method to use (client_secret_basic or client_secret_post)")
+	flags.StringVar(&oidcConf.ClientSecret, "client-secret", oidcConf.ClientSecret, "set client secret (required unless another client credential is provided)")
+	addClientAuthFlags(flags, oidcConf)
+	flags.StringVar(&oidcConf.PrivateKeyFile, "private-key", "", "file to read private key from (eg. for private_key_jwt)")
 
 	var flowConf oidc.ClientCredentialsFlowConfig
 	flags.StringVar(&flowConf.Scopes, "scopes", "", "set scopes as a space separated list")
+	var resources StringsFlag
+	flags.Var(&resources, "resource", "URI of the target resource (RFC 8707), argument can be given multiple times")
+	flags.StringVar(&flowConf.AuthorizationDetails, "authorization-details", "", "set authorization_details as a JSON array (RAR), '-' reads it from stdin, '@file' from a file")
 
 	runner = &oidc.ClientCredentialsFlow{
 		Config:     oidcConf,
@@ -31,6 +35,7 @@ func parseClientCredentialsFlags(name string, args []string, oidcConf *oidc.Conf
 	if err != nil {
 		return nil, buf.String(), err
 	}
+	flowConf.Resources = resources
 
 	var invalidArgsChecks = []struct {
 		condition bool
@@ -45,8 +50,8 @@ func parseClientCredentialsFlags(name string, args []string, oidcConf *oidc.Conf
 			"client-id is required",
 		},
 		{
-			oidcConf.ClientSecret == "",
-			"client-secret is required",
+			!hasClientCredentials(oidcConf),
+			"client-secret, private-key, client-cert, client-assertion-file or client-assertion-cmd is required",
 		},
 	}
 
@@ -55,6 +60,13 @@ func parseClientCredentialsFlags(name string, args []string, oidcConf *oidc.Conf
 			return nil, check.message, flag.ErrHelp
 		}
 	}
+	if message := checkClientAuthArgs(oidcConf); message != "" {
+		return nil, message, flag.ErrHelp
+	}
+
+	if flowConf.AuthorizationDetails, err = parseAuthorizationDetailsArg(flowConf.AuthorizationDetails); err != nil {
+		return nil, err.Error(), flag.ErrHelp
+	}
 
 	return runner, buf.String(), nil
 }
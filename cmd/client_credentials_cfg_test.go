@@ -73,6 +73,59 @@ func TestParseClientCredentialsFlagsResult(t *testing.T) {
 				Scopes: "expected",
 			},
 		},
+		{
+			"private key jwt",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--auth-method", "private_key_jwt",
+				"--private-key", "private.pem",
+				"--key-id", "key-1",
+				"--client-assertion-alg", "PS256",
+				"--client-assertion-aud", "https://example.com",
+			},
+			oidc.Config{
+				IssuerURL:               "https://example.com",
+				ClientID:                "client-id",
+				AuthMethod:              "private_key_jwt",
+				PrivateKeyFile:          "private.pem",
+				KeyID:                   "key-1",
+				ClientAssertionAlg:      "PS256",
+				ClientAssertionAudience: "https://example.com",
+			},
+			oidc.ClientCredentialsFlowConfig{},
+		},
+		{
+			"authorization details",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--client-secret", "client-secret",
+				"--authorization-details", `[ {"type": "payment_initiation", "actions": ["initiate"]} ]`,
+			},
+			oidc.Config{
+				IssuerURL:    "https://example.com",
+				ClientID:     "client-id",
+				ClientSecret: "client-secret",
+			},
+			oidc.ClientCredentialsFlowConfig{
+				AuthorizationDetails: `[{"type":"payment_initiation","actions":["initiate"]}]`,
+			},
+		},
+		{
+			"client assertion file",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--client-assertion-file", "/var/run/secrets/tokens/token",
+			},
+			oidc.Config{
+				IssuerURL:           "https://example.com",
+				ClientID:            "client-id",
+				ClientAssertionFile: "/var/run/secrets/tokens/token",
+			},
+			oidc.ClientCredentialsFlowConfig{},
+		},
 	}
 
 	for _, tt := range tests {
@@ -118,6 +171,42 @@ func TestParseClientCredentialsFlagsError(t *testing.T) {
 				"--client-id", "client-id",
 			},
 		},
+		{
+			"client-assertion-file and client-assertion-cmd",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--client-assertion-file", "token",
+				"--client-assertion-cmd", "cat token",
+			},
+		},
+		{
+			"client-assertion-file with another auth method",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--client-assertion-file", "token",
+				"--auth-method", "client_secret_basic",
+			},
+		},
+		{
+			"authorization details without type",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--client-secret", "client-secret",
+				"--authorization-details", `[{"actions":["initiate"]}]`,
+			},
+		},
+		{
+			"authorization details from missing file",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--client-secret", "client-secret",
+				"--authorization-details", "@does-not-exist.json",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"flag"
+
+	"github.com/jentz/oidc-cli/oidc"
+)
+
+// addClientManagementFlags registers the flags that identify the client to manage (RFC 7592)
+func addClientManagementFlags(flags *flag.FlagSet, oidcConf *oidc.Config, flowConf *oidc.ClientManagementFlowConfig) {
+	flags.StringVar(&oidcConf.IssuerURL, "issuer", oidcConf.IssuerURL, "set issuer url (required)")
+	flags.StringVar(&oidcConf.DiscoveryEndpoint, "discovery-url", oidcConf.DiscoveryEndpoint, "override discovery url")
+	flags.StringVar(&oidcConf.ClientID, "client-id", oidcConf.ClientID, "set client ID to find the stored registration by (required unless registration-client-uri and registration-access-token are given)")
+	flags.StringVar(&flowConf.RegistrationClientURI, "registration-client-uri", "", "override the client configuration endpoint of the stored registration")
+	flags.StringVar(&flowConf.RegistrationAccessToken, "registration-access-token", "", "override the registration access token of the stored registration, '-' reads it from stdin, '@file' from a file")
+	flags.StringVar(&flowConf.RegistrationDir, "registration-dir", defaultRegistrationDir(), "directory registrations are stored in, set to empty to not use stored registrations")
+}
+
+// checkClientManagementArgs validates the flags registered by addClientManagementFlags,
+// returning the usage message if they are invalid or an empty string
+func checkClientManagementArgs(oidcConf *oidc.Config, flowConf *oidc.ClientManagementFlowConfig) string {
+	var invalidArgsChecks = []struct {
+		condition bool
+		message   string
+	}{
+		{
+			oidcConf.IssuerURL == "",
+			"issuer is required",
+		},
+		{
+			oidcConf.ClientID == "" && (flowConf.RegistrationClientURI == "" || flowConf.RegistrationAccessToken == ""),
+			"client-id is required unless registration-client-uri and registration-access-token are given",
+		},
+	}
+
+	for _, check := range invalidArgsChecks {
+		if check.condition {
+			return check.message
+		}
+	}
+	return ""
+}
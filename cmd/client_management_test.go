@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"flag"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/jentz/oidc-cli/oidc"
+)
+
+func TestClientManagementFlags(t *testing.T) {
+	var tests = []struct {
+		name     string
+		args     []string
+		oidcConf oidc.Config
+		flowConf oidc.ClientManagementFlowConfig
+	}{
+		{
+			"stored registration",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--registration-dir", "/tmp/clients",
+			},
+			oidc.Config{
+				IssuerURL: "https://example.com",
+				ClientID:  "client-id",
+			},
+			oidc.ClientManagementFlowConfig{
+				RegistrationDir: "/tmp/clients",
+			},
+		},
+		{
+			"explicit client configuration endpoint",
+			[]string{
+				"--issuer", "https://example.com",
+				"--discovery-url", "https://example.com/.well-known/openid-configuration",
+				"--registration-client-uri", "https://example.com/register/client-id",
+				"--registration-access-token", "rat",
+			},
+			oidc.Config{
+				IssuerURL:         "https://example.com",
+				DiscoveryEndpoint: "https://example.com/.well-known/openid-configuration",
+			},
+			oidc.ClientManagementFlowConfig{
+				RegistrationClientURI:   "https://example.com/register/client-id",
+				RegistrationAccessToken: "rat",
+				RegistrationDir:         defaultRegistrationDir(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var oidcConf oidc.Config
+			var flowConf oidc.ClientManagementFlowConfig
+			flags := flag.NewFlagSet("client_management", flag.ContinueOnError)
+			flags.SetOutput(io.Discard)
+			addClientManagementFlags(flags, &oidcConf, &flowConf)
+			if err := flags.Parse(tt.args); err != nil {
+				t.Fatalf("err got %v, want nil", err)
+			}
+			if message := checkClientManagementArgs(&oidcConf, &flowConf); message != "" {
+				t.Errorf("message got %q, want empty", message)
+			}
+			if !reflect.DeepEqual(oidcConf, tt.oidcConf) {
+				t.Errorf("Config got %+v, want %+v", oidcConf, tt.oidcConf)
+			}
+			if !reflect.DeepEqual(flowConf, tt.flowConf) {
+				t.Errorf("FlowConfig got %+v, want %+v", flowConf, tt.flowConf)
+			}
+		})
+	}
+}
+
+func TestCheckClientManagementArgsError(t *testing.T) {
+	var tests = []struct {
+		name     string
+		oidcConf oidc.Config
+		flowConf oidc.ClientManagementFlowConfig
+	}{
+		{
+			"missing issuer",
+			oidc.Config{ClientID: "client-id"},
+			oidc.ClientManagementFlowConfig{},
+		},
+		{
+			"missing client id",
+			oidc.Config{IssuerURL: "https://example.com"},
+			oidc.ClientManagementFlowConfig{RegistrationClientURI: "https://example.com/register/client-id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if message := checkClientManagementArgs(&tt.oidcConf, &tt.flowConf); message == "" {
+				t.Errorf("message got empty, want usage message")
+			}
+		})
+	}
+}
+
+func TestParseClientManagementFlags(t *testing.T) {
+	var tests = []struct {
+		name  string
+		parse func(name string, args []string, oidcConf *oidc.Config) (CommandRunner, string, error)
+		args  []string
+		want  CommandRunner
+	}{
+		{"client_read", parseClientReadFlags, []string{"--issuer", "https://example.com", "--client-id", "client-id"}, &oidc.ClientReadFlow{}},
+		{"client_update", parseClientUpdateFlags, []string{"--issuer", "https://example.com", "--client-id", "client-id", "--client-name", "renamed"}, &oidc.ClientUpdateFlow{}},
+		{"client_delete", parseClientDeleteFlags, []string{"--issuer", "https://example.com", "--client-id", "client-id"}, &oidc.ClientDeleteFlow{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner, _, err := tt.parse(tt.name, tt.args, &oidc.Config{})
+			if err != nil {
+				t.Fatalf("err got %v, want nil", err)
+			}
+			if reflect.TypeOf(runner) != reflect.TypeOf(tt.want) {
+				t.Errorf("runner type got %T, want %T", runner, tt.want)
+			}
+			if _, _, err := tt.parse(tt.name, tt.args[2:], &oidc.Config{}); err == nil {
+				t.Errorf("err got nil for missing issuer, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"bytes"
+	"flag"
+
+	"github.com/jentz/oidc-cli/oidc"
+)
+
+func parseClientReadFlags(name string, args []string, oidcConf *oidc.Config) (runner CommandRunner, output string, err error) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	var buf bytes.Buffer
+	flags.SetOutput(&buf)
+
+	var flowConf oidc.ClientManagementFlowConfig
+	addClientManagementFlags(flags, oidcConf, &flowConf)
+
+	runner = &oidc.ClientReadFlow{
+		Config:     oidcConf,
+		FlowConfig: &flowConf,
+	}
+
+	err = flags.Parse(args)
+	if err != nil {
+		return nil, buf.String(), err
+	}
+
+	if message := checkClientManagementArgs(oidcConf, &flowConf); message != "" {
+		return nil, message, flag.ErrHelp
+	}
+	if flowConf.RegistrationAccessToken, err = readValueArg(flowConf.RegistrationAccessToken); err != nil {
+		return nil, buf.String(), err
+	}
+
+	return runner, buf.String(), nil
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"bytes"
+	"flag"
+
+	"github.com/jentz/oidc-cli/oidc"
+)
+
+func parseClientUpdateFlags(name string, args []string, oidcConf *oidc.Config) (runner CommandRunner, output string, err error) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	var buf bytes.Buffer
+	flags.SetOutput(&buf)
+
+	var flowConf oidc.ClientManagementFlowConfig
+	addClientManagementFlags(flags, oidcConf, &flowConf)
+	metadataFlags := addClientMetadataFlags(flags)
+
+	runner = &oidc.ClientUpdateFlow{
+		Config:     oidcConf,
+		FlowConfig: &flowConf,
+	}
+
+	err = flags.Parse(args)
+	if err != nil {
+		return nil, buf.String(), err
+	}
+
+	if countStdinArgs(flowConf.RegistrationAccessToken, metadataFlags.metadata, metadataFlags.softwareStatement) > 1 {
+		return nil, "only one of registration-access-token, metadata and software-statement can be read from stdin", flag.ErrHelp
+	}
+	if message := checkClientManagementArgs(oidcConf, &flowConf); message != "" {
+		return nil, message, flag.ErrHelp
+	}
+	if flowConf.RegistrationAccessToken, err = readValueArg(flowConf.RegistrationAccessToken); err != nil {
+		return nil, buf.String(), err
+	}
+	if flowConf.Metadata, err = metadataFlags.parse(); err != nil {
+		return nil, err.Error(), flag.ErrHelp
+	}
+	if len(flowConf.Metadata) == 0 {
+		return nil, "metadata to update is required", flag.ErrHelp
+	}
+
+	return runner, buf.String(), nil
+}
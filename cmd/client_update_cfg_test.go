@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jentz/oidc-cli/oidc"
+)
+
+func TestParseClientUpdateFlagsResult(t *testing.T) {
+	var tests = []struct {
+		name     string
+		args     []string
+		oidcConf oidc.Config
+		flowConf oidc.ClientManagementFlowConfig
+	}{
+		{
+			"stored registration",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--client-name", "renamed",
+				"--metadata", `{"logo_uri":null}`,
+			},
+			oidc.Config{
+				IssuerURL: "https://example.com",
+				ClientID:  "client-id",
+			},
+			oidc.ClientManagementFlowConfig{
+				RegistrationDir: defaultRegistrationDir(),
+				Metadata:        map[string]interface{}{"client_name": "renamed", "logo_uri": nil},
+			},
+		},
+		{
+			"explicit client configuration endpoint",
+			[]string{
+				"--issuer", "https://example.com",
+				"--registration-client-uri", "https://example.com/register/client-id",
+				"--registration-access-token", "rat",
+				"--registration-dir", "",
+				"--redirect-uri", "http://localhost:9555/callback",
+			},
+			oidc.Config{
+				IssuerURL: "https://example.com",
+			},
+			oidc.ClientManagementFlowConfig{
+				RegistrationClientURI:   "https://example.com/register/client-id",
+				RegistrationAccessToken: "rat",
+				Metadata:                map[string]interface{}{"redirect_uris": []string{"http://localhost:9555/callback"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner, output, err := parseClientUpdateFlags("client_update", tt.args, &oidc.Config{})
+			if err != nil {
+				t.Errorf("err got %v, want nil", err)
+			}
+			if output != "" {
+				t.Errorf("output got %q, want empty", output)
+			}
+			f, ok := runner.(*oidc.ClientUpdateFlow)
+			if !ok {
+				t.Fatalf("unexpected runner type: %T", runner)
+			}
+			if !reflect.DeepEqual(*f.Config, tt.oidcConf) {
+				t.Errorf("Config got %+v, want %+v", *f.Config, tt.oidcConf)
+			}
+			if !reflect.DeepEqual(*f.FlowConfig, tt.flowConf) {
+				t.Errorf("FlowConfig got %+v, want %+v", *f.FlowConfig, tt.flowConf)
+			}
+		})
+	}
+}
+
+func TestParseClientUpdateFlagsError(t *testing.T) {
+	var tests = []struct {
+		name string
+		args []string
+	}{
+		{
+			"missing metadata",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+			},
+		},
+		{
+			"missing client id",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-name", "renamed",
+			},
+		},
+		{
+			"two values from stdin",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--registration-access-token", "-",
+				"--metadata", "-",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := parseClientUpdateFlags("client_update", tt.args, &oidc.Config{})
+			if err == nil {
+				t.Errorf("err got nil, want error")
+			}
+		})
+	}
+}
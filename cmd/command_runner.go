@@ -22,13 +22,29 @@ type Command struct {
 	Name      string
 	Help      string
 	Configure func(name string, args []string, cfg *oidc.Config) (config CommandRunner, output string, err error)
+	Offline   bool // skip endpoint discovery for commands that do not talk to the issuer
 }
 
 var commands = []Command{
 	{Name: "authorization_code", Help: "Use the Authorization Code flow to obtain tokens.", Configure: parseAuthorizationCodeFlags},
+	{Name: "ciba", Help: "Use Client-Initiated Backchannel Authentication to obtain tokens.", Configure: parseCIBAFlags},
 	{Name: "client_credentials", Help: "Use the Client Credentials flow to obtain tokens.", Configure: parseClientCredentialsFlags},
+	{Name: "client_delete", Help: "Delete a dynamically registered client (RFC 7592).", Configure: parseClientDeleteFlags},
+	{Name: "client_read", Help: "Read the configuration of a dynamically registered client (RFC 7592).", Configure: parseClientReadFlags},
+	{Name: "client_update", Help: "Update the metadata of a dynamically registered client (RFC 7592).", Configure: parseClientUpdateFlags},
+	{Name: "decode", Help: "Decode a JWT or the tokens in a token response without verifying them.", Configure: parseDecodeFlags, Offline: true},
+	{Name: "device_code", Help: "Use the Device Authorization Grant to obtain tokens.", Configure: parseDeviceCodeFlags},
+	{Name: "end_session", Help: "Log out with RP-Initiated Logout and catch the post-logout redirect.", Configure: parseEndSessionFlags},
 	{Name: "introspect", Help: "Validate a token and retrieve associated claims.", Configure: parseIntrospectFlags},
+	{Name: "jwt_bearer", Help: "Exchange a JWT assertion for tokens (RFC 7523).", Configure: parseJWTBearerFlags},
+	{Name: "logout_listener", Help: "Receive back-channel and front-channel logout notifications and validate them.", Configure: parseLogoutListenerFlags},
+	{Name: "password", Help: "Use the deprecated Resource Owner Password Credentials grant to obtain tokens.", Configure: parsePasswordFlags},
+	{Name: "register", Help: "Register a client dynamically (RFC 7591).", Configure: parseRegisterFlags},
+	{Name: "revoke", Help: "Revoke an access or refresh token.", Configure: parseRevokeFlags},
+	{Name: "token_exchange", Help: "Exchange a token for another token (RFC 8693).", Configure: parseTokenExchangeFlags},
 	{Name: "token_refresh", Help: "Exchange a refresh token for new tokens.", Configure: parseTokenRefreshFlags},
+	{Name: "userinfo", Help: "Fetch the claims for an access token from the UserInfo endpoint.", Configure: parseUserinfoFlags},
+	{Name: "verify", Help: "Verify the signature and claims of a JWT against the issuer's keys.", Configure: parseVerifyFlags},
 	{Name: "version", Help: "Display the current version of oidc-cli."},
 	{Name: "help", Help: "Show help for oidc-cli or a specific command."},
 }
@@ -83,7 +99,7 @@ func RunCommand(name string, args []string, globalConf *oidc.Config, logger *log
 		cancel()
 	}()
 
-	if err := prepareOIDCConfig(ctx, globalConf); err != nil {
+	if err := prepareOIDCConfig(ctx, globalConf, cmd.Offline); err != nil {
 		logger.Errorln("configuration error:", err)
 		return ExitError
 	}
@@ -103,9 +119,11 @@ func RunCommand(name string, args []string, globalConf *oidc.Config, logger *log
 	return ExitOK
 }
 
-func prepareOIDCConfig(ctx context.Context, conf *oidc.Config) error {
-	if err := conf.DiscoverEndpoints(ctx); err != nil {
-		return fmt.Errorf("failed to discover endpoints: %w", err)
+func prepareOIDCConfig(ctx context.Context, conf *oidc.Config, offline bool) error {
+	if !offline {
+		if err := conf.DiscoverEndpoints(ctx); err != nil {
+			return fmt.Errorf("failed to discover endpoints: %w", err)
+		}
 	}
 	if err := conf.ReadKeyFiles(); err != nil {
 		return fmt.Errorf("failed to read key files: %w", err)
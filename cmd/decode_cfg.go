@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+
+	"github.com/jentz/oidc-cli/oidc"
+)
+
+func parseDecodeFlags(name string, args []string, oidcConf *oidc.Config) (runner CommandRunner, output string, err error) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	var buf bytes.Buffer
+	flags.SetOutput(&buf)
+
+	flags.StringVar(&oidcConf.PrivateKeyFile, "private-key", "", "file to read private key from (to decrypt encrypted tokens)")
+
+	var flowConf oidc.DecodeFlowConfig
+	flags.StringVar(&flowConf.Input, "token", "", "JWT or token response JSON to decode, '-' or omit to read from stdin")
+
+	runner = &oidc.DecodeFlow{
+		Config:     oidcConf,
+		FlowConfig: &flowConf,
+	}
+
+	err = flags.Parse(args)
+	if err != nil {
+		return nil, buf.String(), err
+	}
+
+	// Read the whole of stdin if token equals '-' or is omitted while stdin is piped,
+	// token responses span multiple lines
+	if flowConf.Input == "-" || (flowConf.Input == "" && stdinIsPiped()) {
+		input, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, buf.String(), err
+		}
+		flowConf.Input = string(input)
+	}
+
+	var invalidArgsChecks = []struct {
+		condition bool
+		message   string
+	}{
+		{
+			flowConf.Input == "",
+			"token is required",
+		},
+	}
+
+	for _, check := range invalidArgsChecks {
+		if check.condition {
+			return nil, check.message, flag.ErrHelp
+		}
+	}
+
+	return runner, buf.String(), nil
+}
+
+// stdinIsPiped reports whether stdin is a pipe or file rather than a terminal
+func stdinIsPiped() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice == 0
+}
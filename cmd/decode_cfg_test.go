@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jentz/oidc-cli/oidc"
+)
+
+func TestParseDecodeFlagsResult(t *testing.T) {
+	var tests = []struct {
+		name     string
+		args     []string
+		oidcConf oidc.Config
+		flowConf oidc.DecodeFlowConfig
+	}{
+		{
+			"all flags",
+			[]string{
+				"--token", "token",
+				"--private-key", "private.pem",
+			},
+			oidc.Config{
+				PrivateKeyFile: "private.pem",
+			},
+			oidc.DecodeFlowConfig{
+				Input: "token",
+			},
+		},
+		{
+			"no issuer required",
+			[]string{
+				"--token", `{"access_token": "token"}`,
+			},
+			oidc.Config{},
+			oidc.DecodeFlowConfig{
+				Input: `{"access_token": "token"}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner, output, err := parseDecodeFlags("decode", tt.args, &oidc.Config{})
+			if err != nil {
+				t.Errorf("err got %v, want nil", err)
+			}
+			if output != "" {
+				t.Errorf("output got %q, want empty", output)
+			}
+			f, ok := runner.(*oidc.DecodeFlow)
+			if !ok {
+				t.Fatalf("unexpected runner type: %T", runner)
+			}
+			if !reflect.DeepEqual(*f.Config, tt.oidcConf) {
+				t.Errorf("Config got %+v, want %+v", *f.Config, tt.oidcConf)
+			}
+			if !reflect.DeepEqual(*f.FlowConfig, tt.flowConf) {
+				t.Errorf("FlowConfig got %+v, want %+v", *f.FlowConfig, tt.flowConf)
+			}
+		})
+	}
+}
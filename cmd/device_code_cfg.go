@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bytes"
+	"flag"
+
+	"github.com/jentz/oidc-cli/httpclient"
+	"github.com/jentz/oidc-cli/oidc"
+)
+
+func parseDeviceCodeFlags(name string, args []string, oidcConf *oidc.Config) (runner CommandRunner, output string, err error) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	var buf bytes.Buffer
+	flags.SetOutput(&buf)
+
+	flags.StringVar(&oidcConf.IssuerURL, "issuer", oidcConf.IssuerURL, "set issuer url (required)")
+	flags.StringVar(&oidcConf.DiscoveryEndpoint, "discovery-url", oidcConf.DiscoveryEndpoint, "override discovery url")
+	flags.StringVar(&oidcConf.DeviceAuthorizationEndpoint, "device-authorization-url", "", "override device authorization url")
+	flags.StringVar(&oidcConf.TokenEndpoint, "token-url", "", "override token url")
+	flags.StringVar(&oidcConf.ClientID, "client-id", oidcConf.ClientID, "set client ID (required)")
+	flags.StringVar(&oidcConf.ClientSecret, "client-secret", oidcConf.ClientSecret, "set client secret (omit for public clients)")
+	addClientAuthFlags(flags, oidcConf)
+	flags.StringVar(&oidcConf.PrivateKeyFile, "private-key", "", "file to read private key from (eg. for private_key_jwt)")
+
+	var flowConf oidc.DeviceCodeFlowConfig
+	flags.StringVar(&flowConf.Scopes, "scopes", "openid", "set scopes as a space separated list")
+	var resources StringsFlag
+	flags.Var(&resources, "resource", "URI of the target resource (RFC 8707), argument can be given multiple times")
+	var customArgs CustomArgsFlag
+	flags.Var(&customArgs, "custom", "custom device authorization parameters, argument can be given multiple times")
+
+	runner = &oidc.DeviceCodeFlow{
+		Config:     oidcConf,
+		FlowConfig: &flowConf,
+	}
+
+	err = flags.Parse(args)
+	if err != nil {
+		return nil, buf.String(), err
+	}
+	flowConf.Resources = resources
+
+	// populate custom args
+	if len(customArgs) > 0 {
+		if flowConf.CustomArgs == nil {
+			flowConf.CustomArgs = &httpclient.CustomArgs{}
+		}
+		for _, arg := range customArgs {
+			err := flowConf.CustomArgs.Set(arg)
+			if err != nil {
+				return nil, buf.String(), err
+			}
+		}
+	}
+
+	var invalidArgsChecks = []struct {
+		condition bool
+		message   string
+	}{
+		{
+			oidcConf.IssuerURL == "",
+			"issuer is required",
+		},
+		{
+			oidcConf.ClientID == "",
+			"client-id is required",
+		},
+	}
+
+	for _, check := range invalidArgsChecks {
+		if check.condition {
+			return nil, check.message, flag.ErrHelp
+		}
+	}
+	if message := checkClientAuthArgs(oidcConf); message != "" {
+		return nil, message, flag.ErrHelp
+	}
+
+	return runner, buf.String(), nil
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jentz/oidc-cli/httpclient"
+	"github.com/jentz/oidc-cli/oidc"
+)
+
+func TestParseDeviceCodeFlagsResult(t *testing.T) {
+	var tests = []struct {
+		name     string
+		args     []string
+		oidcConf oidc.Config
+		flowConf oidc.DeviceCodeFlowConfig
+	}{
+		{
+			"all flags",
+			[]string{
+				"--issuer", "https://example.com",
+				"--discovery-url", "https://example.com/.well-known/openid-configuration",
+				"--device-authorization-url", "https://example.com/device",
+				"--token-url", "https://example.com/token",
+				"--client-id", "client-id",
+				"--client-secret", "client-secret",
+				"--scopes", "openid profile",
+				"--custom", "audience=api",
+			},
+			oidc.Config{
+				IssuerURL:                   "https://example.com",
+				DiscoveryEndpoint:           "https://example.com/.well-known/openid-configuration",
+				DeviceAuthorizationEndpoint: "https://example.com/device",
+				TokenEndpoint:               "https://example.com/token",
+				ClientID:                    "client-id",
+				ClientSecret:                "client-secret",
+			},
+			oidc.DeviceCodeFlowConfig{
+				Scopes: "openid profile",
+				CustomArgs: &httpclient.CustomArgs{
+					"audience": "api",
+				},
+			},
+		},
+		{
+			"public client with default scopes",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+			},
+			oidc.Config{
+				IssuerURL: "https://example.com",
+				ClientID:  "client-id",
+			},
+			oidc.DeviceCodeFlowConfig{
+				Scopes: "openid",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner, output, err := parseDeviceCodeFlags("device_code", tt.args, &oidc.Config{})
+			if err != nil {
+				t.Errorf("err got %v, want nil", err)
+			}
+			if output != "" {
+				t.Errorf("output got %q, want empty", output)
+			}
+			f, ok := runner.(*oidc.DeviceCodeFlow)
+			if !ok {
+				t.Fatalf("unexpected runner type: %T", runner)
+			}
+			if !reflect.DeepEqual(*f.Config, tt.oidcConf) {
+				t.Errorf("Config got %+v, want %+v", *f.Config, tt.oidcConf)
+			}
+			if !reflect.DeepEqual(*f.FlowConfig, tt.flowConf) {
+				t.Errorf("FlowConfig got %+v, want %+v", *f.FlowConfig, tt.flowConf)
+			}
+		})
+	}
+}
+
+func TestParseDeviceCodeFlagsError(t *testing.T) {
+	var tests = []struct {
+		name string
+		args []string
+	}{
+		{
+			"missing issuer",
+			[]string{
+				"--client-id", "client-id",
+			},
+		},
+		{
+			"missing client-id",
+			[]string{
+				"--issuer", "https://example.com",
+			},
+		},
+		{
+			"invalid custom argument",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--custom", "invalid",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := parseDeviceCodeFlags("device_code", tt.args, &oidc.Config{})
+			if err == nil {
+				t.Errorf("err got nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bytes"
+	"flag"
+
+	"github.com/jentz/oidc-cli/httpclient"
+	"github.com/jentz/oidc-cli/oidc"
+)
+
+func parseEndSessionFlags(name string, args []string, oidcConf *oidc.Config) (runner CommandRunner, output string, err error) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	var buf bytes.Buffer
+	flags.SetOutput(&buf)
+
+	flags.StringVar(&oidcConf.IssuerURL, "issuer", oidcConf.IssuerURL, "set issuer url (required)")
+	flags.StringVar(&oidcConf.DiscoveryEndpoint, "discovery-url", oidcConf.DiscoveryEndpoint, "override discovery url")
+	flags.StringVar(&oidcConf.EndSessionEndpoint, "end-session-url", "", "override end session url")
+	flags.StringVar(&oidcConf.ClientID, "client-id", oidcConf.ClientID, "set client ID (required unless id-token-hint is given)")
+
+	var flowConf oidc.EndSessionFlowConfig
+	flags.StringVar(&flowConf.IDTokenHint, "id-token-hint", "", "ID token of the session to end, '-' reads it from stdin, '@file' from a file")
+	flags.StringVar(&flowConf.LogoutHint, "logout-hint", "", "set logout_hint parameter, eg. the user's login name")
+	flags.StringVar(&flowConf.CallbackURI, "callback-uri", "http://localhost:9555/logout",
+		"set callback uri to catch the post-logout redirect, this will also be used as the post_logout_redirect_uri unless overridden by -post-logout-redirect-uri, set it to an empty string to not request a redirect")
+	flags.StringVar(&flowConf.PostLogoutRedirectURI, "post-logout-redirect-uri", "", "set the post_logout_redirect_uri parameter")
+	flags.StringVar(&flowConf.State, "state", "", "set state parameter (default: random value, always verified on the post-logout redirect)")
+	flags.StringVar(&flowConf.UILocales, "ui-locales", "", "set ui_locales parameter")
+	var customArgs CustomArgsFlag
+	flags.Var(&customArgs, "custom", "custom logout parameters, argument can be given multiple times")
+
+	runner = &oidc.EndSessionFlow{
+		Config:     oidcConf,
+		FlowConfig: &flowConf,
+	}
+
+	err = flags.Parse(args)
+	if err != nil {
+		return nil, buf.String(), err
+	}
+
+	// populate custom args
+	if len(customArgs) > 0 {
+		if flowConf.CustomArgs == nil {
+			flowConf.CustomArgs = &httpclient.CustomArgs{}
+		}
+		for _, arg := range customArgs {
+			err := flowConf.CustomArgs.Set(arg)
+			if err != nil {
+				return nil, buf.String(), err
+			}
+		}
+	}
+
+	var invalidArgsChecks = []struct {
+		condition bool
+		message   string
+	}{
+		{
+			oidcConf.IssuerURL == "",
+			"issuer is required",
+		},
+		{
+			oidcConf.ClientID == "" && flowConf.IDTokenHint == "",
+			"client-id or id-token-hint is required",
+		},
+		{
+			flowConf.PostLogoutRedirectURI != "" && flowConf.CallbackURI == "",
+			"callback-uri is required to catch the post-logout redirect",
+		},
+	}
+
+	for _, check := range invalidArgsChecks {
+		if check.condition {
+			return nil, check.message, flag.ErrHelp
+		}
+	}
+
+	// Read the ID token hint from stdin or a file
+	if flowConf.IDTokenHint, err = readValueArg(flowConf.IDTokenHint); err != nil {
+		return nil, buf.String(), err
+	}
+
+	return runner, buf.String(), nil
+}
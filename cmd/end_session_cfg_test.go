@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jentz/oidc-cli/httpclient"
+	"github.com/jentz/oidc-cli/oidc"
+)
+
+func TestParseEndSessionFlagsResult(t *testing.T) {
+	idTokenFile := filepath.Join(t.TempDir(), "id_token.jwt")
+	if err := os.WriteFile(idTokenFile, []byte("id-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		name     string
+		args     []string
+		oidcConf oidc.Config
+		flowConf oidc.EndSessionFlowConfig
+	}{
+		{
+			"all flags",
+			[]string{
+				"--issuer", "https://example.com",
+				"--discovery-url", "https://example.com/.well-known/openid-configuration",
+				"--end-session-url", "https://example.com/logout",
+				"--client-id", "client-id",
+				"--id-token-hint", "@" + idTokenFile,
+				"--logout-hint", "user@example.com",
+				"--callback-uri", "http://localhost:8080/logout",
+				"--post-logout-redirect-uri", "http://127.0.0.1:8080/logout",
+				"--state", "state-1",
+				"--ui-locales", "de",
+				"--custom", "foo=bar",
+			},
+			oidc.Config{
+				IssuerURL:          "https://example.com",
+				DiscoveryEndpoint:  "https://example.com/.well-known/openid-configuration",
+				EndSessionEndpoint: "https://example.com/logout",
+				ClientID:           "client-id",
+			},
+			oidc.EndSessionFlowConfig{
+				IDTokenHint:           "id-token",
+				LogoutHint:            "user@example.com",
+				CallbackURI:           "http://localhost:8080/logout",
+				PostLogoutRedirectURI: "http://127.0.0.1:8080/logout",
+				State:                 "state-1",
+				UILocales:             "de",
+				CustomArgs:            &httpclient.CustomArgs{"foo": "bar"},
+			},
+		},
+		{
+			"defaults",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+			},
+			oidc.Config{
+				IssuerURL: "https://example.com",
+				ClientID:  "client-id",
+			},
+			oidc.EndSessionFlowConfig{
+				CallbackURI: "http://localhost:9555/logout",
+			},
+		},
+		{
+			"id token hint without redirect",
+			[]string{
+				"--issuer", "https://example.com",
+				"--id-token-hint", "id-token",
+				"--callback-uri", "",
+			},
+			oidc.Config{
+				IssuerURL: "https://example.com",
+			},
+			oidc.EndSessionFlowConfig{
+				IDTokenHint: "id-token",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner, output, err := parseEndSessionFlags("end_session", tt.args, &oidc.Config{})
+			if err != nil {
+				t.Errorf("err got %v, want nil", err)
+			}
+			if output != "" {
+				t.Errorf("output got %q, want empty", output)
+			}
+			f, ok := runner.(*oidc.EndSessionFlow)
+			if !ok {
+				t.Fatalf("unexpected runner type: %T", runner)
+			}
+			if !reflect.DeepEqual(*f.Config, tt.oidcConf) {
+				t.Errorf("Config got %+v, want %+v", *f.Config, tt.oidcConf)
+			}
+			if !reflect.DeepEqual(*f.FlowConfig, tt.flowConf) {
+				t.Errorf("FlowConfig got %+v, want %+v", *f.FlowConfig, tt.flowConf)
+			}
+		})
+	}
+}
+
+func TestParseEndSessionFlagsError(t *testing.T) {
+	var tests = []struct {
+		name string
+		args []string
+	}{
+		{
+			"missing issuer",
+			[]string{
+				"--client-id", "client-id",
+			},
+		},
+		{
+			"missing client id and id token hint",
+			[]string{
+				"--issuer", "https://example.com",
+			},
+		},
+		{
+			"post logout redirect without callback",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--callback-uri", "",
+				"--post-logout-redirect-uri", "https://app.example.com/bye",
+			},
+		},
+		{
+			"missing id token hint file",
+			[]string{
+				"--issuer", "https://example.com",
+				"--id-token-hint", "@/nonexistent/token",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := parseEndSessionFlags("end_session", tt.args, &oidc.Config{})
+			if err == nil {
+				t.Errorf("err got nil, want error")
+			}
+		})
+	}
+}
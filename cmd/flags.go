@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jentz/oidc-cli/oidc"
+)
+
+// StringsFlag collects the values of a flag that can be given multiple times
+type StringsFlag []string
+
+func (s *StringsFlag) String() string {
+	return strings.Join(*s, ", ")
+}
+
+func (s *StringsFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// readValueArg resolves a flag value that is given literally, as '-' to read it from stdin,
+// or as '@file' to read it from a file. Surrounding whitespace is trimmed from read values.
+func readValueArg(value string) (string, error) {
+	var data []byte
+	var err error
+	switch {
+	case value == "-":
+		data, err = io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read from stdin: %w", err)
+		}
+	case strings.HasPrefix(value, "@"):
+		data, err = os.ReadFile(strings.TrimPrefix(value, "@"))
+		if err != nil {
+			return "", fmt.Errorf("failed to read file: %w", err)
+		}
+	default:
+		return value, nil
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// countStdinArgs returns how many of the flag values are '-', as stdin can only be read once
+func countStdinArgs(values ...string) int {
+	count := 0
+	for _, value := range values {
+		if value == "-" {
+			count++
+		}
+	}
+	return count
+}
+
+// parseAuthorizationDetailsArg reads the authorization details like readValueArg and checks them
+func parseAuthorizationDetailsArg(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	details, err := readValueArg(value)
+	if err != nil {
+		return "", err
+	}
+	return oidc.ParseAuthorizationDetails(details)
+}
@@ -19,8 +19,9 @@ func parseIntrospectFlags(name string, args []string, oidcConf *oidc.Config) (ru
 	flags.StringVar(&oidcConf.DiscoveryEndpoint, "discovery-url", oidcConf.DiscoveryEndpoint, "override discovery url")
 	flags.StringVar(&oidcConf.IntrospectionEndpoint, "introspection-url", "", "override introspection url")
 	flags.StringVar(&oidcConf.ClientID, "client-id", oidcConf.ClientID, "set client ID (required)")
-	flags.StringVar(&oidcConf.ClientSecret, "client-secret", oidcConf.ClientSecret, "set client secret (required unless bearer token is provided)")
-	flags.Var(&oidcConf.AuthMethod, "auth-method", "auth method to use (client_secret_basic or client_secret_post)")
+	flags.StringVar(&oidcConf.ClientSecret, "client-secret", oidcConf.ClientSecret, "set client secret (required unless another client credential or a bearer token is provided)")
+	addClientAuthFlags(flags, oidcConf)
+	flags.StringVar(&oidcConf.PrivateKeyFile, "private-key", "", "file to read private key from (eg. for private_key_jwt)")
 
 	var flowConf oidc.IntrospectFlowConfig
 	flags.StringVar(&flowConf.BearerToken, "bearer-token", "", "bearer token for authorization (required unless client secret is provided)")
@@ -73,8 +74,8 @@ func parseIntrospectFlags(name string, args []string, oidcConf *oidc.Config) (ru
 			"client-id is required",
 		},
 		{
-			oidcConf.ClientSecret == "" && flowConf.BearerToken == "",
-			"client-secret or bearer-token is required",
+			!hasClientCredentials(oidcConf) && flowConf.BearerToken == "",
+			"client-secret, private-key, client-cert, client-assertion-file, client-assertion-cmd or bearer-token is required",
 		},
 		{
 			flowConf.Token == "",
@@ -87,6 +88,9 @@ func parseIntrospectFlags(name string, args []string, oidcConf *oidc.Config) (ru
 			return nil, check.message, flag.ErrHelp
 		}
 	}
+	if message := checkClientAuthArgs(oidcConf); message != "" {
+		return nil, message, flag.ErrHelp
+	}
 
 	return runner, buf.String(), nil
 }
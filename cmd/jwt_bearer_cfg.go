@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/jentz/oidc-cli/oidc"
+)
+
+func parseJWTBearerFlags(name string, args []string, oidcConf *oidc.Config) (runner CommandRunner, output string, err error) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	var buf bytes.Buffer
+	flags.SetOutput(&buf)
+
+	flags.StringVar(&oidcConf.IssuerURL, "issuer", oidcConf.IssuerURL, "set issuer url (required)")
+	flags.StringVar(&oidcConf.DiscoveryEndpoint, "discovery-url", oidcConf.DiscoveryEndpoint, "override discovery url")
+	flags.StringVar(&oidcConf.TokenEndpoint, "token-url", "", "override token url")
+	flags.StringVar(&oidcConf.ClientID, "client-id", oidcConf.ClientID, "set client ID (omit if the client is not authenticated)")
+	flags.StringVar(&oidcConf.ClientSecret, "client-secret", oidcConf.ClientSecret, "set client secret")
+	addClientAuthFlags(flags, oidcConf)
+	flags.StringVar(&oidcConf.PrivateKeyFile, "private-key", "", "file to read private key from to sign the assertion")
+
+	var flowConf oidc.JWTBearerFlowConfig
+	flags.StringVar(&flowConf.Assertion, "assertion", "", "existing assertion to send, '-' to read it from stdin or '@file' to read it from a file")
+	flags.StringVar(&flowConf.Issuer, "assertion-iss", "", "iss claim of the signed assertion (default is the client ID)")
+	flags.StringVar(&flowConf.Subject, "assertion-sub", "", "sub claim of the signed assertion (default is the assertion issuer)")
+	flags.StringVar(&flowConf.Audience, "assertion-aud", "", "aud claim of the signed assertion (default is the token endpoint)")
+	flags.DurationVar(&flowConf.Lifetime, "assertion-lifetime", oidc.DefaultAssertionLifetime, "lifetime of the signed assertion")
+	flags.StringVar(&flowConf.Algorithm, "assertion-alg", "", "signing algorithm of the assertion (default derived from the private key)")
+	var claimArgs StringsFlag
+	flags.Var(&claimArgs, "assertion-claim", "extra claim of the signed assertion as name=value, values are parsed as JSON if possible, argument can be given multiple times")
+	flags.StringVar(&flowConf.Scopes, "scopes", "", "set scopes as a space separated list")
+	var resources StringsFlag
+	flags.Var(&resources, "resource", "URI of the target resource (RFC 8707), argument can be given multiple times")
+
+	runner = &oidc.JWTBearerFlow{
+		Config:     oidcConf,
+		FlowConfig: &flowConf,
+	}
+
+	err = flags.Parse(args)
+	if err != nil {
+		return nil, buf.String(), err
+	}
+	flowConf.Resources = resources
+
+	// populate extra claims
+	if len(claimArgs) > 0 {
+		flowConf.Claims, err = parseClaimArgs(claimArgs)
+		if err != nil {
+			return nil, buf.String(), err
+		}
+	}
+
+	var invalidArgsChecks = []struct {
+		condition bool
+		message   string
+	}{
+		{
+			oidcConf.IssuerURL == "",
+			"issuer is required",
+		},
+		{
+			flowConf.Assertion == "" && oidcConf.PrivateKeyFile == "",
+			"assertion or private-key is required",
+		},
+		{
+			flowConf.Assertion == "" && flowConf.Issuer == "" && oidcConf.ClientID == "",
+			"assertion-iss or client-id is required to sign an assertion",
+		},
+	}
+
+	for _, check := range invalidArgsChecks {
+		if check.condition {
+			return nil, check.message, flag.ErrHelp
+		}
+	}
+	if message := checkClientAuthArgs(oidcConf); message != "" {
+		return nil, message, flag.ErrHelp
+	}
+
+	// Read the assertion from stdin or a file
+	if flowConf.Assertion, err = readValueArg(flowConf.Assertion); err != nil {
+		return nil, buf.String(), err
+	}
+
+	return runner, buf.String(), nil
+}
+
+// parseClaimArgs parses name=value claim arguments. Values that are valid JSON, such as numbers,
+// booleans, arrays and objects, are used as such, any other value is used as a string.
+func parseClaimArgs(args []string) (map[string]interface{}, error) {
+	claims := make(map[string]interface{})
+	for _, arg := range args {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid claim %q, must be in the format name=value", arg)
+		}
+		var parsed interface{}
+		if err := json.Unmarshal([]byte(value), &parsed); err != nil {
+			parsed = value
+		}
+		claims[name] = parsed
+	}
+	return claims, nil
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jentz/oidc-cli/oidc"
+)
+
+func TestParseJWTBearerFlagsResult(t *testing.T) {
+	var tests = []struct {
+		name     string
+		args     []string
+		oidcConf oidc.Config
+		flowConf oidc.JWTBearerFlowConfig
+	}{
+		{
+			"signed assertion",
+			[]string{
+				"--issuer", "https://example.com",
+				"--token-url", "https://example.com/token",
+				"--client-id", "client-id",
+				"--private-key", "private.pem",
+				"--key-id", "key-1",
+				"--assertion-iss", "workload",
+				"--assertion-sub", "system:serviceaccount:default:app",
+				"--assertion-aud", "https://example.com",
+				"--assertion-lifetime", "1m",
+				"--assertion-alg", "ES384",
+				"--assertion-claim", "tenant=acme",
+				"--assertion-claim", `groups=["a","b"]`,
+				"--assertion-claim", "level=2",
+				"--scopes", "read",
+			},
+			oidc.Config{
+				IssuerURL:      "https://example.com",
+				TokenEndpoint:  "https://example.com/token",
+				ClientID:       "client-id",
+				PrivateKeyFile: "private.pem",
+				KeyID:          "key-1",
+			},
+			oidc.JWTBearerFlowConfig{
+				Issuer:    "workload",
+				Subject:   "system:serviceaccount:default:app",
+				Audience:  "https://example.com",
+				Lifetime:  time.Minute,
+				Algorithm: "ES384",
+				Claims: map[string]interface{}{
+					"tenant": "acme",
+					"groups": []interface{}{"a", "b"},
+					"level":  float64(2),
+				},
+				Scopes: "read",
+			},
+		},
+		{
+			"existing assertion",
+			[]string{
+				"--issuer", "https://example.com",
+				"--assertion", "assertion-jwt",
+			},
+			oidc.Config{
+				IssuerURL: "https://example.com",
+			},
+			oidc.JWTBearerFlowConfig{
+				Assertion: "assertion-jwt",
+				Lifetime:  oidc.DefaultAssertionLifetime,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner, output, err := parseJWTBearerFlags("jwt_bearer", tt.args, &oidc.Config{})
+			if err != nil {
+				t.Errorf("err got %v, want nil", err)
+			}
+			if output != "" {
+				t.Errorf("output got %q, want empty", output)
+			}
+			f, ok := runner.(*oidc.JWTBearerFlow)
+			if !ok {
+				t.Fatalf("unexpected runner type: %T", runner)
+			}
+			if !reflect.DeepEqual(*f.Config, tt.oidcConf) {
+				t.Errorf("Config got %+v, want %+v", *f.Config, tt.oidcConf)
+			}
+			if !reflect.DeepEqual(*f.FlowConfig, tt.flowConf) {
+				t.Errorf("FlowConfig got %+v, want %+v", *f.FlowConfig, tt.flowConf)
+			}
+		})
+	}
+}
+
+func TestParseJWTBearerFlagsError(t *testing.T) {
+	var tests = []struct {
+		name string
+		args []string
+	}{
+		{
+			"missing assertion and private key",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+			},
+		},
+		{
+			"missing assertion issuer",
+			[]string{
+				"--issuer", "https://example.com",
+				"--private-key", "private.pem",
+			},
+		},
+		{
+			"invalid claim",
+			[]string{
+				"--issuer", "https://example.com",
+				"--assertion", "assertion-jwt",
+				"--assertion-claim", "tenant",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := parseJWTBearerFlags("jwt_bearer", tt.args, &oidc.Config{})
+			if err == nil {
+				t.Errorf("err got nil, want error")
+			}
+		})
+	}
+}
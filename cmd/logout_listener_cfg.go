@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bytes"
+	"flag"
+
+	"github.com/jentz/oidc-cli/oidc"
+)
+
+func parseLogoutListenerFlags(name string, args []string, oidcConf *oidc.Config) (runner CommandRunner, output string, err error) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	var buf bytes.Buffer
+	flags.SetOutput(&buf)
+
+	flags.StringVar(&oidcConf.IssuerURL, "issuer", oidcConf.IssuerURL, "set issuer url (required)")
+	flags.StringVar(&oidcConf.DiscoveryEndpoint, "discovery-url", oidcConf.DiscoveryEndpoint, "override discovery url")
+	flags.StringVar(&oidcConf.JWKSEndpoint, "jwks-url", "", "override jwks url")
+	flags.StringVar(&oidcConf.ClientID, "client-id", oidcConf.ClientID, "set client ID, the expected audience of logout tokens (required)")
+	flags.StringVar(&oidcConf.ClientSecret, "client-secret", oidcConf.ClientSecret, "set client secret (to verify HMAC signed logout tokens)")
+	flags.StringVar(&oidcConf.PrivateKeyFile, "private-key", "", "file to read private key from (to decrypt encrypted logout tokens)")
+
+	var flowConf oidc.LogoutListenerFlowConfig
+	flags.StringVar(&flowConf.BackChannelURI, "backchannel-url", "http://localhost:9557/backchannel_logout", "back-channel logout URI to receive logout tokens on, set it to an empty string to disable")
+	flags.StringVar(&flowConf.FrontChannelURI, "frontchannel-url", "http://localhost:9557/frontchannel_logout", "front-channel logout URI to receive logout requests on, set it to an empty string to disable")
+	flags.StringVar(&flowConf.ListenAddr, "listen", "", "local address to listen on, defaults to the host of the logout URIs")
+	flags.IntVar(&flowConf.Count, "count", 0, "exit after this many notifications (default: listen until interrupted)")
+	flags.DurationVar(&flowConf.ClockSkew, "clock-skew", oidc.DefaultClockSkew, "allowed clock skew when checking exp and iat")
+
+	runner = &oidc.LogoutListenerFlow{
+		Config:     oidcConf,
+		FlowConfig: &flowConf,
+	}
+
+	err = flags.Parse(args)
+	if err != nil {
+		return nil, buf.String(), err
+	}
+
+	var invalidArgsChecks = []struct {
+		condition bool
+		message   string
+	}{
+		{
+			oidcConf.IssuerURL == "",
+			"issuer is required",
+		},
+		{
+			oidcConf.ClientID == "",
+			"client-id is required",
+		},
+		{
+			flowConf.BackChannelURI == "" && flowConf.FrontChannelURI == "",
+			"backchannel-url or frontchannel-url is required",
+		},
+		{
+			flowConf.Count < 0,
+			"count must not be negative",
+		},
+	}
+
+	for _, check := range invalidArgsChecks {
+		if check.condition {
+			return nil, check.message, flag.ErrHelp
+		}
+	}
+
+	return runner, buf.String(), nil
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jentz/oidc-cli/oidc"
+)
+
+func TestParseLogoutListenerFlagsResult(t *testing.T) {
+	var tests = []struct {
+		name     string
+		args     []string
+		oidcConf oidc.Config
+		flowConf oidc.LogoutListenerFlowConfig
+	}{
+		{
+			"all flags",
+			[]string{
+				"--issuer", "https://example.com",
+				"--discovery-url", "https://example.com/.well-known/openid-configuration",
+				"--jwks-url", "https://example.com/jwks",
+				"--client-id", "client-id",
+				"--client-secret", "client-secret",
+				"--private-key", "key.pem",
+				"--backchannel-url", "https://rp.example.com/backchannel",
+				"--frontchannel-url", "",
+				"--listen", "localhost:8080",
+				"--count", "2",
+				"--clock-skew", "10s",
+			},
+			oidc.Config{
+				IssuerURL:         "https://example.com",
+				DiscoveryEndpoint: "https://example.com/.well-known/openid-configuration",
+				JWKSEndpoint:      "https://example.com/jwks",
+				ClientID:          "client-id",
+				ClientSecret:      "client-secret",
+				PrivateKeyFile:    "key.pem",
+			},
+			oidc.LogoutListenerFlowConfig{
+				BackChannelURI: "https://rp.example.com/backchannel",
+				ListenAddr:     "localhost:8080",
+				Count:          2,
+				ClockSkew:      10 * time.Second,
+			},
+		},
+		{
+			"defaults",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+			},
+			oidc.Config{
+				IssuerURL: "https://example.com",
+				ClientID:  "client-id",
+			},
+			oidc.LogoutListenerFlowConfig{
+				BackChannelURI:  "http://localhost:9557/backchannel_logout",
+				FrontChannelURI: "http://localhost:9557/frontchannel_logout",
+				ClockSkew:       oidc.DefaultClockSkew,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner, output, err := parseLogoutListenerFlags("logout_listener", tt.args, &oidc.Config{})
+			if err != nil {
+				t.Errorf("err got %v, want nil", err)
+			}
+			if output != "" {
+				t.Errorf("output got %q, want empty", output)
+			}
+			f, ok := runner.(*oidc.LogoutListenerFlow)
+			if !ok {
+				t.Fatalf("unexpected runner type: %T", runner)
+			}
+			if !reflect.DeepEqual(*f.Config, tt.oidcConf) {
+				t.Errorf("Config got %+v, want %+v", *f.Config, tt.oidcConf)
+			}
+			if !reflect.DeepEqual(*f.FlowConfig, tt.flowConf) {
+				t.Errorf("FlowConfig got %+v, want %+v", *f.FlowConfig, tt.flowConf)
+			}
+		})
+	}
+}
+
+func TestParseLogoutListenerFlagsError(t *testing.T) {
+	var tests = []struct {
+		name string
+		args []string
+	}{
+		{
+			"missing issuer",
+			[]string{
+				"--client-id", "client-id",
+			},
+		},
+		{
+			"missing client id",
+			[]string{
+				"--issuer", "https://example.com",
+			},
+		},
+		{
+			"no logout uri",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--backchannel-url", "",
+				"--frontchannel-url", "",
+			},
+		},
+		{
+			"negative count",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--count", "-1",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := parseLogoutListenerFlags("logout_listener", tt.args, &oidc.Config{})
+			if err == nil {
+				t.Errorf("err got nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jentz/oidc-cli/httpclient"
+	"github.com/jentz/oidc-cli/oidc"
+)
+
+func parsePasswordFlags(name string, args []string, oidcConf *oidc.Config) (runner CommandRunner, output string, err error) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	var buf bytes.Buffer
+	flags.SetOutput(&buf)
+
+	flags.StringVar(&oidcConf.IssuerURL, "issuer", oidcConf.IssuerURL, "set issuer url (required)")
+	flags.StringVar(&oidcConf.DiscoveryEndpoint, "discovery-url", oidcConf.DiscoveryEndpoint, "override discovery url")
+	flags.StringVar(&oidcConf.TokenEndpoint, "token-url", "", "override token url")
+	flags.StringVar(&oidcConf.ClientID, "client-id", oidcConf.ClientID, "set client ID (required)")
+	flags.StringVar(&oidcConf.ClientSecret, "client-secret", oidcConf.ClientSecret, "set client secret (omit for public clients)")
+	addClientAuthFlags(flags, oidcConf)
+	flags.StringVar(&oidcConf.PrivateKeyFile, "private-key", "", "file to read private key from (eg. for private_key_jwt)")
+
+	var flowConf oidc.PasswordFlowConfig
+	flags.StringVar(&flowConf.Username, "username", "", "resource owner username (required)")
+	var passwordFile string
+	flags.StringVar(&passwordFile, "password-file", "", "file to read the password from, '-' reads it from stdin (prompts without echo if omitted)")
+	flags.StringVar(&flowConf.Scopes, "scopes", "", "set scopes as a space separated list")
+	var resources StringsFlag
+	flags.Var(&resources, "resource", "URI of the target resource (RFC 8707), argument can be given multiple times")
+	var customArgs CustomArgsFlag
+	flags.Var(&customArgs, "custom", "custom token request parameters, argument can be given multiple times")
+
+	runner = &oidc.PasswordFlow{
+		Config:     oidcConf,
+		FlowConfig: &flowConf,
+	}
+
+	err = flags.Parse(args)
+	if err != nil {
+		return nil, buf.String(), err
+	}
+	flowConf.Resources = resources
+
+	// populate custom args
+	if len(customArgs) > 0 {
+		if flowConf.CustomArgs == nil {
+			flowConf.CustomArgs = &httpclient.CustomArgs{}
+		}
+		for _, arg := range customArgs {
+			err := flowConf.CustomArgs.Set(arg)
+			if err != nil {
+				return nil, buf.String(), err
+			}
+		}
+	}
+
+	var invalidArgsChecks = []struct {
+		condition bool
+		message   string
+	}{
+		{
+			oidcConf.IssuerURL == "",
+			"issuer is required",
+		},
+		{
+			oidcConf.ClientID == "",
+			"client-id is required",
+		},
+		{
+			flowConf.Username == "",
+			"username is required",
+		},
+	}
+
+	for _, check := range invalidArgsChecks {
+		if check.condition {
+			return nil, check.message, flag.ErrHelp
+		}
+	}
+	if message := checkClientAuthArgs(oidcConf); message != "" {
+		return nil, message, flag.ErrHelp
+	}
+
+	// The password is never accepted on the command line, where it would end up in
+	// the shell history and the process list
+	if passwordFile != "" {
+		if flowConf.Password, err = readPasswordFile(passwordFile); err != nil {
+			return nil, buf.String(), err
+		}
+	} else {
+		flowConf.PromptPassword = true
+	}
+
+	return runner, buf.String(), nil
+}
+
+// readPasswordFile reads a password from a file, or from stdin if the name is '-'.
+// Only the trailing line break is removed, other whitespace may be part of the password.
+func readPasswordFile(name string) (string, error) {
+	var data []byte
+	var err error
+	if name == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
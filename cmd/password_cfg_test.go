@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jentz/oidc-cli/httpclient"
+	"github.com/jentz/oidc-cli/oidc"
+)
+
+func TestParsePasswordFlagsResult(t *testing.T) {
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(passwordFile, []byte(" pass word \n"), 0600); err != nil {
+		t.Fatalf("failed to write password file: %v", err)
+	}
+
+	var tests = []struct {
+		name     string
+		args     []string
+		oidcConf oidc.Config
+		flowConf oidc.PasswordFlowConfig
+	}{
+		{
+			"confidential client",
+			[]string{
+				"--issuer", "https://example.com",
+				"--token-url", "https://example.com/token",
+				"--client-id", "client-id",
+				"--client-secret", "client-secret",
+				"--auth-method", "client_secret_post",
+				"--username", "alice",
+				"--password-file", passwordFile,
+				"--scopes", "openid profile",
+				"--custom", "acr_values=urn:legacy",
+			},
+			oidc.Config{
+				IssuerURL:     "https://example.com",
+				TokenEndpoint: "https://example.com/token",
+				ClientID:      "client-id",
+				ClientSecret:  "client-secret",
+				AuthMethod:    httpclient.AuthMethodPost,
+			},
+			oidc.PasswordFlowConfig{
+				Username: "alice",
+				Password: " pass word ",
+				Scopes:   "openid profile",
+				CustomArgs: &httpclient.CustomArgs{
+					"acr_values": "urn:legacy",
+				},
+			},
+		},
+		{
+			"public client",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--username", "alice",
+				"--password-file", passwordFile,
+			},
+			oidc.Config{
+				IssuerURL: "https://example.com",
+				ClientID:  "client-id",
+			},
+			oidc.PasswordFlowConfig{
+				Username: "alice",
+				Password: " pass word ",
+			},
+		},
+		{
+			"password prompted when the flow runs",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--username", "alice",
+			},
+			oidc.Config{
+				IssuerURL: "https://example.com",
+				ClientID:  "client-id",
+			},
+			oidc.PasswordFlowConfig{
+				Username:       "alice",
+				PromptPassword: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner, output, err := parsePasswordFlags("password", tt.args, &oidc.Config{})
+			if err != nil {
+				t.Errorf("err got %v, want nil", err)
+			}
+			if output != "" {
+				t.Errorf("output got %q, want empty", output)
+			}
+			f, ok := runner.(*oidc.PasswordFlow)
+			if !ok {
+				t.Fatalf("unexpected runner type: %T", runner)
+			}
+			if !reflect.DeepEqual(*f.Config, tt.oidcConf) {
+				t.Errorf("Config got %+v, want %+v", *f.Config, tt.oidcConf)
+			}
+			if !reflect.DeepEqual(*f.FlowConfig, tt.flowConf) {
+				t.Errorf("FlowConfig got %+v, want %+v", *f.FlowConfig, tt.flowConf)
+			}
+		})
+	}
+}
+
+func TestParsePasswordFlagsError(t *testing.T) {
+	var tests = []struct {
+		name string
+		args []string
+	}{
+		{
+			"missing username",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--password-file", "password",
+			},
+		},
+		{
+			"password on the command line",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--username", "alice",
+				"--password", "secret",
+			},
+		},
+		{
+			"missing password file",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--username", "alice",
+				"--password-file", filepath.Join(t.TempDir(), "missing"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := parsePasswordFlags("password", tt.args, &oidc.Config{})
+			if err == nil {
+				t.Errorf("err got nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jentz/oidc-cli/oidc"
+)
+
+// defaultRegistrationDir returns the directory client registrations are stored in, or an empty
+// string if there is no user config directory
+func defaultRegistrationDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "oidc-cli", "clients")
+}
+
+// clientMetadataFlags holds the flags that set client metadata (RFC 7591 section 2)
+type clientMetadataFlags struct {
+	metadata                string
+	softwareStatement       string
+	clientName              string
+	scopes                  string
+	tokenEndpointAuthMethod string
+	jwksURI                 string
+	redirectURIs            StringsFlag
+	postLogoutRedirectURIs  StringsFlag
+	grantTypes              StringsFlag
+	responseTypes           StringsFlag
+}
+
+func addClientMetadataFlags(flags *flag.FlagSet) *clientMetadataFlags {
+	m := &clientMetadataFlags{}
+	flags.StringVar(&m.metadata, "metadata", "", "client metadata as a JSON object, '-' reads it from stdin, '@file' from a file, the other metadata flags override its fields")
+	flags.StringVar(&m.softwareStatement, "software-statement", "", "software statement JWT, '-' reads it from stdin, '@file' from a file")
+	flags.StringVar(&m.clientName, "client-name", "", "set client_name")
+	flags.StringVar(&m.scopes, "scopes", "", "set scope as a space separated list")
+	flags.StringVar(&m.tokenEndpointAuthMethod, "token-endpoint-auth-method", "", "set token_endpoint_auth_method, eg. client_secret_basic or private_key_jwt")
+	flags.StringVar(&m.jwksURI, "jwks-uri", "", "set jwks_uri")
+	flags.Var(&m.redirectURIs, "redirect-uri", "add to redirect_uris, argument can be given multiple times")
+	flags.Var(&m.postLogoutRedirectURIs, "post-logout-redirect-uri", "add to post_logout_redirect_uris, argument can be given multiple times")
+	flags.Var(&m.grantTypes, "grant-type", "add to grant_types, argument can be given multiple times")
+	flags.Var(&m.responseTypes, "response-type", "add to response_types, argument can be given multiple times")
+	return m
+}
+
+// parse returns the client metadata from the metadata JSON and the other metadata flags
+func (m *clientMetadataFlags) parse() (map[string]interface{}, error) {
+	metadata := make(map[string]interface{})
+	if m.metadata != "" {
+		value, err := readValueArg(m.metadata)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(value), &metadata); err != nil {
+			return nil, fmt.Errorf("metadata must be a JSON object: %w", err)
+		}
+		if metadata == nil {
+			return nil, errors.New("metadata must be a JSON object")
+		}
+	}
+
+	if m.softwareStatement != "" {
+		statement, err := readValueArg(m.softwareStatement)
+		if err != nil {
+			return nil, err
+		}
+		metadata["software_statement"] = statement
+	}
+	for name, value := range map[string]string{
+		"client_name":                m.clientName,
+		"scope":                      m.scopes,
+		"token_endpoint_auth_method": m.tokenEndpointAuthMethod,
+		"jwks_uri":                   m.jwksURI,
+	} {
+		if value != "" {
+			metadata[name] = value
+		}
+	}
+	for name, values := range map[string]StringsFlag{
+		"redirect_uris":             m.redirectURIs,
+		"post_logout_redirect_uris": m.postLogoutRedirectURIs,
+		"grant_types":               m.grantTypes,
+		"response_types":            m.responseTypes,
+	} {
+		if len(values) > 0 {
+			metadata[name] = []string(values)
+		}
+	}
+	return metadata, nil
+}
+
+func parseRegisterFlags(name string, args []string, oidcConf *oidc.Config) (runner CommandRunner, output string, err error) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	var buf bytes.Buffer
+	flags.SetOutput(&buf)
+
+	flags.StringVar(&oidcConf.IssuerURL, "issuer", oidcConf.IssuerURL, "set issuer url (required)")
+	flags.StringVar(&oidcConf.DiscoveryEndpoint, "discovery-url", oidcConf.DiscoveryEndpoint, "override discovery url")
+	flags.StringVar(&oidcConf.RegistrationEndpoint, "registration-url", "", "override registration url")
+
+	var flowConf oidc.RegisterFlowConfig
+	flags.StringVar(&flowConf.InitialAccessToken, "initial-access-token", "", "initial access token authorizing the registration, '-' reads it from stdin, '@file' from a file")
+	flags.StringVar(&flowConf.RegistrationDir, "registration-dir", defaultRegistrationDir(), "directory to store the registration in for client_read, client_update and client_delete, set to empty to not store it")
+	metadataFlags := addClientMetadataFlags(flags)
+
+	runner = &oidc.RegisterFlow{
+		Config:     oidcConf,
+		FlowConfig: &flowConf,
+	}
+
+	err = flags.Parse(args)
+	if err != nil {
+		return nil, buf.String(), err
+	}
+
+	var invalidArgsChecks = []struct {
+		condition bool
+		message   string
+	}{
+		{
+			oidcConf.IssuerURL == "",
+			"issuer is required",
+		},
+		{
+			countStdinArgs(flowConf.InitialAccessToken, metadataFlags.metadata, metadataFlags.softwareStatement) > 1,
+			"only one of initial-access-token, metadata and software-statement can be read from stdin",
+		},
+	}
+
+	for _, check := range invalidArgsChecks {
+		if check.condition {
+			return nil, check.message, flag.ErrHelp
+		}
+	}
+
+	if flowConf.InitialAccessToken, err = readValueArg(flowConf.InitialAccessToken); err != nil {
+		return nil, buf.String(), err
+	}
+	if flowConf.Metadata, err = metadataFlags.parse(); err != nil {
+		return nil, err.Error(), flag.ErrHelp
+	}
+
+	return runner, buf.String(), nil
+}
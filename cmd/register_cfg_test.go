@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jentz/oidc-cli/oidc"
+)
+
+func TestParseRegisterFlagsResult(t *testing.T) {
+	metadataFile := filepath.Join(t.TempDir(), "metadata.json")
+	if err := os.WriteFile(metadataFile, []byte(`{"client_name":"from file","application_type":"native"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		name     string
+		args     []string
+		oidcConf oidc.Config
+		flowConf oidc.RegisterFlowConfig
+	}{
+		{
+			"all flags",
+			[]string{
+				"--issuer", "https://example.com",
+				"--discovery-url", "https://example.com/.well-known/openid-configuration",
+				"--registration-url", "https://example.com/register",
+				"--initial-access-token", "iat",
+				"--registration-dir", "/tmp/clients",
+				"--metadata", "@" + metadataFile,
+				"--software-statement", "header.payload.signature",
+				"--client-name", "test client",
+				"--scopes", "openid profile",
+				"--token-endpoint-auth-method", "private_key_jwt",
+				"--jwks-uri", "https://client.example.com/jwks",
+				"--redirect-uri", "http://localhost:9555/callback",
+				"--redirect-uri", "http://127.0.0.1:9555/callback",
+				"--post-logout-redirect-uri", "http://localhost:9555/logout",
+				"--grant-type", "authorization_code",
+				"--grant-type", "refresh_token",
+				"--response-type", "code",
+			},
+			oidc.Config{
+				IssuerURL:            "https://example.com",
+				DiscoveryEndpoint:    "https://example.com/.well-known/openid-configuration",
+				RegistrationEndpoint: "https://example.com/register",
+			},
+			oidc.RegisterFlowConfig{
+				InitialAccessToken: "iat",
+				RegistrationDir:    "/tmp/clients",
+				Metadata: map[string]interface{}{
+					"application_type":           "native",
+					"client_name":                "test client",
+					"software_statement":         "header.payload.signature",
+					"scope":                      "openid profile",
+					"token_endpoint_auth_method": "private_key_jwt",
+					"jwks_uri":                   "https://client.example.com/jwks",
+					"redirect_uris":              []string{"http://localhost:9555/callback", "http://127.0.0.1:9555/callback"},
+					"post_logout_redirect_uris":  []string{"http://localhost:9555/logout"},
+					"grant_types":                []string{"authorization_code", "refresh_token"},
+					"response_types":             []string{"code"},
+				},
+			},
+		},
+		{
+			"defaults",
+			[]string{
+				"--issuer", "https://example.com",
+			},
+			oidc.Config{
+				IssuerURL: "https://example.com",
+			},
+			oidc.RegisterFlowConfig{
+				RegistrationDir: defaultRegistrationDir(),
+				Metadata:        map[string]interface{}{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner, output, err := parseRegisterFlags("register", tt.args, &oidc.Config{})
+			if err != nil {
+				t.Errorf("err got %v, want nil", err)
+			}
+			if output != "" {
+				t.Errorf("output got %q, want empty", output)
+			}
+			f, ok := runner.(*oidc.RegisterFlow)
+			if !ok {
+				t.Fatalf("unexpected runner type: %T", runner)
+			}
+			if !reflect.DeepEqual(*f.Config, tt.oidcConf) {
+				t.Errorf("Config got %+v, want %+v", *f.Config, tt.oidcConf)
+			}
+			if !reflect.DeepEqual(*f.FlowConfig, tt.flowConf) {
+				t.Errorf("FlowConfig got %+v, want %+v", *f.FlowConfig, tt.flowConf)
+			}
+		})
+	}
+}
+
+func TestParseRegisterFlagsError(t *testing.T) {
+	var tests = []struct {
+		name string
+		args []string
+	}{
+		{
+			"missing issuer",
+			[]string{
+				"--client-name", "test",
+			},
+		},
+		{
+			"metadata not an object",
+			[]string{
+				"--issuer", "https://example.com",
+				"--metadata", `["client_name"]`,
+			},
+		},
+		{
+			"metadata null",
+			[]string{
+				"--issuer", "https://example.com",
+				"--metadata", "null",
+				"--client-name", "test",
+			},
+		},
+		{
+			"two values from stdin",
+			[]string{
+				"--issuer", "https://example.com",
+				"--metadata", "-",
+				"--software-statement", "-",
+			},
+		},
+		{
+			"missing software statement file",
+			[]string{
+				"--issuer", "https://example.com",
+				"--software-statement", "@/nonexistent/statement",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := parseRegisterFlags("register", tt.args, &oidc.Config{})
+			if err == nil {
+				t.Errorf("err got nil, want error")
+			}
+		})
+	}
+}
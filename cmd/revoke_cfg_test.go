@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jentz/oidc-cli/httpclient"
+	"github.com/jentz/oidc-cli/oidc"
+)
+
+func TestParseRevokeFlagsResult(t *testing.T) {
+	var tests = []struct {
+		name     string
+		args     []string
+		oidcConf oidc.Config
+		flowConf oidc.RevokeFlowConfig
+	}{
+		{
+			"all flags",
+			[]string{
+				"--issuer", "https://example.com",
+				"--discovery-url", "https://example.com/.well-known/openid-configuration",
+				"--revocation-url", "https://example.com/revoke",
+				"--client-id", "client-id",
+				"--client-secret", "client-secret",
+				"--auth-method", "client_secret_post",
+				"--token-type", "refresh_token",
+				"--token", "token",
+				"--custom", "foo=bar",
+			},
+			oidc.Config{
+				IssuerURL:          "https://example.com",
+				DiscoveryEndpoint:  "https://example.com/.well-known/openid-configuration",
+				RevocationEndpoint: "https://example.com/revoke",
+				ClientID:           "client-id",
+				ClientSecret:       "client-secret",
+				AuthMethod:         httpclient.AuthMethodPost,
+			},
+			oidc.RevokeFlowConfig{
+				Token:         "token",
+				TokenTypeHint: "refresh_token",
+				CustomArgs:    &httpclient.CustomArgs{"foo": "bar"},
+			},
+		},
+		{
+			"public client without token type hint",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--token", "token",
+			},
+			oidc.Config{
+				IssuerURL: "https://example.com",
+				ClientID:  "client-id",
+			},
+			oidc.RevokeFlowConfig{
+				Token: "token",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner, output, err := parseRevokeFlags("revoke", tt.args, &oidc.Config{})
+			if err != nil {
+				t.Errorf("err got %v, want nil", err)
+			}
+			if output != "" {
+				t.Errorf("output got %q, want empty", output)
+			}
+			f, ok := runner.(*oidc.RevokeFlow)
+			if !ok {
+				t.Fatalf("unexpected runner type: %T", runner)
+			}
+			if !reflect.DeepEqual(*f.Config, tt.oidcConf) {
+				t.Errorf("Config got %+v, want %+v", *f.Config, tt.oidcConf)
+			}
+			if !reflect.DeepEqual(*f.FlowConfig, tt.flowConf) {
+				t.Errorf("FlowConfig got %+v, want %+v", *f.FlowConfig, tt.flowConf)
+			}
+		})
+	}
+}
+
+func TestParseRevokeFlagsError(t *testing.T) {
+	var tests = []struct {
+		name string
+		args []string
+	}{
+		{
+			"missing issuer",
+			[]string{
+				"--client-id", "client-id",
+				"--token", "token",
+			},
+		},
+		{
+			"missing client-id",
+			[]string{
+				"--issuer", "https://example.com",
+				"--token", "token",
+			},
+		},
+		{
+			"missing token",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, output, err := parseRevokeFlags("revoke", tt.args, &oidc.Config{})
+			if err == nil {
+				t.Errorf("err got nil, want error")
+			}
+			if output == "" {
+				t.Errorf("output got empty, want error message")
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"bytes"
+	"flag"
+
+	"github.com/jentz/oidc-cli/oidc"
+)
+
+func parseTokenExchangeFlags(name string, args []string, oidcConf *oidc.Config) (runner CommandRunner, output string, err error) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	var buf bytes.Buffer
+	flags.SetOutput(&buf)
+
+	flags.StringVar(&oidcConf.IssuerURL, "issuer", oidcConf.IssuerURL, "set issuer url (required)")
+	flags.StringVar(&oidcConf.DiscoveryEndpoint, "discovery-url", oidcConf.DiscoveryEndpoint, "override discovery url")
+	flags.StringVar(&oidcConf.TokenEndpoint, "token-url", "", "override token url")
+	flags.StringVar(&oidcConf.ClientID, "client-id", oidcConf.ClientID, "set client ID (required)")
+	flags.StringVar(&oidcConf.ClientSecret, "client-secret", oidcConf.ClientSecret, "set client secret")
+	addClientAuthFlags(flags, oidcConf)
+	flags.StringVar(&oidcConf.PrivateKeyFile, "private-key", "", "file to read private key from (eg. for private_key_jwt or to decrypt the issued token)")
+
+	var flowConf oidc.TokenExchangeFlowConfig
+	flags.StringVar(&flowConf.SubjectToken, "subject-token", "", "token to exchange, '-' to read it from stdin or '@file' to read it from a file (required)")
+	flags.StringVar(&flowConf.SubjectTokenType, "subject-token-type", "access_token", "type of the subject token (access_token, refresh_token, id_token, jwt, saml1, saml2 or a token type URI)")
+	flags.StringVar(&flowConf.ActorToken, "actor-token", "", "token of the acting party for delegation, '-' to read it from stdin or '@file' to read it from a file")
+	flags.StringVar(&flowConf.ActorTokenType, "actor-token-type", "access_token", "type of the actor token")
+	flags.StringVar(&flowConf.RequestedTokenType, "requested-token-type", "", "type of the token to issue")
+	var audiences, resources StringsFlag
+	flags.Var(&audiences, "audience", "logical name of the target service, argument can be given multiple times")
+	flags.Var(&resources, "resource", "URI of the target resource, argument can be given multiple times")
+	flags.StringVar(&flowConf.Scopes, "scopes", "", "set scopes as a space separated list")
+
+	runner = &oidc.TokenExchangeFlow{
+		Config:     oidcConf,
+		FlowConfig: &flowConf,
+	}
+
+	err = flags.Parse(args)
+	if err != nil {
+		return nil, buf.String(), err
+	}
+	flowConf.Audiences = audiences
+	flowConf.Resources = resources
+
+	var invalidArgsChecks = []struct {
+		condition bool
+		message   string
+	}{
+		{
+			oidcConf.IssuerURL == "",
+			"issuer is required",
+		},
+		{
+			oidcConf.ClientID == "",
+			"client-id is required",
+		},
+		{
+			flowConf.SubjectToken == "",
+			"subject-token is required",
+		},
+		{
+			flowConf.SubjectToken == "-" && flowConf.ActorToken == "-",
+			"only one of subject-token and actor-token can be read from stdin",
+		},
+	}
+
+	for _, check := range invalidArgsChecks {
+		if check.condition {
+			return nil, check.message, flag.ErrHelp
+		}
+	}
+	if message := checkClientAuthArgs(oidcConf); message != "" {
+		return nil, message, flag.ErrHelp
+	}
+
+	// Read the tokens from stdin or files
+	if flowConf.SubjectToken, err = readValueArg(flowConf.SubjectToken); err != nil {
+		return nil, buf.String(), err
+	}
+	if flowConf.ActorToken, err = readValueArg(flowConf.ActorToken); err != nil {
+		return nil, buf.String(), err
+	}
+
+	return runner, buf.String(), nil
+}
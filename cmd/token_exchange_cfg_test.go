@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jentz/oidc-cli/oidc"
+)
+
+func TestParseTokenExchangeFlagsResult(t *testing.T) {
+	actorFile := filepath.Join(t.TempDir(), "actor.jwt")
+	if err := os.WriteFile(actorFile, []byte("actor-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		name     string
+		args     []string
+		oidcConf oidc.Config
+		flowConf oidc.TokenExchangeFlowConfig
+	}{
+		{
+			"all flags",
+			[]string{
+				"--issuer", "https://example.com",
+				"--discovery-url", "https://example.com/.well-known/openid-configuration",
+				"--token-url", "https://example.com/token",
+				"--client-id", "client-id",
+				"--client-secret", "client-secret",
+				"--subject-token", "subject-token",
+				"--subject-token-type", "id_token",
+				"--actor-token", "@" + actorFile,
+				"--actor-token-type", "jwt",
+				"--requested-token-type", "urn:ietf:params:oauth:token-type:refresh_token",
+				"--audience", "svc-a",
+				"--audience", "svc-b",
+				"--resource", "https://api.example.com",
+				"--scopes", "read write",
+			},
+			oidc.Config{
+				IssuerURL:         "https://example.com",
+				DiscoveryEndpoint: "https://example.com/.well-known/openid-configuration",
+				TokenEndpoint:     "https://example.com/token",
+				ClientID:          "client-id",
+				ClientSecret:      "client-secret",
+			},
+			oidc.TokenExchangeFlowConfig{
+				SubjectToken:       "subject-token",
+				SubjectTokenType:   "id_token",
+				ActorToken:         "actor-token",
+				ActorTokenType:     "jwt",
+				RequestedTokenType: "urn:ietf:params:oauth:token-type:refresh_token",
+				Audiences:          []string{"svc-a", "svc-b"},
+				Resources:          []string{"https://api.example.com"},
+				Scopes:             "read write",
+			},
+		},
+		{
+			"defaults",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--subject-token", "subject-token",
+			},
+			oidc.Config{
+				IssuerURL: "https://example.com",
+				ClientID:  "client-id",
+			},
+			oidc.TokenExchangeFlowConfig{
+				SubjectToken:     "subject-token",
+				SubjectTokenType: "access_token",
+				ActorTokenType:   "access_token",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner, output, err := parseTokenExchangeFlags("token_exchange", tt.args, &oidc.Config{})
+			if err != nil {
+				t.Errorf("err got %v, want nil", err)
+			}
+			if output != "" {
+				t.Errorf("output got %q, want empty", output)
+			}
+			f, ok := runner.(*oidc.TokenExchangeFlow)
+			if !ok {
+				t.Fatalf("unexpected runner type: %T", runner)
+			}
+			if !reflect.DeepEqual(*f.Config, tt.oidcConf) {
+				t.Errorf("Config got %+v, want %+v", *f.Config, tt.oidcConf)
+			}
+			if !reflect.DeepEqual(*f.FlowConfig, tt.flowConf) {
+				t.Errorf("FlowConfig got %+v, want %+v", *f.FlowConfig, tt.flowConf)
+			}
+		})
+	}
+}
+
+func TestParseTokenExchangeFlagsError(t *testing.T) {
+	var tests = []struct {
+		name string
+		args []string
+	}{
+		{
+			"missing subject token",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+			},
+		},
+		{
+			"both tokens from stdin",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--subject-token", "-",
+				"--actor-token", "-",
+			},
+		},
+		{
+			"missing subject token file",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--subject-token", "@/nonexistent/token",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := parseTokenExchangeFlags("token_exchange", tt.args, &oidc.Config{})
+			if err == nil {
+				t.Errorf("err got nil, want error")
+			}
+		})
+	}
+}
@@ -19,11 +19,15 @@ func parseTokenRefreshFlags(name string, args []string, oidcConf *oidc.Config) (
 	flags.StringVar(&oidcConf.IntrospectionEndpoint, "introspection-url", "", "override introspection url")
 	flags.StringVar(&oidcConf.ClientID, "client-id", oidcConf.ClientID, "set client ID")
 	flags.StringVar(&oidcConf.ClientSecret, "client-secret", oidcConf.ClientSecret, "set client secret")
-	flags.Var(&oidcConf.AuthMethod, "auth-method", "auth method to use (client_secret_basic or client_secret_post)")
+	addClientAuthFlags(flags, oidcConf)
+	flags.StringVar(&oidcConf.PrivateKeyFile, "private-key", "", "file to read private key from (eg. for private_key_jwt)")
 
 	var flowConf oidc.TokenRefreshFlowConfig
 	flags.StringVar(&flowConf.RefreshToken, "refresh-token", "", "refresh token to be used for token refresh")
 	flags.StringVar(&flowConf.Scopes, "scopes", "", "set scopes as a space separated list")
+	var resources StringsFlag
+	flags.Var(&resources, "resource", "URI of the target resource (RFC 8707), argument can be given multiple times")
+	flags.StringVar(&flowConf.AuthorizationDetails, "authorization-details", "", "set authorization_details as a JSON array (RAR), '-' reads it from stdin, '@file' from a file")
 
 	runner = &oidc.TokenRefreshFlow{
 		Config:     oidcConf,
@@ -34,6 +38,11 @@ func parseTokenRefreshFlags(name string, args []string, oidcConf *oidc.Config) (
 	if err != nil {
 		return nil, buf.String(), err
 	}
+	flowConf.Resources = resources
+
+	if countStdinArgs(flowConf.RefreshToken, flowConf.AuthorizationDetails) > 1 {
+		return nil, "only one of refresh-token and authorization-details can be read from stdin", flag.ErrHelp
+	}
 
 	// Read refresh token from stdin if token equals '-'
 	if flowConf.RefreshToken == "-" {
@@ -61,6 +70,13 @@ func parseTokenRefreshFlags(name string, args []string, oidcConf *oidc.Config) (
 			return nil, check.message, flag.ErrHelp
 		}
 	}
+	if message := checkClientAuthArgs(oidcConf); message != "" {
+		return nil, message, flag.ErrHelp
+	}
+
+	if flowConf.AuthorizationDetails, err = parseAuthorizationDetailsArg(flowConf.AuthorizationDetails); err != nil {
+		return nil, err.Error(), flag.ErrHelp
+	}
 
 	return runner, buf.String(), nil
 }
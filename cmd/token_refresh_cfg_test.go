@@ -37,6 +37,26 @@ func TestParseTokenRefreshFlagsResult(t *testing.T) {
 				RefreshToken: "refresh-token",
 			},
 		},
+		{
+			"downscoped to resources",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--client-secret", "client-secret",
+				"--refresh-token", "refresh-token",
+				"--resource", "https://api.example.com",
+				"--resource", "https://payments.example.com",
+			},
+			oidc.Config{
+				IssuerURL:    "https://example.com",
+				ClientID:     "client-id",
+				ClientSecret: "client-secret",
+			},
+			oidc.TokenRefreshFlowConfig{
+				RefreshToken: "refresh-token",
+				Resources:    []string{"https://api.example.com", "https://payments.example.com"},
+			},
+		},
 		{
 			"only issuer, no scopes",
 			[]string{
@@ -101,6 +121,26 @@ func TestParseTokenRefreshFlagsError(t *testing.T) {
 				"--client-secret", "client-secret",
 			},
 		},
+		{
+			"client-assertion-file and client-assertion-cmd",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--refresh-token", "refresh-token",
+				"--client-assertion-file", "token",
+				"--client-assertion-cmd", "cat token",
+			},
+		},
+		{
+			"refresh-token and authorization-details from stdin",
+			[]string{
+				"--issuer", "https://example.com",
+				"--client-id", "client-id",
+				"--client-secret", "client-secret",
+				"--refresh-token", "-",
+				"--authorization-details", "-",
+			},
+		},
 		{
 			"undefined argument provided",
 			[]string{
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"os"
+
+	"github.com/jentz/oidc-cli/oidc"
+)
+
+func parseUserinfoFlags(name string, args []string, oidcConf *oidc.Config) (runner CommandRunner, output string, err error) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	var buf bytes.Buffer
+	flags.SetOutput(&buf)
+
+	flags.StringVar(&oidcConf.IssuerURL, "issuer", oidcConf.IssuerURL, "set issuer url (required)")
+	flags.StringVar(&oidcConf.DiscoveryEndpoint, "discovery-url", oidcConf.DiscoveryEndpoint, "override discovery url")
+	flags.StringVar(&oidcConf.UserinfoEndpoint, "userinfo-url", "", "override userinfo url")
+	flags.StringVar(&oidcConf.JWKSEndpoint, "jwks-url", "", "override jwks url used to verify signed userinfo responses")
+	flags.StringVar(&oidcConf.ClientID, "client-id", oidcConf.ClientID, "set client ID (used to check the audience of signed userinfo responses)")
+	flags.BoolVar(&oidcConf.SkipTLSVerify, "skip-tls-verify", oidcConf.SkipTLSVerify, "skip TLS certificate verification")
+	flags.StringVar(&oidcConf.PrivateKeyFile, "private-key", "", "file to read private key from (eg. for DPoP or to decrypt userinfo responses)")
+	flags.StringVar(&oidcConf.PublicKeyFile, "public-key", "", "file to read public key from (eg. for DPoP)")
+	addClientCertificateFlags(flags, oidcConf)
+
+	var flowConf oidc.UserinfoFlowConfig
+	flags.StringVar(&flowConf.AccessToken, "token", "", "access token or '-' to read token from stdin (required)")
+	flags.BoolVar(&flowConf.DPoP, "dpop", false, "send the access token as a dpop-bound token")
+
+	runner = &oidc.UserinfoFlow{
+		Config:     oidcConf,
+		FlowConfig: &flowConf,
+	}
+
+	err = flags.Parse(args)
+	if err != nil {
+		return nil, buf.String(), err
+	}
+
+	// Read token from stdin if token equals '-'
+	if flowConf.AccessToken == "-" {
+		scanner := bufio.NewScanner(os.Stdin)
+		scanner.Scan()
+		flowConf.AccessToken = scanner.Text()
+	}
+
+	var invalidArgsChecks = []struct {
+		condition bool
+		message   string
+	}{
+		{
+			oidcConf.IssuerURL == "",
+			"issuer is required",
+		},
+		{
+			flowConf.AccessToken == "",
+			"token is required",
+		},
+		{
+			flowConf.DPoP && (oidcConf.PrivateKeyFile == "" || oidcConf.PublicKeyFile == ""),
+			"private-key and public-key are required when using DPoP",
+		},
+	}
+
+	for _, check := range invalidArgsChecks {
+		if check.condition {
+			return nil, check.message, flag.ErrHelp
+		}
+	}
+
+	return runner, buf.String(), nil
+}
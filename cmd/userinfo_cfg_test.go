@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jentz/oidc-cli/oidc"
+)
+
+func TestParseUserinfoFlagsResult(t *testing.T) {
+	var tests = []struct {
+		name     string
+		args     []string
+		oidcConf oidc.Config
+		flowConf oidc.UserinfoFlowConfig
+	}{
+		{
+			"all flags",
+			[]string{
+				"--issuer", "https://example.com",
+				"--discovery-url", "https://example.com/.well-known/openid-configuration",
+				"--userinfo-url", "https://example.com/userinfo",
+				"--jwks-url", "https://example.com/jwks",
+				"--client-id", "client-id",
+				"--skip-tls-verify",
+				"--private-key", "private.pem",
+				"--public-key", "public.pem",
+				"--token", "token",
+				"--dpop",
+			},
+			oidc.Config{
+				IssuerURL:         "https://example.com",
+				DiscoveryEndpoint: "https://example.com/.well-known/openid-configuration",
+				UserinfoEndpoint:  "https://example.com/userinfo",
+				JWKSEndpoint:      "https://example.com/jwks",
+				ClientID:          "client-id",
+				SkipTLSVerify:     true,
+				PrivateKeyFile:    "private.pem",
+				PublicKeyFile:     "public.pem",
+			},
+			oidc.UserinfoFlowConfig{
+				AccessToken: "token",
+				DPoP:        true,
+			},
+		},
+		{
+			"bearer token only",
+			[]string{
+				"--issuer", "https://example.com",
+				"--token", "token",
+			},
+			oidc.Config{
+				IssuerURL: "https://example.com",
+			},
+			oidc.UserinfoFlowConfig{
+				AccessToken: "token",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner, output, err := parseUserinfoFlags("userinfo", tt.args, &oidc.Config{})
+			if err != nil {
+				t.Errorf("err got %v, want nil", err)
+			}
+			if output != "" {
+				t.Errorf("output got %q, want empty", output)
+			}
+			f, ok := runner.(*oidc.UserinfoFlow)
+			if !ok {
+				t.Fatalf("unexpected runner type: %T", runner)
+			}
+			if !reflect.DeepEqual(*f.Config, tt.oidcConf) {
+				t.Errorf("Config got %+v, want %+v", *f.Config, tt.oidcConf)
+			}
+			if !reflect.DeepEqual(*f.FlowConfig, tt.flowConf) {
+				t.Errorf("FlowConfig got %+v, want %+v", *f.FlowConfig, tt.flowConf)
+			}
+		})
+	}
+}
+
+func TestParseUserinfoFlagsError(t *testing.T) {
+	var tests = []struct {
+		name string
+		args []string
+	}{
+		{
+			"missing issuer",
+			[]string{
+				"--token", "token",
+			},
+		},
+		{
+			"missing token",
+			[]string{
+				"--issuer", "https://example.com",
+			},
+		},
+		{
+			"dpop without keys",
+			[]string{
+				"--issuer", "https://example.com",
+				"--token", "token",
+				"--dpop",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, output, err := parseUserinfoFlags("userinfo", tt.args, &oidc.Config{})
+			if err == nil {
+				t.Errorf("err got nil, want error")
+			}
+			if output == "" {
+				t.Errorf("output got empty, want error message")
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+
+	"github.com/jentz/oidc-cli/oidc"
+)
+
+// defaultJWKSCacheDir returns the directory key sets are cached in, or an empty string
+// if there is no user cache directory
+func defaultJWKSCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "oidc-cli", "jwks")
+}
+
+func parseVerifyFlags(name string, args []string, oidcConf *oidc.Config) (runner CommandRunner, output string, err error) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	var buf bytes.Buffer
+	flags.SetOutput(&buf)
+
+	flags.StringVar(&oidcConf.IssuerURL, "issuer", oidcConf.IssuerURL, "set issuer url (required)")
+	flags.StringVar(&oidcConf.DiscoveryEndpoint, "discovery-url", oidcConf.DiscoveryEndpoint, "override discovery url")
+	flags.StringVar(&oidcConf.JWKSEndpoint, "jwks-url", "", "override jwks url")
+	flags.StringVar(&oidcConf.JWKSCacheDir, "jwks-cache-dir", defaultJWKSCacheDir(), "directory to cache the jwks in, set to empty to disable caching")
+	flags.StringVar(&oidcConf.ClientSecret, "client-secret", oidcConf.ClientSecret, "set client secret (to verify tokens signed with HMAC algorithms)")
+	flags.BoolVar(&oidcConf.SkipTLSVerify, "skip-tls-verify", oidcConf.SkipTLSVerify, "skip TLS certificate verification")
+	flags.StringVar(&oidcConf.PrivateKeyFile, "private-key", "", "file to read private key from (to decrypt encrypted tokens)")
+
+	var flowConf oidc.VerifyFlowConfig
+	flags.StringVar(&flowConf.Token, "token", "", "token to be verified or '-' to read token from stdin (required)")
+	flags.StringVar(&flowConf.Audience, "audience", "", "expected audience (aud claim)")
+	flags.StringVar(&flowConf.TokenType, "type", "", "expected typ header, e.g. at+jwt for JWT access tokens")
+	flags.DurationVar(&flowConf.ClockSkew, "clock-skew", oidc.DefaultClockSkew, "allowed clock skew when checking exp, nbf and iat")
+
+	runner = &oidc.VerifyFlow{
+		Config:     oidcConf,
+		FlowConfig: &flowConf,
+	}
+
+	err = flags.Parse(args)
+	if err != nil {
+		return nil, buf.String(), err
+	}
+
+	// Read token from stdin if token equals '-'
+	if flowConf.Token == "-" {
+		scanner := bufio.NewScanner(os.Stdin)
+		scanner.Scan()
+		flowConf.Token = scanner.Text()
+	}
+
+	var invalidArgsChecks = []struct {
+		condition bool
+		message   string
+	}{
+		{
+			oidcConf.IssuerURL == "",
+			"issuer is required",
+		},
+		{
+			flowConf.Token == "",
+			"token is required",
+		},
+	}
+
+	for _, check := range invalidArgsChecks {
+		if check.condition {
+			return nil, check.message, flag.ErrHelp
+		}
+	}
+
+	return runner, buf.String(), nil
+}
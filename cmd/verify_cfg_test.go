@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jentz/oidc-cli/oidc"
+)
+
+func TestParseVerifyFlagsResult(t *testing.T) {
+	var tests = []struct {
+		name     string
+		args     []string
+		oidcConf oidc.Config
+		flowConf oidc.VerifyFlowConfig
+	}{
+		{
+			"all flags",
+			[]string{
+				"--issuer", "https://example.com",
+				"--discovery-url", "https://example.com/.well-known/openid-configuration",
+				"--jwks-url", "https://example.com/jwks",
+				"--jwks-cache-dir", "/tmp/jwks",
+				"--client-secret", "client-secret",
+				"--skip-tls-verify",
+				"--private-key", "private.pem",
+				"--token", "token",
+				"--audience", "https://api.example.com",
+				"--type", "at+jwt",
+				"--clock-skew", "5s",
+			},
+			oidc.Config{
+				IssuerURL:         "https://example.com",
+				DiscoveryEndpoint: "https://example.com/.well-known/openid-configuration",
+				JWKSEndpoint:      "https://example.com/jwks",
+				JWKSCacheDir:      "/tmp/jwks",
+				ClientSecret:      "client-secret",
+				SkipTLSVerify:     true,
+				PrivateKeyFile:    "private.pem",
+			},
+			oidc.VerifyFlowConfig{
+				Token:     "token",
+				Audience:  "https://api.example.com",
+				TokenType: "at+jwt",
+				ClockSkew: 5 * time.Second,
+			},
+		},
+		{
+			"defaults",
+			[]string{
+				"--issuer", "https://example.com",
+				"--token", "token",
+			},
+			oidc.Config{
+				IssuerURL:    "https://example.com",
+				JWKSCacheDir: defaultJWKSCacheDir(),
+			},
+			oidc.VerifyFlowConfig{
+				Token:     "token",
+				ClockSkew: oidc.DefaultClockSkew,
+			},
+		},
+		{
+			"caching disabled",
+			[]string{
+				"--issuer", "https://example.com",
+				"--token", "token",
+				"--jwks-cache-dir", "",
+			},
+			oidc.Config{
+				IssuerURL: "https://example.com",
+			},
+			oidc.VerifyFlowConfig{
+				Token:     "token",
+				ClockSkew: oidc.DefaultClockSkew,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner, output, err := parseVerifyFlags("verify", tt.args, &oidc.Config{})
+			if err != nil {
+				t.Errorf("err got %v, want nil", err)
+			}
+			if output != "" {
+				t.Errorf("output got %q, want empty", output)
+			}
+			f, ok := runner.(*oidc.VerifyFlow)
+			if !ok {
+				t.Fatalf("unexpected runner type: %T", runner)
+			}
+			if !reflect.DeepEqual(*f.Config, tt.oidcConf) {
+				t.Errorf("Config got %+v, want %+v", *f.Config, tt.oidcConf)
+			}
+			if !reflect.DeepEqual(*f.FlowConfig, tt.flowConf) {
+				t.Errorf("FlowConfig got %+v, want %+v", *f.FlowConfig, tt.flowConf)
+			}
+		})
+	}
+}
+
+func TestParseVerifyFlagsError(t *testing.T) {
+	var tests = []struct {
+		name string
+		args []string
+	}{
+		{
+			"missing issuer",
+			[]string{
+				"--token", "token",
+			},
+		},
+		{
+			"missing token",
+			[]string{
+				"--issuer", "https://example.com",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, output, err := parseVerifyFlags("verify", tt.args, &oidc.Config{})
+			if err == nil {
+				t.Errorf("err got nil, want error")
+			}
+			if output == "" {
+				t.Errorf("output got empty, want error message")
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ClientAssertionType is the client_assertion_type of JWT client assertions (RFC 7523 section 2.2)
+const ClientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// ClientAssertionLifetime is how long a client assertion is valid after it has been issued
+const ClientAssertionLifetime = 5 * time.Minute
+
+// NewClientAssertion creates a JWT client assertion as described in RFC 7523 section 3, with the
+// client as issuer and subject. The key is a private key, or the client secret for HMAC algorithms.
+// If alg is empty, the algorithm is derived from the key.
+func NewClientAssertion(clientID, audience string, key any, kid, alg string) (string, error) {
+	jti, err := GenerateRandomValue()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	signed, err := SignJWT(jwt.MapClaims{
+		"iss": clientID,
+		"sub": clientID,
+		"aud": audience,
+		"jti": jti,
+		"iat": now.Unix(),
+		"exp": now.Add(ClientAssertionLifetime).Unix(),
+	}, key, kid, alg)
+	if err != nil {
+		return "", fmt.Errorf("error signing client assertion: %w", err)
+	}
+	return signed, nil
+}
+
+// SignJWT signs the claims with a private key, or a secret for HMAC algorithms.
+// If alg is empty, the algorithm is derived from the key. The kid header is set if kid is not empty.
+func SignJWT(claims jwt.MapClaims, key any, kid, alg string) (string, error) {
+	return signJWT(claims, key, kid, alg, "")
+}
+
+// signJWT is SignJWT with an explicit typ header, which is omitted if typ is empty
+func signJWT(claims jwt.MapClaims, key any, kid, alg, typ string) (string, error) {
+	if alg == "" {
+		alg = signingAlgorithmForKey(key)
+		if alg == "" {
+			return "", fmt.Errorf("unsupported key type: %T", key)
+		}
+	}
+	method := jwt.GetSigningMethod(alg)
+	if method == nil || alg == "none" {
+		return "", fmt.Errorf("unsupported algorithm %q", alg)
+	}
+
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	if typ != "" {
+		token.Header["typ"] = typ
+	}
+	return token.SignedString(key)
+}
+
+// signingAlgorithmForKey returns the default JWS algorithm for a private key
+func signingAlgorithmForKey(key any) string {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return "RS256"
+	case *ecdsa.PrivateKey:
+		return ecdsaAlgorithmString(&k.PublicKey)
+	case ed25519.PrivateKey:
+		return ed25519AlgorithmString()
+	case []byte:
+		return "HS256"
+	default:
+		return ""
+	}
+}
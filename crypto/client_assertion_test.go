@@ -0,0 +1,54 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestNewClientAssertion(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	tests := []struct {
+		name    string
+		key     any
+		alg     string
+		wantAlg string
+		wantErr bool
+	}{
+		{"rsa default", rsaKey, "", "RS256", false},
+		{"rsa pss", rsaKey, "PS256", "PS256", false},
+		{"ecdsa default", ecKey, "", "ES384", false},
+		{"ed25519 default", edKey, "", "EdDSA", false},
+		{"hmac default", []byte("secret"), "", "HS256", false},
+		{"unsupported algorithm", rsaKey, "XS256", "", true},
+		{"none algorithm", rsaKey, "none", "", true},
+		{"unsupported key", "key", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertion, err := NewClientAssertion("client-id", "https://example.com/token", tt.key, "", tt.alg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewClientAssertion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			token, _, err := jwt.NewParser().ParseUnverified(assertion, jwt.MapClaims{})
+			if err != nil {
+				t.Fatalf("failed to parse assertion: %v", err)
+			}
+			if token.Header["alg"] != tt.wantAlg {
+				t.Errorf("alg = %v, want %v", token.Header["alg"], tt.wantAlg)
+			}
+		})
+	}
+}
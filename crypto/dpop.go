@@ -43,6 +43,8 @@ type DPoPProofBuilder struct {
 	method        string
 	url           string
 	jti           string
+	ath           string
+	nonce         string
 	alg           string
 	jwk           any
 	token         *jwt.Token
@@ -104,6 +106,24 @@ func (d *DPoPProofBuilder) URL(s string) *DPoPProofBuilder {
 	return d
 }
 
+// AccessToken binds the proof to an access token by setting the ath claim,
+// as required when presenting the token to a protected resource.
+func (d *DPoPProofBuilder) AccessToken(s string) *DPoPProofBuilder {
+	if s == "" {
+		d.errs = append(d.errs, errors.New("access token cannot be empty"))
+	}
+	hash := sha256.Sum256([]byte(s))
+	d.ath = base64.RawURLEncoding.EncodeToString(hash[:])
+	return d
+}
+
+// Nonce sets the nonce claim to a value provided by the server in the DPoP-Nonce header
+// (RFC 9449 section 8). An empty nonce leaves the claim out.
+func (d *DPoPProofBuilder) Nonce(s string) *DPoPProofBuilder {
+	d.nonce = s
+	return d
+}
+
 func (d *DPoPProofBuilder) Build() (*DPoPProof, error) {
 	if len(d.errs) > 0 {
 		return nil, fmt.Errorf("build errors: %v", d.errs)
@@ -173,6 +193,12 @@ func (d *DPoPProofBuilder) constructJWT() {
 		"htu": d.url,
 		"iat": time.Now().Unix(),
 	}
+	if d.ath != "" {
+		claims["ath"] = d.ath
+	}
+	if d.nonce != "" {
+		claims["nonce"] = d.nonce
+	}
 	d.token = jwt.NewWithClaims(d.signingMethod, claims)
 	d.token.Header = header
 }
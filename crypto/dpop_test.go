@@ -293,6 +293,44 @@ func TestConstructJWT(t *testing.T) {
 	}
 }
 
+func TestAccessToken(t *testing.T) {
+	// Example access token and ath value from RFC 9449 section 7.1
+	d := NewDPoPProofBuilder().AccessToken("Kz~8mXK1EalYznwH-LC-1fBAo.4Ljp~zsPE_NeO.gxU")
+	want := "fUHyO2r2Z3DZ53EsNrWBb0xWXoaNy59IiKCAqksmQEo"
+	if d.ath != want {
+		t.Errorf("AccessToken() ath = %v, want %v", d.ath, want)
+	}
+	if len(d.errs) != 0 {
+		t.Errorf("AccessToken() errs = %v, want none", d.errs)
+	}
+
+	d.signingMethod = jwt.SigningMethodRS256
+	d.constructJWT()
+	if claims := d.token.Claims.(jwt.MapClaims); claims["ath"] != want {
+		t.Errorf("claims[\"ath\"] = %v, want %v", claims["ath"], want)
+	}
+
+	if d := NewDPoPProofBuilder().AccessToken(""); len(d.errs) == 0 {
+		t.Error("AccessToken(\"\") errs = none, want error")
+	}
+}
+
+func TestNonce(t *testing.T) {
+	d := NewDPoPProofBuilder().Nonce("eyJ7S_zG.eyJH0-Z.HX4w-7v")
+	d.signingMethod = jwt.SigningMethodRS256
+	d.constructJWT()
+	if claims := d.token.Claims.(jwt.MapClaims); claims["nonce"] != "eyJ7S_zG.eyJH0-Z.HX4w-7v" {
+		t.Errorf("claims[\"nonce\"] = %v, want %v", claims["nonce"], "eyJ7S_zG.eyJH0-Z.HX4w-7v")
+	}
+
+	d = NewDPoPProofBuilder().Nonce("")
+	d.signingMethod = jwt.SigningMethodRS256
+	d.constructJWT()
+	if _, ok := d.token.Claims.(jwt.MapClaims)["nonce"]; ok {
+		t.Error("claims[\"nonce\"] is set, want no nonce claim")
+	}
+}
+
 func TestSignJWT(t *testing.T) {
 	privateKey, _ := rsa.GenerateKey(rand.Reader, 2048)
 	publicKey := &privateKey.PublicKey
@@ -0,0 +1,568 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+// Key management algorithms (RFC 7518 section 4.1)
+const (
+	JWEAlgRSAOAEP      = "RSA-OAEP"
+	JWEAlgRSAOAEP256   = "RSA-OAEP-256"
+	JWEAlgECDHES       = "ECDH-ES"
+	JWEAlgECDHESA128KW = "ECDH-ES+A128KW"
+	JWEAlgECDHESA192KW = "ECDH-ES+A192KW"
+	JWEAlgECDHESA256KW = "ECDH-ES+A256KW"
+)
+
+// Content encryption algorithms (RFC 7518 section 5.1)
+const (
+	JWEEncA128CBCHS256 = "A128CBC-HS256"
+	JWEEncA192CBCHS384 = "A192CBC-HS384"
+	JWEEncA256CBCHS512 = "A256CBC-HS512"
+	JWEEncA128GCM      = "A128GCM"
+	JWEEncA192GCM      = "A192GCM"
+	JWEEncA256GCM      = "A256GCM"
+)
+
+const (
+	jweAESKeyWrapIV = uint64(0xA6A6A6A6A6A6A6A6)
+	jweGCMNonceSize = 12
+	jweGCMTagSize   = 16
+	jweCBCIVSize    = aes.BlockSize
+)
+
+type jweHeader struct {
+	Alg string `json:"alg"`
+	Enc string `json:"enc"`
+	Zip string `json:"zip,omitempty"`
+	Cty string `json:"cty,omitempty"`
+	Epk *JWK   `json:"epk,omitempty"`
+	Apu string `json:"apu,omitempty"`
+	Apv string `json:"apv,omitempty"`
+}
+
+// IsJWE reports whether the token uses the five part JWE compact serialization.
+func IsJWE(token string) bool {
+	return strings.Count(token, ".") == 4
+}
+
+// DecryptJWE decrypts a compact JWE (RFC 7516) with the recipient's private key.
+// It returns the plaintext and the content type (cty) header, which is "JWT" for nested tokens.
+func DecryptJWE(token string, privateKey any) ([]byte, string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 5 {
+		return nil, "", errors.New("JWE must have five parts")
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid JWE header encoding: %w", err)
+	}
+	var header jweHeader
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return nil, "", fmt.Errorf("invalid JWE header: %w", err)
+	}
+	if header.Zip != "" {
+		return nil, "", fmt.Errorf("unsupported JWE compression: %s", header.Zip)
+	}
+
+	var segments [4][]byte
+	for i, part := range parts[1:] {
+		segments[i], err = base64.RawURLEncoding.DecodeString(part)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid JWE segment encoding: %w", err)
+		}
+	}
+	encryptedKey, iv, ciphertext, tag := segments[0], segments[1], segments[2], segments[3]
+
+	keySize, err := jweContentKeySize(header.Enc)
+	if err != nil {
+		return nil, "", err
+	}
+	cek, err := jweDecryptKey(&header, encryptedKey, privateKey, keySize)
+	if err != nil {
+		return nil, "", err
+	}
+
+	plaintext, err := jweDecryptContent(header.Enc, cek, iv, ciphertext, tag, []byte(parts[0]))
+	if err != nil {
+		return nil, "", err
+	}
+	return plaintext, header.Cty, nil
+}
+
+// EncryptJWE encrypts the plaintext to the recipient's public key and returns a compact JWE (RFC 7516).
+// alg is the key management algorithm and enc the content encryption algorithm.
+// Additional protected header parameters, such as kid or cty, can be provided in extraHeaders.
+func EncryptJWE(plaintext []byte, publicKey any, alg, enc string, extraHeaders map[string]any) (string, error) {
+	keySize, err := jweContentKeySize(enc)
+	if err != nil {
+		return "", err
+	}
+
+	header := map[string]any{}
+	for k, v := range extraHeaders {
+		header[k] = v
+	}
+	header["alg"] = alg
+	header["enc"] = enc
+
+	cek, encryptedKey, err := jweEncryptKey(header, publicKey, alg, enc, keySize)
+	if err != nil {
+		return "", err
+	}
+
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JWE header: %w", err)
+	}
+	protected := base64.RawURLEncoding.EncodeToString(headerJSON)
+
+	iv, ciphertext, tag, err := jweEncryptContent(enc, cek, plaintext, []byte(protected))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join([]string{
+		protected,
+		base64.RawURLEncoding.EncodeToString(encryptedKey),
+		base64.RawURLEncoding.EncodeToString(iv),
+		base64.RawURLEncoding.EncodeToString(ciphertext),
+		base64.RawURLEncoding.EncodeToString(tag),
+	}, "."), nil
+}
+
+func jweContentKeySize(enc string) (int, error) {
+	switch enc {
+	case JWEEncA128GCM:
+		return 16, nil
+	case JWEEncA192GCM:
+		return 24, nil
+	case JWEEncA256GCM, JWEEncA128CBCHS256:
+		return 32, nil
+	case JWEEncA192CBCHS384:
+		return 48, nil
+	case JWEEncA256CBCHS512:
+		return 64, nil
+	default:
+		return 0, fmt.Errorf("unsupported JWE content encryption algorithm: %s", enc)
+	}
+}
+
+func jweKeyWrapSize(alg string) int {
+	switch alg {
+	case JWEAlgECDHESA128KW:
+		return 16
+	case JWEAlgECDHESA192KW:
+		return 24
+	case JWEAlgECDHESA256KW:
+		return 32
+	default:
+		return 0
+	}
+}
+
+func jweDecryptKey(header *jweHeader, encryptedKey []byte, privateKey any, keySize int) ([]byte, error) {
+	switch header.Alg {
+	case JWEAlgRSAOAEP, JWEAlgRSAOAEP256:
+		key, ok := privateKey.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("%s requires an RSA private key, got %T", header.Alg, privateKey)
+		}
+		cek, err := rsa.DecryptOAEP(jweOAEPHash(header.Alg), nil, key, encryptedKey, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt content encryption key: %w", err)
+		}
+		if len(cek) != keySize {
+			return nil, errors.New("content encryption key has invalid size")
+		}
+		return cek, nil
+	case JWEAlgECDHES, JWEAlgECDHESA128KW, JWEAlgECDHESA192KW, JWEAlgECDHESA256KW:
+		if header.Epk == nil {
+			return nil, errors.New("JWE header is missing the ephemeral public key")
+		}
+		epk, err := header.Epk.PublicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid ephemeral public key: %w", err)
+		}
+		z, err := ecdhSharedSecret(privateKey, epk)
+		if err != nil {
+			return nil, err
+		}
+		apu, err := base64.RawURLEncoding.DecodeString(header.Apu)
+		if err != nil {
+			return nil, fmt.Errorf("invalid apu header: %w", err)
+		}
+		apv, err := base64.RawURLEncoding.DecodeString(header.Apv)
+		if err != nil {
+			return nil, fmt.Errorf("invalid apv header: %w", err)
+		}
+		if header.Alg == JWEAlgECDHES {
+			if len(encryptedKey) != 0 {
+				return nil, errors.New("encrypted key must be empty for ECDH-ES")
+			}
+			return concatKDF(z, header.Enc, apu, apv, keySize), nil
+		}
+		kek := concatKDF(z, header.Alg, apu, apv, jweKeyWrapSize(header.Alg))
+		cek, err := aesKeyUnwrap(kek, encryptedKey)
+		if err != nil {
+			return nil, err
+		}
+		if len(cek) != keySize {
+			return nil, errors.New("content encryption key has invalid size")
+		}
+		return cek, nil
+	default:
+		return nil, fmt.Errorf("unsupported JWE key management algorithm: %s", header.Alg)
+	}
+}
+
+func jweEncryptKey(header map[string]any, publicKey any, alg, enc string, keySize int) (cek []byte, encryptedKey []byte, err error) {
+	switch alg {
+	case JWEAlgRSAOAEP, JWEAlgRSAOAEP256:
+		key, ok := publicKey.(*rsa.PublicKey)
+		if !ok {
+			return nil, nil, fmt.Errorf("%s requires an RSA public key, got %T", alg, publicKey)
+		}
+		cek = make([]byte, keySize)
+		if _, err := rand.Read(cek); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate content encryption key: %w", err)
+		}
+		encryptedKey, err = rsa.EncryptOAEP(jweOAEPHash(alg), rand.Reader, key, cek, nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to encrypt content encryption key: %w", err)
+		}
+		return cek, encryptedKey, nil
+	case JWEAlgECDHES, JWEAlgECDHESA128KW, JWEAlgECDHESA192KW, JWEAlgECDHESA256KW:
+		ephemeral, epk, err := ecdhEphemeralKey(publicKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		header["epk"] = epk
+		z, err := ecdhSharedSecret(ephemeral, publicKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		if alg == JWEAlgECDHES {
+			return concatKDF(z, enc, nil, nil, keySize), nil, nil
+		}
+		kek := concatKDF(z, alg, nil, nil, jweKeyWrapSize(alg))
+		cek = make([]byte, keySize)
+		if _, err := rand.Read(cek); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate content encryption key: %w", err)
+		}
+		encryptedKey, err = aesKeyWrap(kek, cek)
+		if err != nil {
+			return nil, nil, err
+		}
+		return cek, encryptedKey, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported JWE key management algorithm: %s", alg)
+	}
+}
+
+func jweOAEPHash(alg string) hash.Hash {
+	if alg == JWEAlgRSAOAEP256 {
+		return sha256.New()
+	}
+	return sha1.New()
+}
+
+func jweDecryptContent(enc string, cek, iv, ciphertext, tag, aad []byte) ([]byte, error) {
+	switch enc {
+	case JWEEncA128GCM, JWEEncA192GCM, JWEEncA256GCM:
+		gcm, err := newGCM(cek)
+		if err != nil {
+			return nil, err
+		}
+		if len(iv) != jweGCMNonceSize {
+			return nil, errors.New("invalid JWE initialization vector size")
+		}
+		if len(tag) != jweGCMTagSize {
+			return nil, errors.New("invalid JWE authentication tag size")
+		}
+		plaintext, err := gcm.Open(nil, iv, append(ciphertext, tag...), aad)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt JWE content: %w", err)
+		}
+		return plaintext, nil
+	case JWEEncA128CBCHS256, JWEEncA192CBCHS384, JWEEncA256CBCHS512:
+		macKey, encKey := cek[:len(cek)/2], cek[len(cek)/2:]
+		if len(iv) != jweCBCIVSize {
+			return nil, errors.New("invalid JWE initialization vector size")
+		}
+		// The tag is the first half of the HMAC output, as long as the MAC key (RFC 7518 section 5.2.2.1)
+		if len(tag) != len(macKey) {
+			return nil, errors.New("invalid JWE authentication tag size")
+		}
+		if len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+			return nil, errors.New("invalid JWE ciphertext")
+		}
+		expected := cbcHMACTag(enc, macKey, aad, iv, ciphertext)
+		if subtle.ConstantTimeCompare(expected, tag) != 1 {
+			return nil, errors.New("failed to decrypt JWE content: authentication tag mismatch")
+		}
+		block, err := aes.NewCipher(encKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cipher: %w", err)
+		}
+		plaintext := make([]byte, len(ciphertext))
+		cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+		return pkcs7Unpad(plaintext)
+	default:
+		return nil, fmt.Errorf("unsupported JWE content encryption algorithm: %s", enc)
+	}
+}
+
+func jweEncryptContent(enc string, cek, plaintext, aad []byte) (iv, ciphertext, tag []byte, err error) {
+	switch enc {
+	case JWEEncA128GCM, JWEEncA192GCM, JWEEncA256GCM:
+		gcm, err := newGCM(cek)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		iv = make([]byte, jweGCMNonceSize)
+		if _, err := rand.Read(iv); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to generate initialization vector: %w", err)
+		}
+		sealed := gcm.Seal(nil, iv, plaintext, aad)
+		split := len(sealed) - gcm.Overhead()
+		return iv, sealed[:split], sealed[split:], nil
+	case JWEEncA128CBCHS256, JWEEncA192CBCHS384, JWEEncA256CBCHS512:
+		macKey, encKey := cek[:len(cek)/2], cek[len(cek)/2:]
+		block, err := aes.NewCipher(encKey)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to create cipher: %w", err)
+		}
+		iv = make([]byte, jweCBCIVSize)
+		if _, err := rand.Read(iv); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to generate initialization vector: %w", err)
+		}
+		padded := pkcs7Pad(plaintext)
+		ciphertext = make([]byte, len(padded))
+		cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, padded)
+		return iv, ciphertext, cbcHMACTag(enc, macKey, aad, iv, ciphertext), nil
+	default:
+		return nil, nil, nil, fmt.Errorf("unsupported JWE content encryption algorithm: %s", enc)
+	}
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// cbcHMACTag computes the authentication tag for the AES_CBC_HMAC_SHA2 algorithms (RFC 7518 section 5.2.2.1).
+func cbcHMACTag(enc string, macKey, aad, iv, ciphertext []byte) []byte {
+	var h func() hash.Hash
+	switch enc {
+	case JWEEncA192CBCHS384:
+		h = sha512.New384
+	case JWEEncA256CBCHS512:
+		h = sha512.New
+	default:
+		h = sha256.New
+	}
+	al := make([]byte, 8)
+	binary.BigEndian.PutUint64(al, uint64(len(aad))*8)
+
+	mac := hmac.New(h, macKey)
+	mac.Write(aad)
+	mac.Write(iv)
+	mac.Write(ciphertext)
+	mac.Write(al)
+	return mac.Sum(nil)[:len(macKey)]
+}
+
+func pkcs7Pad(data []byte) []byte {
+	padding := aes.BlockSize - len(data)%aes.BlockSize
+	return append(bytes.Clone(data), bytes.Repeat([]byte{byte(padding)}, padding)...)
+}
+
+func pkcs7Unpad(data []byte) ([]byte, error) {
+	padding := int(data[len(data)-1])
+	if padding == 0 || padding > aes.BlockSize || padding > len(data) {
+		return nil, errors.New("invalid JWE content padding")
+	}
+	for _, b := range data[len(data)-padding:] {
+		if int(b) != padding {
+			return nil, errors.New("invalid JWE content padding")
+		}
+	}
+	return data[:len(data)-padding], nil
+}
+
+// ecdhSharedSecret computes the ECDH shared secret between a private key and a public key
+// given as either crypto/ecdsa or crypto/ecdh keys.
+func ecdhSharedSecret(privateKey any, publicKey any) ([]byte, error) {
+	var priv *ecdh.PrivateKey
+	switch k := privateKey.(type) {
+	case *ecdh.PrivateKey:
+		priv = k
+	case *ecdsa.PrivateKey:
+		var err error
+		if priv, err = k.ECDH(); err != nil {
+			return nil, fmt.Errorf("invalid EC private key: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("ECDH-ES requires an EC or X25519 private key, got %T", privateKey)
+	}
+
+	var pub *ecdh.PublicKey
+	switch k := publicKey.(type) {
+	case *ecdh.PublicKey:
+		pub = k
+	case *ecdsa.PublicKey:
+		var err error
+		if pub, err = k.ECDH(); err != nil {
+			return nil, fmt.Errorf("invalid EC public key: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("ECDH-ES requires an EC or X25519 public key, got %T", publicKey)
+	}
+
+	z, err := priv.ECDH(pub)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute ECDH shared secret: %w", err)
+	}
+	return z, nil
+}
+
+// ecdhEphemeralKey generates an ephemeral key on the curve of the recipient's public key
+// and returns it with its public JWK representation.
+func ecdhEphemeralKey(publicKey any) (*ecdh.PrivateKey, *JWK, error) {
+	switch k := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		ephemeral, err := ecdsa.GenerateKey(k.Curve, rand.Reader)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to generate ephemeral key: %w", err)
+		}
+		epk, err := NewJWK(&ephemeral.PublicKey, "")
+		if err != nil {
+			return nil, nil, err
+		}
+		priv, err := ephemeral.ECDH()
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid ephemeral key: %w", err)
+		}
+		return priv, epk, nil
+	case *ecdh.PublicKey:
+		if k.Curve() != ecdh.X25519() {
+			return nil, nil, errors.New("only X25519 is supported for ECDH-ES with OKP keys")
+		}
+		priv, err := ecdh.X25519().GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to generate ephemeral key: %w", err)
+		}
+		epk := &JWK{
+			Kty: "OKP",
+			Crv: "X25519",
+			X:   base64.RawURLEncoding.EncodeToString(priv.PublicKey().Bytes()),
+		}
+		return priv, epk, nil
+	default:
+		return nil, nil, fmt.Errorf("ECDH-ES requires an EC or X25519 public key, got %T", publicKey)
+	}
+}
+
+// concatKDF derives a key from the ECDH shared secret (RFC 7518 section 4.6.2).
+func concatKDF(z []byte, algorithmID string, apu, apv []byte, keySize int) []byte {
+	lengthPrefixed := func(b []byte) []byte {
+		return binary.BigEndian.AppendUint32(nil, uint32(len(b)))
+	}
+
+	var otherInfo []byte
+	otherInfo = append(otherInfo, lengthPrefixed([]byte(algorithmID))...)
+	otherInfo = append(otherInfo, algorithmID...)
+	otherInfo = append(otherInfo, lengthPrefixed(apu)...)
+	otherInfo = append(otherInfo, apu...)
+	otherInfo = append(otherInfo, lengthPrefixed(apv)...)
+	otherInfo = append(otherInfo, apv...)
+	otherInfo = binary.BigEndian.AppendUint32(otherInfo, uint32(keySize*8))
+
+	var derived []byte
+	for counter := uint32(1); len(derived) < keySize; counter++ {
+		h := sha256.New()
+		h.Write(binary.BigEndian.AppendUint32(nil, counter))
+		h.Write(z)
+		h.Write(otherInfo)
+		derived = h.Sum(derived)
+	}
+	return derived[:keySize]
+}
+
+// aesKeyWrap wraps a key with the AES Key Wrap algorithm (RFC 3394).
+func aesKeyWrap(kek, key []byte) ([]byte, error) {
+	if len(key)%8 != 0 || len(key) < 16 {
+		return nil, errors.New("key to wrap must be a multiple of 8 bytes and at least 16 bytes")
+	}
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key wrap cipher: %w", err)
+	}
+
+	n := len(key) / 8
+	a := jweAESKeyWrapIV
+	r := bytes.Clone(key)
+	buf := make([]byte, 16)
+	for j := 0; j <= 5; j++ {
+		for i := 1; i <= n; i++ {
+			binary.BigEndian.PutUint64(buf[:8], a)
+			copy(buf[8:], r[(i-1)*8:i*8])
+			block.Encrypt(buf, buf)
+			a = binary.BigEndian.Uint64(buf[:8]) ^ uint64(n*j+i)
+			copy(r[(i-1)*8:i*8], buf[8:])
+		}
+	}
+	return append(binary.BigEndian.AppendUint64(nil, a), r...), nil
+}
+
+// aesKeyUnwrap unwraps a key wrapped with the AES Key Wrap algorithm (RFC 3394).
+func aesKeyUnwrap(kek, wrapped []byte) ([]byte, error) {
+	if len(wrapped)%8 != 0 || len(wrapped) < 24 {
+		return nil, errors.New("wrapped key must be a multiple of 8 bytes and at least 24 bytes")
+	}
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key wrap cipher: %w", err)
+	}
+
+	n := len(wrapped)/8 - 1
+	a := binary.BigEndian.Uint64(wrapped[:8])
+	r := bytes.Clone(wrapped[8:])
+	buf := make([]byte, 16)
+	for j := 5; j >= 0; j-- {
+		for i := n; i >= 1; i-- {
+			binary.BigEndian.PutUint64(buf[:8], a^uint64(n*j+i))
+			copy(buf[8:], r[(i-1)*8:i*8])
+			block.Decrypt(buf, buf)
+			a = binary.BigEndian.Uint64(buf[:8])
+			copy(r[(i-1)*8:i*8], buf[8:])
+		}
+	}
+	if a != jweAESKeyWrapIV {
+		return nil, errors.New("failed to unwrap content encryption key: integrity check failed")
+	}
+	return r, nil
+}
@@ -0,0 +1,334 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestEncryptDecryptJWE(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	x25519Key, _ := ecdh.X25519().GenerateKey(rand.Reader)
+
+	tests := []struct {
+		name       string
+		publicKey  any
+		privateKey any
+		alg        string
+		enc        string
+	}{
+		{"RSA-OAEP A128CBC-HS256", &rsaKey.PublicKey, rsaKey, JWEAlgRSAOAEP, JWEEncA128CBCHS256},
+		{"RSA-OAEP-256 A256GCM", &rsaKey.PublicKey, rsaKey, JWEAlgRSAOAEP256, JWEEncA256GCM},
+		{"RSA-OAEP-256 A256CBC-HS512", &rsaKey.PublicKey, rsaKey, JWEAlgRSAOAEP256, JWEEncA256CBCHS512},
+		{"ECDH-ES A128GCM", &ecKey.PublicKey, ecKey, JWEAlgECDHES, JWEEncA128GCM},
+		{"ECDH-ES+A128KW A192CBC-HS384", &ecKey.PublicKey, ecKey, JWEAlgECDHESA128KW, JWEEncA192CBCHS384},
+		{"ECDH-ES+A256KW A192GCM", &ecKey.PublicKey, ecKey, JWEAlgECDHESA256KW, JWEEncA192GCM},
+		{"ECDH-ES X25519", x25519Key.PublicKey(), x25519Key, JWEAlgECDHES, JWEEncA256GCM},
+	}
+
+	plaintext := []byte(`{"sub":"alice","email":"alice@example.com"}`)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := EncryptJWE(plaintext, tt.publicKey, tt.alg, tt.enc, map[string]any{"cty": "JWT"})
+			if err != nil {
+				t.Fatalf("EncryptJWE() error = %v", err)
+			}
+			if !IsJWE(token) {
+				t.Fatalf("IsJWE(%q) = false, want true", token)
+			}
+
+			got, cty, err := DecryptJWE(token, tt.privateKey)
+			if err != nil {
+				t.Fatalf("DecryptJWE() error = %v", err)
+			}
+			if !bytes.Equal(got, plaintext) {
+				t.Errorf("DecryptJWE() = %q, want %q", got, plaintext)
+			}
+			if cty != "JWT" {
+				t.Errorf("DecryptJWE() cty = %q, want JWT", cty)
+			}
+		})
+	}
+}
+
+func TestDecryptJWEError(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	token, err := EncryptJWE([]byte("secret"), &rsaKey.PublicKey, JWEAlgRSAOAEP256, JWEEncA128CBCHS256, nil)
+	if err != nil {
+		t.Fatalf("EncryptJWE() error = %v", err)
+	}
+	parts := strings.Split(token, ".")
+	tamperedTag := strings.Join(append(parts[:4:4], base64.RawURLEncoding.EncodeToString(make([]byte, 16))), ".")
+	gcmToken, err := EncryptJWE([]byte("secret"), &rsaKey.PublicKey, JWEAlgRSAOAEP256, JWEEncA256GCM, nil)
+	if err != nil {
+		t.Fatalf("EncryptJWE() error = %v", err)
+	}
+	gcmParts := strings.Split(gcmToken, ".")
+	// replace replaces the part at index i of a compact JWE with the base64url encoding of b
+	replace := func(parts []string, i int, b []byte) string {
+		p := append([]string(nil), parts...)
+		p[i] = base64.RawURLEncoding.EncodeToString(b)
+		return strings.Join(p, ".")
+	}
+	truncate := func(parts []string, i, n int) []byte {
+		b, _ := base64.RawURLEncoding.DecodeString(parts[i])
+		return b[:n]
+	}
+
+	tests := []struct {
+		name       string
+		token      string
+		privateKey any
+	}{
+		{"wrong private key", token, otherKey},
+		{"wrong key type", token, ecKey},
+		{"tampered tag", tamperedTag, rsaKey},
+		{"truncated tag", replace(parts, 4, truncate(parts, 4, 8)), rsaKey},
+		{"empty tag", replace(parts, 4, nil), rsaKey},
+		{"short iv", replace(parts, 2, truncate(parts, 2, 8)), rsaKey},
+		{"gcm truncated tag", replace(gcmParts, 4, truncate(gcmParts, 4, 12)), rsaKey},
+		{"gcm short iv", replace(gcmParts, 2, truncate(gcmParts, 2, 8)), rsaKey},
+		{"not a jwe", "a.b.c", rsaKey},
+		{"invalid header", "e30.." + strings.Join(parts[2:], "."), rsaKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := DecryptJWE(tt.token, tt.privateKey); err == nil {
+				t.Error("DecryptJWE() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestEncryptJWEError(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	tests := []struct {
+		name      string
+		publicKey any
+		alg       string
+		enc       string
+	}{
+		{"unsupported alg", &rsaKey.PublicKey, "RSA1_5", JWEEncA128GCM},
+		{"unsupported enc", &rsaKey.PublicKey, JWEAlgRSAOAEP, "A128KW"},
+		{"rsa alg with ec key", &ecKey.PublicKey, JWEAlgRSAOAEP, JWEEncA128GCM},
+		{"ecdh alg with rsa key", &rsaKey.PublicKey, JWEAlgECDHES, JWEEncA128GCM},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := EncryptJWE([]byte("secret"), tt.publicKey, tt.alg, tt.enc, nil); err == nil {
+				t.Error("EncryptJWE() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestConcatKDF(t *testing.T) {
+	// Test vector from RFC 7518 appendix C
+	z := []byte{158, 86, 217, 29, 129, 113, 53, 211, 114, 131, 66, 131, 191, 132, 38, 156,
+		251, 49, 110, 163, 218, 128, 106, 72, 246, 218, 167, 121, 140, 254, 144, 196}
+	got := concatKDF(z, "A128GCM", []byte("Alice"), []byte("Bob"), 16)
+	want := "VqqN6vgjbSBcIijNcacQGg"
+	if base64.RawURLEncoding.EncodeToString(got) != want {
+		t.Errorf("concatKDF() = %s, want %s", base64.RawURLEncoding.EncodeToString(got), want)
+	}
+}
+
+func TestAESKeyWrap(t *testing.T) {
+	// Test vector from RFC 3394 section 4.1
+	kek, _ := hex.DecodeString("000102030405060708090A0B0C0D0E0F")
+	key, _ := hex.DecodeString("00112233445566778899AABBCCDDEEFF")
+	want, _ := hex.DecodeString("1FA68B0A8112B447AEF34BD8FB5A7B829D3E862371D2CFE5")
+
+	wrapped, err := aesKeyWrap(kek, key)
+	if err != nil {
+		t.Fatalf("aesKeyWrap() error = %v", err)
+	}
+	if !bytes.Equal(wrapped, want) {
+		t.Errorf("aesKeyWrap() = %X, want %X", wrapped, want)
+	}
+
+	unwrapped, err := aesKeyUnwrap(kek, wrapped)
+	if err != nil {
+		t.Fatalf("aesKeyUnwrap() error = %v", err)
+	}
+	if !bytes.Equal(unwrapped, key) {
+		t.Errorf("aesKeyUnwrap() = %X, want %X", unwrapped, key)
+	}
+
+	wrapped[0] ^= 1
+	if _, err := aesKeyUnwrap(kek, wrapped); err == nil {
+		t.Error("aesKeyUnwrap() with tampered input error = nil, want error")
+	}
+}
+
+func TestDecryptJWERFC7516(t *testing.T) {
+	// Example JWE using RSAES-OAEP and AES GCM from RFC 7516 appendix A.1
+	token := "eyJhbGciOiJSU0EtT0FFUCIsImVuYyI6IkEyNTZHQ00ifQ." +
+		"OKOawDo13gRp2ojaHV7LFpZcgV7T6DVZKTyKOMTYUmKoTCVJRgckCL9kiMT03JGe" +
+		"ipsEdY3mx_etLbbWSrFr05kLzcSr4qKAq7YN7e9jwQRb23nfa6c9d-StnImGyFDb" +
+		"Sv04uVuxIp5Zms1gNxKKK2Da14B8S4rzVRltdYwam_lDp5XnZAYpQdb76FdIKLaV" +
+		"mqgfwX7XWRxv2322i-vDxRfqNzo_tETKzpVLzfiwQyeyPGLBIO56YJ7eObdv0je8" +
+		"1860ppamavo35UgoRdbYaBcoh9QcfylQr66oc6vFWXRcZ_ZT2LawVCWTIy3brGPi" +
+		"6UklfCpIMfIjf7iGdXKHzg." +
+		"48V1_ALb6US04U3b." +
+		"5eym8TW_c8SuK0ltJ3rpYIzOeDQz7TALvtu6UG9oMo4vpzs9tX_EFShS8iB7j6ji" +
+		"SdiwkIr3ajwQzaBtQD_A." +
+		"XFBoMYUZodetZdvTiFvSkQ"
+	key := rsaKeyFromJWK(t, map[string]string{
+		"n": "oahUIoWw0K0usKNuOR6H4wkf4oBUXHTxRvgb48E-BVvxkeDNjbC4he8rUWcJoZmds2h7M70imEVhRU5djINXtqllXI4DFqcI1DgjT9LewND8MW2Krf3Spsk_ZkoFnilakGygTwpZ3uesH-PFABNIUYpOiN15dsQRkgr0vEhxN92i2asbOenSZeyaxziK72UwxrrKoExv6kc5twXTq4h-QChLOln0_mtUZwfsRaMStPs6mS6XrgxnxbWhojf663tuEQueGC-FCMfra36C9knDFGzKsNa7LZK2djYgyD3JR_MB_4NUJW_TqOQtwHYbxevoJArm-L5StowjzGy-_bq6Gw",
+		"e": "AQAB",
+		"d": "kLdtIj6GbDks_ApCSTYQtelcNttlKiOyPzMrXHeI-yk1F7-kpDxY4-WY5NWV5KntaEeXS1j82E375xxhWMHXyvjYecPT9fpwR_M9gV8n9Hrh2anTpTD93Dt62ypW3yDsJzBnTnrYu1iwWRgBKrEYY46qAZIrA2xAwnm2X7uGR1hghkqDp0Vqj3kbSCz1XyfCs6_LehBwtxHIyh8Ripy40p24moOAbgxVw3rxT_vlt3UVe4WO3JkJOzlpUf-KTVI2Ptgm-dARxTEtE-id-4OJr0h-K-VFs3VSndVTIznSxfyrj8ILL6MG_Uv8YAu7VILSB3lOW085-4qE3DzgrTjgyQ",
+		"p": "1r52Xk46c-LsfB5P442p7atdPUrxQSy4mti_tZI3Mgf2EuFVbUoDBvaRQ-SWxkbkmoEzL7JXroSBjSrK3YIQgYdMgyAEPTPjXv_hI2_1eTSPVZfzL0lffNn03IXqWF5MDFuoUYE0hzb2vhrlN_rKrbfDIwUbTrjjgieRbwC6Cl0",
+		"q": "wLb35x7hmQWZsWJmB_vle87ihgZ19S8lBEROLIsZG4ayZVe9Hi9gDVCOBmUDdaDYVTSNx_8Fyw1YYa9XGrGnDew00J28cRUoeBB_jKI1oma0Orv1T9aXIWxKwd4gvxFImOWr3QRL9KEBRzk2RatUBnmDZJTIAfwTs0g68UZHvtc",
+	})
+
+	got, _, err := DecryptJWE(token, key)
+	if err != nil {
+		t.Fatalf("DecryptJWE() error = %v", err)
+	}
+	if want := "The true sign of intelligence is not knowledge but imagination."; string(got) != want {
+		t.Errorf("DecryptJWE() = %q, want %q", got, want)
+	}
+}
+
+func TestJWEContentVectors(t *testing.T) {
+	tests := []struct {
+		name       string
+		enc        string
+		key        string
+		iv         string
+		aad        string
+		plaintext  string
+		ciphertext string
+		tag        string
+	}{
+		{
+			// Content encryption of the example JWE in RFC 7516 appendix A.2
+			name:       "RFC 7516 A.2 A128CBC-HS256",
+			enc:        JWEEncA128CBCHS256,
+			key:        "04d31fc5549dfcfe0b649dfa3faa6ace6b7cd42d6f6b09dbc8b100f08f9c2ccf",
+			iv:         "03163c0c2b4368696c6c69636f746865",
+			aad:        hex.EncodeToString([]byte("eyJhbGciOiJSU0ExXzUiLCJlbmMiOiJBMTI4Q0JDLUhTMjU2In0")),
+			plaintext:  hex.EncodeToString([]byte("Live long and prosper.")),
+			ciphertext: "283953b577218594c6b9f31898e6064b81df7f13d252b7e6a821d7688f703866",
+			tag:        "f611f4be045f6203e700739df2cb64bf",
+		},
+		{
+			name:       "RFC 7518 B.3 A256CBC-HS512",
+			enc:        JWEEncA256CBCHS512,
+			key:        "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f202122232425262728292a2b2c2d2e2f303132333435363738393a3b3c3d3e3f",
+			iv:         "1af38c2dc2b96ffdd86694092341bc04",
+			aad:        hex.EncodeToString([]byte("The second principle of Auguste Kerckhoffs")),
+			plaintext:  hex.EncodeToString([]byte("A cipher system must not be required to be secret, and it must be able to fall into the hands of the enemy without inconvenience")),
+			ciphertext: "4affaaadb78c31c5da4b1b590d10ffbd3dd8d5d302423526912da037ecbcc7bd822c301dd67c373bccb584ad3e9279c2e6d12a1374b77f077553df829410446b36ebd97066296ae6427ea75c2e0846a11a09ccf5370dc80bfecbad28c73f09b3a3b75e662a2594410ae496b2e2e6609e31e6e02cc837f053d21f37ff4f51950bbe2638d09dd7a4930930806d0703b1f6",
+			tag:        "4dd3b4c088a7f45c216839645b2012bf2e6269a8c56a816dbc1b267761955bc5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decode := func(s string) []byte {
+				b, err := hex.DecodeString(s)
+				if err != nil {
+					t.Fatalf("invalid test vector: %v", err)
+				}
+				return b
+			}
+			key, iv, aad, ciphertext, tag := decode(tt.key), decode(tt.iv), decode(tt.aad), decode(tt.ciphertext), decode(tt.tag)
+
+			if got := cbcHMACTag(tt.enc, key[:len(key)/2], aad, iv, ciphertext); !bytes.Equal(got, tag) {
+				t.Errorf("cbcHMACTag() = %x, want %s", got, tt.tag)
+			}
+			got, err := jweDecryptContent(tt.enc, key, iv, ciphertext, tag, aad)
+			if err != nil {
+				t.Fatalf("jweDecryptContent() error = %v", err)
+			}
+			if hex.EncodeToString(got) != tt.plaintext {
+				t.Errorf("jweDecryptContent() = %x, want %s", got, tt.plaintext)
+			}
+		})
+	}
+}
+
+func TestDecryptJWEInterop(t *testing.T) {
+	// Tokens encrypted with lestrrat-go/jwx v2.1.6 for every supported alg and enc, with P-256 and
+	// X25519 keys for the ECDH-ES algorithms
+	data, err := os.ReadFile("testdata/jwe_interop.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fixture struct {
+		Plaintext string            `json:"plaintext"`
+		Keys      map[string]string `json:"keys"`
+		Vectors   []struct {
+			Key   string `json:"key"`
+			Alg   string `json:"alg"`
+			Enc   string `json:"enc"`
+			Token string `json:"token"`
+		} `json:"vectors"`
+	}
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		t.Fatal(err)
+	}
+
+	keys := make(map[string]any)
+	for name, keyPEM := range fixture.Keys {
+		block, _ := pem.Decode([]byte(keyPEM))
+		if keys[name], err = ParsePrivateKeyPEMBlock(block); err != nil {
+			t.Fatalf("invalid %s key: %v", name, err)
+		}
+	}
+
+	for _, v := range fixture.Vectors {
+		t.Run(v.Key+" "+v.Alg+" "+v.Enc, func(t *testing.T) {
+			got, _, err := DecryptJWE(v.Token, keys[v.Key])
+			if err != nil {
+				t.Fatalf("DecryptJWE() error = %v", err)
+			}
+			if string(got) != fixture.Plaintext {
+				t.Errorf("DecryptJWE() = %q, want %q", got, fixture.Plaintext)
+			}
+		})
+	}
+}
+
+// rsaKeyFromJWK creates an RSA private key from the base64url encoded JWK parameters
+func rsaKeyFromJWK(t *testing.T, params map[string]string) *rsa.PrivateKey {
+	t.Helper()
+	value := func(name string) *big.Int {
+		b, err := base64.RawURLEncoding.DecodeString(params[name])
+		if err != nil {
+			t.Fatalf("invalid %s: %v", name, err)
+		}
+		return new(big.Int).SetBytes(b)
+	}
+	key := &rsa.PrivateKey{
+		PublicKey: rsa.PublicKey{N: value("n"), E: int(value("e").Int64())},
+		D:         value("d"),
+		Primes:    []*big.Int{value("p"), value("q")},
+	}
+	key.Precompute()
+	if err := key.Validate(); err != nil {
+		t.Fatalf("invalid RSA key: %v", err)
+	}
+	return key
+}
@@ -0,0 +1,227 @@
+package crypto
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// JWK is a JSON Web Key (RFC 7517) holding a public key.
+type JWK struct {
+	Kty string   `json:"kty"`
+	Kid string   `json:"kid,omitempty"`
+	Use string   `json:"use,omitempty"`
+	Alg string   `json:"alg,omitempty"`
+	Crv string   `json:"crv,omitempty"`
+	X   string   `json:"x,omitempty"`
+	Y   string   `json:"y,omitempty"`
+	N   string   `json:"n,omitempty"`
+	E   string   `json:"e,omitempty"`
+	X5c []string `json:"x5c,omitempty"`
+}
+
+// JWKSet is a JSON Web Key Set (RFC 7517 section 5).
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// ParseJWKSet parses a JSON Web Key Set document.
+func ParseJWKSet(data []byte) (*JWKSet, error) {
+	var set JWKSet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse JWK set: %w", err)
+	}
+	if set.Keys == nil {
+		return nil, errors.New("JWK set has no keys member")
+	}
+	return &set, nil
+}
+
+// Lookup returns the first key in the set that matches the key ID, the algorithm and the intended use.
+// Empty arguments match any key, and keys that do not declare an algorithm or use match any value.
+func (s *JWKSet) Lookup(kid, alg, use string) (*JWK, error) {
+	for i := range s.Keys {
+		k := &s.Keys[i]
+		if kid != "" && k.Kid != kid {
+			continue
+		}
+		if use != "" && k.Use != "" && k.Use != use {
+			continue
+		}
+		if alg != "" {
+			if k.Alg != "" && k.Alg != alg {
+				continue
+			}
+			if !k.supportsAlgorithm(alg) {
+				continue
+			}
+		}
+		return k, nil
+	}
+	return nil, fmt.Errorf("no key found for kid %q and alg %q", kid, alg)
+}
+
+// PublicKey returns the public key represented by the JWK.
+// RSA keys are returned as *rsa.PublicKey, EC keys as *ecdsa.PublicKey,
+// Ed25519 keys as ed25519.PublicKey and X25519 keys as *ecdh.PublicKey.
+func (k *JWK) PublicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA modulus: %w", err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA exponent: %w", err)
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("RSA exponent is too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		curve, err := ellipticCurve(k.Crv)
+		if err != nil {
+			return nil, err
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC x coordinate: %w", err)
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC y coordinate: %w", err)
+		}
+		pub := &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+		// Validate that the point is on the curve
+		if _, err := pub.ECDH(); err != nil {
+			return nil, fmt.Errorf("invalid EC public key: %w", err)
+		}
+		return pub, nil
+	case "OKP":
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid OKP public key: %w", err)
+		}
+		switch k.Crv {
+		case "Ed25519":
+			if len(x) != ed25519.PublicKeySize {
+				return nil, errors.New("invalid Ed25519 public key size")
+			}
+			return ed25519.PublicKey(x), nil
+		case "X25519":
+			return ecdh.X25519().NewPublicKey(x)
+		default:
+			return nil, fmt.Errorf("unsupported OKP curve: %s", k.Crv)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported key type: %s", k.Kty)
+	}
+}
+
+// NewJWK creates a JWK from an RSA, ECDSA or Ed25519 public key.
+func NewJWK(publicKey any, kid string) (*JWK, error) {
+	switch k := publicKey.(type) {
+	case *rsa.PublicKey:
+		return &JWK{
+			Kty: "RSA",
+			Kid: kid,
+			N:   base64.RawURLEncoding.EncodeToString(k.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+		}, nil
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		return &JWK{
+			Kty: "EC",
+			Kid: kid,
+			Crv: k.Curve.Params().Name,
+			X:   base64.RawURLEncoding.EncodeToString(k.X.FillBytes(make([]byte, size))),
+			Y:   base64.RawURLEncoding.EncodeToString(k.Y.FillBytes(make([]byte, size))),
+		}, nil
+	case ed25519.PublicKey:
+		return &JWK{
+			Kty: "OKP",
+			Kid: kid,
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(k),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type: %T", publicKey)
+	}
+}
+
+// Thumbprint computes the base64url encoded SHA-256 JWK thumbprint (RFC 7638), as used in
+// the jkt confirmation method of DPoP-bound tokens.
+func (k *JWK) Thumbprint() (string, error) {
+	// The required members in lexicographic order, json.Marshal sorts map keys
+	var members map[string]string
+	switch k.Kty {
+	case "RSA":
+		members = map[string]string{"e": k.E, "kty": k.Kty, "n": k.N}
+	case "EC":
+		members = map[string]string{"crv": k.Crv, "kty": k.Kty, "x": k.X, "y": k.Y}
+	case "OKP":
+		members = map[string]string{"crv": k.Crv, "kty": k.Kty, "x": k.X}
+	default:
+		return "", fmt.Errorf("unsupported key type: %s", k.Kty)
+	}
+	data, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// supportsAlgorithm reports whether the key type can be used with the JWS or JWE algorithm.
+func (k *JWK) supportsAlgorithm(alg string) bool {
+	switch {
+	case strings.HasPrefix(alg, "RS"), strings.HasPrefix(alg, "PS"), strings.HasPrefix(alg, "RSA"):
+		return k.Kty == "RSA"
+	case alg == "ES256":
+		return k.Kty == "EC" && k.Crv == "P-256"
+	case alg == "ES384":
+		return k.Kty == "EC" && k.Crv == "P-384"
+	case alg == "ES512":
+		return k.Kty == "EC" && k.Crv == "P-521"
+	case alg == "EdDSA":
+		return k.Kty == "OKP" && k.Crv == "Ed25519"
+	case strings.HasPrefix(alg, "ECDH-ES"):
+		return k.Kty == "EC" || (k.Kty == "OKP" && k.Crv == "X25519")
+	default:
+		return false
+	}
+}
+
+func ellipticCurve(crv string) (elliptic.Curve, error) {
+	switch crv {
+	case "P-256":
+		return elliptic.P256(), nil
+	case "P-384":
+		return elliptic.P384(), nil
+	case "P-521":
+		return elliptic.P521(), nil
+	default:
+		return nil, fmt.Errorf("unsupported EC curve: %s", crv)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	if s == "" {
+		return nil, errors.New("value is empty")
+	}
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,157 @@
+package crypto
+
+import (
+	stdcrypto "crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+)
+
+func TestNewJWKPublicKeyRoundTrip(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	edPub, _, _ := ed25519.GenerateKey(rand.Reader)
+
+	tests := []struct {
+		name      string
+		publicKey any
+		wantKty   string
+	}{
+		{"rsa", &rsaKey.PublicKey, "RSA"},
+		{"ecdsa", &ecKey.PublicKey, "EC"},
+		{"ed25519", edPub, "OKP"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jwk, err := NewJWK(tt.publicKey, "kid-1")
+			if err != nil {
+				t.Fatalf("NewJWK() error = %v", err)
+			}
+			if jwk.Kty != tt.wantKty || jwk.Kid != "kid-1" {
+				t.Errorf("NewJWK() kty = %q kid = %q, want %q and %q", jwk.Kty, jwk.Kid, tt.wantKty, "kid-1")
+			}
+			got, err := jwk.PublicKey()
+			if err != nil {
+				t.Fatalf("PublicKey() error = %v", err)
+			}
+			type equaler interface {
+				Equal(x stdcrypto.PublicKey) bool
+			}
+			if !got.(equaler).Equal(tt.publicKey) {
+				t.Errorf("PublicKey() = %v, want %v", got, tt.publicKey)
+			}
+		})
+	}
+}
+
+func TestNewJWKUnsupportedKey(t *testing.T) {
+	if _, err := NewJWK("not a key", ""); err == nil {
+		t.Error("NewJWK() error = nil, want error")
+	}
+}
+
+func TestJWKPublicKeyError(t *testing.T) {
+	tests := []struct {
+		name string
+		jwk  JWK
+	}{
+		{"unsupported key type", JWK{Kty: "oct"}},
+		{"missing rsa modulus", JWK{Kty: "RSA", E: "AQAB"}},
+		{"unsupported curve", JWK{Kty: "EC", Crv: "P-192", X: "AA", Y: "AA"}},
+		{"point not on curve", JWK{Kty: "EC", Crv: "P-256", X: "AQ", Y: "AQ"}},
+		{"invalid ed25519 size", JWK{Kty: "OKP", Crv: "Ed25519", X: "AQID"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.jwk.PublicKey(); err == nil {
+				t.Error("PublicKey() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestParseJWKSet(t *testing.T) {
+	set, err := ParseJWKSet([]byte(`{"keys":[{"kty":"RSA","kid":"a","n":"AQAB","e":"AQAB"}]}`))
+	if err != nil {
+		t.Fatalf("ParseJWKSet() error = %v", err)
+	}
+	if len(set.Keys) != 1 || set.Keys[0].Kid != "a" {
+		t.Errorf("ParseJWKSet() = %+v, want one key with kid a", set)
+	}
+
+	for _, data := range []string{`not json`, `{}`} {
+		if _, err := ParseJWKSet([]byte(data)); err == nil {
+			t.Errorf("ParseJWKSet(%q) error = nil, want error", data)
+		}
+	}
+}
+
+func TestJWKSetLookup(t *testing.T) {
+	set := &JWKSet{Keys: []JWK{
+		{Kty: "RSA", Kid: "enc-key", Use: "enc"},
+		{Kty: "RSA", Kid: "rsa-key", Use: "sig", Alg: "RS256"},
+		{Kty: "EC", Kid: "ec-key", Crv: "P-256"},
+		{Kty: "OKP", Kid: "ed-key", Crv: "Ed25519"},
+	}}
+
+	tests := []struct {
+		name    string
+		kid     string
+		alg     string
+		use     string
+		wantKid string
+		wantErr bool
+	}{
+		{"by kid", "ec-key", "", "", "ec-key", false},
+		{"by kid and alg", "rsa-key", "RS256", "sig", "rsa-key", false},
+		{"by alg without kid", "", "ES256", "sig", "ec-key", false},
+		{"eddsa without kid", "", "EdDSA", "sig", "ed-key", false},
+		{"use filters encryption keys", "", "RS256", "sig", "rsa-key", false},
+		{"encryption key by use", "", "RSA-OAEP-256", "enc", "enc-key", false},
+		{"alg mismatch with key alg", "rsa-key", "PS256", "", "", true},
+		{"curve mismatch", "ec-key", "ES384", "", "", true},
+		{"unknown kid", "unknown", "", "", "", true},
+		{"unsupported alg", "", "HS256", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := set.Lookup(tt.kid, tt.alg, tt.use)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Lookup() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got.Kid != tt.wantKid {
+				t.Errorf("Lookup() kid = %q, want %q", got.Kid, tt.wantKid)
+			}
+		})
+	}
+}
+
+func TestJWKThumbprint(t *testing.T) {
+	// Example from RFC 7638 section 3.1
+	rsaJWK := JWK{
+		Kty: "RSA",
+		Kid: "2011-04-29",
+		Alg: "RS256",
+		E:   "AQAB",
+		N: "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMs" +
+			"tn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91Cb" +
+			"OpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+	}
+	got, err := rsaJWK.Thumbprint()
+	if err != nil {
+		t.Fatalf("Thumbprint() error = %v", err)
+	}
+	if want := "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"; got != want {
+		t.Errorf("Thumbprint() = %q, want %q", got, want)
+	}
+
+	if _, err := (&JWK{Kty: "oct"}).Thumbprint(); err == nil {
+		t.Error("Thumbprint() error = nil, want error")
+	}
+}
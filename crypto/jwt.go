@@ -0,0 +1,84 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// asymmetricAlgorithms lists the JWS algorithms accepted when verifying tokens against a JWK set.
+// "none" and the HMAC algorithms are deliberately excluded.
+var asymmetricAlgorithms = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+}
+
+// VerifyJWTSignature parses a compact JWS and verifies its signature with the matching key from the set.
+// Only the signature is verified, the registered claims must be validated by the caller.
+// The key used for verification is returned alongside the token.
+func VerifyJWTSignature(token string, keys *JWKSet) (*jwt.Token, *JWK, error) {
+	var usedKey *JWK
+	parser := jwt.NewParser(
+		jwt.WithValidMethods(asymmetricAlgorithms),
+		jwt.WithoutClaimsValidation(),
+	)
+	parsed, err := parser.Parse(token, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		alg, _ := t.Header["alg"].(string)
+		key, err := keys.Lookup(kid, alg, "sig")
+		if err != nil {
+			return nil, err
+		}
+		usedKey = key
+		return key.PublicKey()
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("signature verification failed: %w", err)
+	}
+	return parsed, usedKey, nil
+}
+
+// VerifyJWTHMAC parses a compact JWS signed with a shared secret, such as an ID token
+// signed with the client secret, and verifies its signature. Claims are not validated.
+func VerifyJWTHMAC(token string, secret []byte) (*jwt.Token, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("signature verification failed: no secret available")
+	}
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{"HS256", "HS384", "HS512"}),
+		jwt.WithoutClaimsValidation(),
+	)
+	parsed, err := parser.Parse(token, func(*jwt.Token) (any, error) {
+		return secret, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("signature verification failed: %w", err)
+	}
+	return parsed, nil
+}
+
+// TokenHash computes the at_hash, c_hash or s_hash value of a token for the JWS algorithm of an ID token:
+// the base64url encoded left-most half of the hash of the value (OIDC Core section 3.1.3.6).
+func TokenHash(value, alg string) (string, error) {
+	var h hash.Hash
+	switch alg {
+	case "RS256", "PS256", "ES256", "HS256":
+		h = sha256.New()
+	case "RS384", "PS384", "ES384", "HS384":
+		h = sha512.New384()
+	case "RS512", "PS512", "ES512", "HS512", "EdDSA":
+		h = sha512.New()
+	default:
+		return "", fmt.Errorf("unsupported algorithm for token hash: %q", alg)
+	}
+	h.Write([]byte(value))
+	sum := h.Sum(nil)
+	return base64.RawURLEncoding.EncodeToString(sum[:len(sum)/2]), nil
+}
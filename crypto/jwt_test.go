@@ -0,0 +1,130 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestVerifyJWTSignature(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	rsaJWK, _ := NewJWK(&rsaKey.PublicKey, "rsa")
+	ecJWK, _ := NewJWK(&ecKey.PublicKey, "ec")
+	keys := &JWKSet{Keys: []JWK{*rsaJWK, *ecJWK}}
+
+	sign := func(method jwt.SigningMethod, kid string, key any) string {
+		token := jwt.NewWithClaims(method, jwt.MapClaims{"sub": "alice"})
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatalf("failed to sign token: %v", err)
+		}
+		return signed
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantKid string
+		wantErr bool
+	}{
+		{"rsa with kid", sign(jwt.SigningMethodRS256, "rsa", rsaKey), "rsa", false},
+		{"ec without kid", sign(jwt.SigningMethodES256, "", ecKey), "ec", false},
+		{"wrong key", sign(jwt.SigningMethodRS256, "rsa", otherKey), "", true},
+		{"unknown kid", sign(jwt.SigningMethodRS256, "unknown", rsaKey), "", true},
+		{"hmac rejected", sign(jwt.SigningMethodHS256, "", []byte("secret")), "", true},
+		{"none rejected", sign(jwt.SigningMethodNone, "", jwt.UnsafeAllowNoneSignatureType), "", true},
+		{"not a jwt", "not-a-jwt", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, key, err := VerifyJWTSignature(tt.token, keys)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifyJWTSignature() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if key.Kid != tt.wantKid {
+				t.Errorf("VerifyJWTSignature() key kid = %q, want %q", key.Kid, tt.wantKid)
+			}
+			if sub, _ := token.Claims.(jwt.MapClaims)["sub"].(string); sub != "alice" {
+				t.Errorf("VerifyJWTSignature() sub = %q, want alice", sub)
+			}
+		})
+	}
+}
+
+func TestVerifyJWTHMAC(t *testing.T) {
+	sign := func(method jwt.SigningMethod, key any) string {
+		signed, err := jwt.NewWithClaims(method, jwt.MapClaims{"sub": "alice"}).SignedString(key)
+		if err != nil {
+			t.Fatalf("failed to sign token: %v", err)
+		}
+		return signed
+	}
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	tests := []struct {
+		name    string
+		token   string
+		secret  []byte
+		wantErr bool
+	}{
+		{"valid hs256", sign(jwt.SigningMethodHS256, []byte("secret")), []byte("secret"), false},
+		{"valid hs512", sign(jwt.SigningMethodHS512, []byte("secret")), []byte("secret"), false},
+		{"wrong secret", sign(jwt.SigningMethodHS256, []byte("secret")), []byte("other"), true},
+		{"empty secret", sign(jwt.SigningMethodHS256, []byte("secret")), nil, true},
+		{"asymmetric rejected", sign(jwt.SigningMethodRS256, rsaKey), []byte("secret"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := VerifyJWTHMAC(tt.token, tt.secret)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyJWTHMAC() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTokenHash(t *testing.T) {
+	// Example values from OIDC Core appendix A.3 and A.4
+	tests := []struct {
+		name    string
+		value   string
+		alg     string
+		want    string
+		wantErr bool
+	}{
+		{"at_hash RS256", "jHkWEdUXMU1BwAsC4vtUsZwnNvTIxEl0z9K3vx5KF0Y", "RS256", "77QmUPtjPfzWtF2AnpK9RQ", false},
+		{"c_hash RS256", "Qcb0Orv1zh30vL1MPRsbm-diHiMwcLyZvn1arpZv-Jxf_11jnpEX3Tgfvk", "RS256", "LDktKdoQak3Pk0cnXxCltA", false},
+		{"sha512 for EdDSA", "token", "EdDSA", "", false},
+		{"unsupported alg", "token", "none", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TokenHash(tt.value, tt.alg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TokenHash() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.want != "" && got != tt.want {
+				t.Errorf("TokenHash() = %q, want %q", got, tt.want)
+			}
+			if !tt.wantErr && tt.alg == "EdDSA" && len(got) != 43 {
+				t.Errorf("TokenHash() length = %d, want 43", len(got))
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+)
+
+// GenerateRandomValue returns a base64url encoded value with 256 bits of entropy,
+// suitable for the state and nonce parameters of an authorization request.
+func GenerateRandomValue() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", fmt.Errorf("failed to read from random to generate value %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
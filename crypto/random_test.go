@@ -0,0 +1,28 @@
+package crypto
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestGenerateRandomValue(t *testing.T) {
+	value, err := GenerateRandomValue()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 32 bytes base64url-encoded is 43 chars (no padding)
+	if len(value) != 43 {
+		t.Errorf("expected value length 43, got %d", len(value))
+	}
+	if _, err := base64.RawURLEncoding.DecodeString(value); err != nil {
+		t.Errorf("value is not valid base64url: %v", err)
+	}
+
+	other, err := GenerateRandomValue()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value == other {
+		t.Error("expected two generated values to differ")
+	}
+}
@@ -0,0 +1,106 @@
+package crypto
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// RequestObjectType is the typ header of request objects (RFC 9101 section 10.8)
+const RequestObjectType = "oauth-authz-req+jwt"
+
+// RequestObjectLifetime is how long a request object is valid after it has been issued
+const RequestObjectLifetime = 5 * time.Minute
+
+// requestObjectJSONParams are the authorization request parameters whose values are JSON,
+// and which are therefore sent as JSON in the claims of a request object
+var requestObjectJSONParams = []string{"claims", "authorization_details"}
+
+// NewRequestObject packs the authorization request parameters into a request object as described
+// in RFC 9101 section 4, with the client as issuer and the authorization server as audience.
+// The key is a private key, or the client secret for HMAC algorithms. If alg is empty, the
+// algorithm is derived from the key.
+func NewRequestObject(params url.Values, clientID, audience string, key any, kid, alg string) (string, error) {
+	claims := jwt.MapClaims{}
+	for name, values := range params {
+		if len(values) > 1 {
+			// Repeated parameters such as resource (RFC 8707 section 2) are sent as an array
+			claim := make([]any, len(values))
+			for i, value := range values {
+				claim[i] = requestObjectClaim(name, value)
+			}
+			claims[name] = claim
+		} else {
+			claims[name] = requestObjectClaim(name, params.Get(name))
+		}
+	}
+
+	jti, err := GenerateRandomValue()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	claims["iss"] = clientID
+	claims["aud"] = audience
+	claims["jti"] = jti
+	claims["iat"] = now.Unix()
+	claims["nbf"] = now.Unix()
+	claims["exp"] = now.Add(RequestObjectLifetime).Unix()
+
+	signed, err := signJWT(claims, key, kid, alg, RequestObjectType)
+	if err != nil {
+		return "", fmt.Errorf("error signing request object: %w", err)
+	}
+	return signed, nil
+}
+
+// requestObjectClaim returns the claim value of an authorization request parameter.
+// max_age is a number and JSON valued parameters are embedded as JSON, all other values are strings.
+func requestObjectClaim(name, value string) any {
+	if name == "max_age" {
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	}
+	for _, jsonParam := range requestObjectJSONParams {
+		if name == jsonParam && json.Valid([]byte(value)) {
+			return json.RawMessage(value)
+		}
+	}
+	return value
+}
+
+// EncryptRequestObject encrypts a signed request object to an encryption key of the authorization
+// server (RFC 9101 section 6.1). The key management algorithm is the alg of the key, or else derived
+// from its type, and the content is encrypted with A128CBC-HS256, the default of OpenID Connect.
+func EncryptRequestObject(requestObject string, key *JWK) (string, error) {
+	alg := key.Alg
+	if alg == "" {
+		switch key.Kty {
+		case "RSA":
+			alg = JWEAlgRSAOAEP256
+		case "EC", "OKP":
+			alg = JWEAlgECDHES
+		default:
+			return "", fmt.Errorf("unsupported encryption key type %q", key.Kty)
+		}
+	}
+	publicKey, err := key.PublicKey()
+	if err != nil {
+		return "", err
+	}
+
+	headers := map[string]any{"cty": "JWT"}
+	if key.Kid != "" {
+		headers["kid"] = key.Kid
+	}
+	encrypted, err := EncryptJWE([]byte(requestObject), publicKey, alg, JWEEncA128CBCHS256, headers)
+	if err != nil {
+		return "", fmt.Errorf("error encrypting request object: %w", err)
+	}
+	return encrypted, nil
+}
@@ -0,0 +1,106 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/url"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestNewRequestObject(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	params := url.Values{}
+	params.Set("client_id", "client-id")
+	params.Set("response_type", "code")
+	params.Set("scope", "openid")
+	params.Set("max_age", "600")
+	params.Set("claims", `{"id_token":{"acr":null}}`)
+	params.Add("resource", "https://api.example.com")
+	params.Add("resource", "https://files.example.com")
+
+	requestObject, err := NewRequestObject(params, "client-id", "https://example.com", key, "key-1", "")
+	if err != nil {
+		t.Fatalf("NewRequestObject() error = %v", err)
+	}
+	token, err := jwt.Parse(requestObject, func(*jwt.Token) (any, error) { return &key.PublicKey, nil })
+	if err != nil {
+		t.Fatalf("failed to verify request object: %v", err)
+	}
+
+	if token.Header["typ"] != RequestObjectType {
+		t.Errorf("typ = %v, want %v", token.Header["typ"], RequestObjectType)
+	}
+	if token.Header["kid"] != "key-1" {
+		t.Errorf("kid = %v, want key-1", token.Header["kid"])
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	for name, want := range map[string]any{
+		"iss":           "client-id",
+		"aud":           "https://example.com",
+		"client_id":     "client-id",
+		"response_type": "code",
+		"scope":         "openid",
+		"max_age":       float64(600),
+	} {
+		if claims[name] != want {
+			t.Errorf("%s = %v, want %v", name, claims[name], want)
+		}
+	}
+	if resources, _ := claims["resource"].([]any); len(resources) != 2 || resources[0] != "https://api.example.com" || resources[1] != "https://files.example.com" {
+		t.Errorf("resource = %v, want both resources as an array", claims["resource"])
+	}
+	if _, ok := claims["claims"].(map[string]any); !ok {
+		t.Errorf("claims = %v, want a JSON object", claims["claims"])
+	}
+	for _, name := range []string{"jti", "iat", "nbf", "exp"} {
+		if claims[name] == nil {
+			t.Errorf("%s is missing", name)
+		}
+	}
+}
+
+func TestEncryptRequestObject(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	tests := []struct {
+		name       string
+		privateKey any
+		publicKey  any
+		wantAlg    string
+	}{
+		{"rsa", rsaKey, &rsaKey.PublicKey, JWEAlgRSAOAEP256},
+		{"ecdsa", ecKey, &ecKey.PublicKey, JWEAlgECDHES},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jwk, err := NewJWK(tt.publicKey, "enc-1")
+			if err != nil {
+				t.Fatalf("NewJWK() error = %v", err)
+			}
+			encrypted, err := EncryptRequestObject("header.payload.signature", jwk)
+			if err != nil {
+				t.Fatalf("EncryptRequestObject() error = %v", err)
+			}
+			if !IsJWE(encrypted) {
+				t.Fatalf("EncryptRequestObject() = %q, want a JWE", encrypted)
+			}
+			plaintext, cty, err := DecryptJWE(encrypted, tt.privateKey)
+			if err != nil {
+				t.Fatalf("DecryptJWE() error = %v", err)
+			}
+			if string(plaintext) != "header.payload.signature" || cty != "JWT" {
+				t.Errorf("DecryptJWE() = %q, %q", plaintext, cty)
+			}
+		})
+	}
+
+	if _, err := EncryptRequestObject("header.payload.signature", &JWK{Kty: "oct"}); err == nil {
+		t.Error("EncryptRequestObject() with an unsupported key type succeeded, want error")
+	}
+}
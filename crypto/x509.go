@@ -0,0 +1,13 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// CertificateThumbprint returns the base64url encoded SHA-256 hash of a DER encoded certificate,
+// as used by the x5t#S256 confirmation method of certificate-bound tokens (RFC 8705 section 3.1)
+func CertificateThumbprint(der []byte) string {
+	hash := sha256.Sum256(der)
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
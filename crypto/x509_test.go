@@ -0,0 +1,10 @@
+package crypto
+
+import "testing"
+
+func TestCertificateThumbprint(t *testing.T) {
+	// SHA-256 of empty input
+	if got, want := CertificateThumbprint([]byte{}), "47DEQpj8HBSa-_TImW-5JCeuQeRkm5NMpJWZG3hSuFU"; got != want {
+		t.Errorf("CertificateThumbprint() = %q, want %q", got, want)
+	}
+}
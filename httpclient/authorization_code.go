@@ -13,30 +13,48 @@ import (
 )
 
 type AuthorizationCodeRequest struct {
-	ClientID            string
-	RedirectURI         string
-	Scope               string
-	State               string
-	Prompt              string
-	AcrValues           string
-	LoginHint           string
-	MaxAge              string
-	UILocales           string
-	CodeChallengeMethod string
-	CodeChallenge       string
-	RequestURI          string
-	CustomArgs          *CustomArgs
+	ClientID             string
+	ResponseType         string // defaults to code
+	ResponseMode         string
+	RedirectURI          string
+	Scope                string
+	State                string
+	Nonce                string
+	Prompt               string
+	AcrValues            string
+	LoginHint            string
+	MaxAge               string
+	UILocales            string
+	AuthorizationDetails string   // JSON array of authorization details (RFC 9396)
+	Resources            []string // resource indicators (RFC 8707 section 2.1)
+	Claims               string   // claims request parameter as JSON (OpenID Connect Core section 5.5)
+	CodeChallengeMethod  string
+	CodeChallenge        string
+	RequestURI           string
+	Request              string // signed request object (RFC 9101), replaces the other parameters
+	CustomArgs           *CustomArgs
+	JARM                 *JARMVerifier // verifies JARM responses, not sent
 }
 
 type AuthorizationCodeResponse struct {
-	Code  string
-	State string
+	Code        string
+	State       string
+	Issuer      string
+	IDToken     string // tokens issued from the authorization endpoint in the implicit and hybrid flows
+	AccessToken string
+	TokenType   string
+	ExpiresIn   string
+	Scope       string
 }
 
 // CreateAuthorizationCodeRequestValues builds the authorization request URI.
 func CreateAuthorizationCodeRequestValues(req *AuthorizationCodeRequest) (*url.Values, error) {
 	values := &url.Values{}
-	values.Set("response_type", "code")
+	responseType := req.ResponseType
+	if responseType == "" {
+		responseType = "code"
+	}
+	values.Set("response_type", responseType)
 
 	// Add required parameters
 	if req.ClientID == "" {
@@ -44,13 +62,29 @@ func CreateAuthorizationCodeRequestValues(req *AuthorizationCodeRequest) (*url.V
 	}
 	values.Set("client_id", req.ClientID)
 
+	// A request object carries all parameters. OpenID Connect still requires response_type
+	// and scope outside of it, which RFC 9101 section 5 allows for compatibility.
+	if req.Request != "" {
+		if req.Scope != "" {
+			values.Set("scope", req.Scope)
+		}
+		values.Set("request", req.Request)
+		return values, nil
+	}
+
 	// Add standard params if set
 	if req.State != "" {
 		values.Set("state", req.State)
 	}
+	if req.Nonce != "" {
+		values.Set("nonce", req.Nonce)
+	}
 	if req.RedirectURI != "" {
 		values.Set("redirect_uri", req.RedirectURI)
 	}
+	if req.ResponseMode != "" {
+		values.Set("response_mode", req.ResponseMode)
+	}
 	if req.Scope != "" {
 		values.Set("scope", req.Scope)
 	}
@@ -69,6 +103,15 @@ func CreateAuthorizationCodeRequestValues(req *AuthorizationCodeRequest) (*url.V
 	if req.UILocales != "" {
 		values.Set("ui_locales", req.UILocales)
 	}
+	if req.AuthorizationDetails != "" {
+		values.Set("authorization_details", req.AuthorizationDetails)
+	}
+	if req.Claims != "" {
+		values.Set("claims", req.Claims)
+	}
+	for _, resource := range req.Resources {
+		values.Add("resource", resource)
+	}
 	if req.CodeChallengeMethod != "" {
 		values.Set("code_challenge_method", req.CodeChallengeMethod)
 	}
@@ -114,21 +157,8 @@ func (c *Client) ExecuteAuthorizationCodeRequest(ctx context.Context, endpoint s
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
 	defer cancel()
 
-	serverErrChan := make(chan error, 1)
-	go func() {
-		if err := callbackServer.Start(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			serverErrChan <- err
-		}
-	}()
-
-	// Give the server a moment to start or fail
-	select {
-	case err := <-serverErrChan:
-		return nil, fmt.Errorf("callback server failed to start: %w", err)
-	case <-ctx.Done():
-		return nil, ctx.Err()
-	case <-time.After(100 * time.Millisecond):
-		// Server started successfully
+	if err := startCallbackServer(ctx, callbackServer); err != nil {
+		return nil, err
 	}
 
 	requestValues, err := CreateAuthorizationCodeRequestValues(req)
@@ -153,12 +183,54 @@ func (c *Client) ExecuteAuthorizationCodeRequest(ctx context.Context, endpoint s
 		return nil, fmt.Errorf("callback failed: %w", err)
 	}
 
-	if callbackResp.Code == "" {
+	// A JARM response carries the authorization response parameters in the claims of a JWT
+	switch {
+	case callbackResp.Response != "":
+		if req.JARM == nil {
+			return nil, errors.New("callback returned a JARM response but it cannot be verified")
+		}
+		callbackResp, err = VerifyJARMResponse(ctx, callbackResp.Response, req.JARM)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JARM response: %w", err)
+		}
+		log.Printf("JARM response verified\n")
+	case IsJARMResponseMode(req.ResponseMode) && callbackResp.ErrorMsg == "":
+		return nil, fmt.Errorf("callback is missing the response parameter although response mode %s was requested", req.ResponseMode)
+	}
+
+	if callbackResp.Code == "" && callbackResp.IDToken == "" && callbackResp.AccessToken == "" {
 		return nil, fmt.Errorf("authorization failed with error %s and description %s", callbackResp.ErrorMsg, callbackResp.ErrorDescription)
 	}
 
 	return &AuthorizationCodeResponse{
-		Code:  callbackResp.Code,
-		State: req.State, // TODO: Return the state from the callback response
+		Code:        callbackResp.Code,
+		State:       callbackResp.State,
+		Issuer:      callbackResp.Issuer,
+		IDToken:     callbackResp.IDToken,
+		AccessToken: callbackResp.AccessToken,
+		TokenType:   callbackResp.TokenType,
+		ExpiresIn:   callbackResp.ExpiresIn,
+		Scope:       callbackResp.Scope,
 	}, nil
 }
+
+// startCallbackServer starts the callback server, which is stopped when the context is done
+func startCallbackServer(ctx context.Context, callbackServer *webflow.CallbackServer) error {
+	serverErrChan := make(chan error, 1)
+	go func() {
+		if err := callbackServer.Start(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErrChan <- err
+		}
+	}()
+
+	// Give the server a moment to start or fail
+	select {
+	case err := <-serverErrChan:
+		return fmt.Errorf("callback server failed to start: %w", err)
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(100 * time.Millisecond):
+		// Server started successfully
+		return nil
+	}
+}
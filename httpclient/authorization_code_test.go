@@ -27,18 +27,21 @@ func TestCreateAuthorizationCodeRequestValues(t *testing.T) {
 		{
 			name: "all standard fields",
 			req: &AuthorizationCodeRequest{
-				ClientID:            "test-client",
-				RedirectURI:         "https://example.com/callback",
-				Scope:               "openid profile email",
-				State:               "random-state-123",
-				Prompt:              "consent",
-				AcrValues:           "level1 level2",
-				LoginHint:           "user@example.com",
-				MaxAge:              "3600",
-				UILocales:           "en-US",
-				CodeChallengeMethod: "S256",
-				CodeChallenge:       "challenge123",
-				RequestURI:          "urn:ietf:params:oauth:request_uri:example",
+				ClientID:             "test-client",
+				RedirectURI:          "https://example.com/callback",
+				Scope:                "openid profile email",
+				State:                "random-state-123",
+				Nonce:                "random-nonce-456",
+				Prompt:               "consent",
+				AcrValues:            "level1 level2",
+				LoginHint:            "user@example.com",
+				MaxAge:               "3600",
+				UILocales:            "en-US",
+				AuthorizationDetails: `[{"type":"payment_initiation"}]`,
+				Claims:               `{"id_token":{"email":null}}`,
+				CodeChallengeMethod:  "S256",
+				CodeChallenge:        "challenge123",
+				RequestURI:           "urn:ietf:params:oauth:request_uri:example",
 			},
 			wantErr: false,
 			wantParams: map[string]string{
@@ -47,16 +50,66 @@ func TestCreateAuthorizationCodeRequestValues(t *testing.T) {
 				"redirect_uri":          "https://example.com/callback",
 				"scope":                 "openid profile email",
 				"state":                 "random-state-123",
+				"nonce":                 "random-nonce-456",
 				"prompt":                "consent",
 				"acr_values":            "level1 level2",
 				"login_hint":            "user@example.com",
 				"max_age":               "3600",
 				"ui_locales":            "en-US",
+				"authorization_details": `[{"type":"payment_initiation"}]`,
+				"claims":                `{"id_token":{"email":null}}`,
 				"code_challenge_method": "S256",
 				"code_challenge":        "challenge123",
 				"request_uri":           "urn:ietf:params:oauth:request_uri:example",
 			},
 		},
+		{
+			name: "hybrid response type with form post",
+			req: &AuthorizationCodeRequest{
+				ClientID:     "test-client",
+				ResponseType: "code id_token",
+				ResponseMode: "form_post",
+			},
+			wantErr: false,
+			wantParams: map[string]string{
+				"response_type": "code id_token",
+				"response_mode": "form_post",
+				"client_id":     "test-client",
+			},
+		},
+		{
+			name: "request object replaces the other parameters",
+			req: &AuthorizationCodeRequest{
+				ClientID: "test-client",
+				Scope:    "openid",
+				State:    "random-state-123",
+				Request:  "eyJhbGciOiJSUzI1NiJ9.e30.c2ln",
+				CustomArgs: &CustomArgs{
+					"custom_param": "custom_value",
+				},
+			},
+			wantErr: false,
+			wantParams: map[string]string{
+				"response_type": "code",
+				"client_id":     "test-client",
+				"scope":         "openid",
+				"request":       "eyJhbGciOiJSUzI1NiJ9.e30.c2ln",
+				"state":         "",
+				"custom_param":  "",
+			},
+		},
+		{
+			name: "multiple resources",
+			req: &AuthorizationCodeRequest{
+				ClientID:  "test-client",
+				Resources: []string{"https://api.example.com", "https://payments.example.com"},
+			},
+			wantErr: false,
+			wantParams: map[string]string{
+				"client_id": "test-client",
+				"resource":  "https://api.example.com",
+			},
+		},
 		{
 			name: "with custom arguments",
 			req: &AuthorizationCodeRequest{
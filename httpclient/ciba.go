@@ -0,0 +1,124 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// GrantTypeCIBA is the grant type for polling the token endpoint in CIBA (OpenID Connect CIBA Core section 10.1)
+const GrantTypeCIBA = "urn:openid:params:grant-type:ciba"
+
+// BackchannelAuthenticationRequest is an authentication request to the backchannel authentication
+// endpoint (OpenID Connect CIBA Core section 7.1). Exactly one of the hints must be set.
+type BackchannelAuthenticationRequest struct {
+	ClientID                string
+	ClientSecret            string
+	ClientAssertion         *ClientAssertion
+	AuthMethod              AuthMethod
+	Scope                   string
+	LoginHint               string
+	IDTokenHint             string
+	LoginHintToken          string
+	BindingMessage          string
+	RequestedExpiry         int    // requested lifetime of the auth_req_id in seconds, 0 to omit
+	ClientNotificationToken string // bearer token for the ping callback, only sent in ping mode
+	CustomArgs              *CustomArgs
+}
+
+type BackchannelAuthenticationResponse struct {
+	AuthReqID string `json:"auth_req_id"`
+	ExpiresIn int    `json:"expires_in"`
+	Interval  int    `json:"interval,omitempty"`
+}
+
+// ExecuteBackchannelAuthenticationRequest sends a backchannel authentication request (OpenID Connect CIBA Core section 7.1)
+func (c *Client) ExecuteBackchannelAuthenticationRequest(ctx context.Context, endpoint string, req *BackchannelAuthenticationRequest) (*Response, error) {
+	headers := make(map[string]string)
+
+	params := url.Values{}
+	params.Set("scope", req.Scope)
+	if req.LoginHint != "" {
+		params.Set("login_hint", req.LoginHint)
+	}
+	if req.IDTokenHint != "" {
+		params.Set("id_token_hint", req.IDTokenHint)
+	}
+	if req.LoginHintToken != "" {
+		params.Set("login_hint_token", req.LoginHintToken)
+	}
+	if req.BindingMessage != "" {
+		params.Set("binding_message", req.BindingMessage)
+	}
+	if req.RequestedExpiry > 0 {
+		params.Set("requested_expiry", strconv.Itoa(req.RequestedExpiry))
+	}
+	if req.ClientNotificationToken != "" {
+		params.Set("client_notification_token", req.ClientNotificationToken)
+	}
+	// Add custom args
+	if req.CustomArgs != nil {
+		for k, v := range *req.CustomArgs {
+			params.Set(k, v)
+		}
+	}
+
+	// Apply authentication method
+	if err := applyClientAuth(ctx, endpoint, params, headers, req.AuthMethod, req.ClientID, req.ClientSecret, req.ClientAssertion); err != nil {
+		return nil, err
+	}
+
+	// Execute the request
+	return c.PostForm(ctx, endpoint, params, headers)
+}
+
+// ParseBackchannelAuthenticationResponse parses the backchannel authentication response
+func ParseBackchannelAuthenticationResponse(resp *Response) (*BackchannelAuthenticationResponse, error) {
+	if !resp.IsSuccess() {
+		oauth2Err := &Error{
+			StatusCode: resp.StatusCode,
+			RawBody:    resp.String(),
+		}
+		var mapResp map[string]interface{}
+
+		if err := json.Unmarshal(resp.Body, &mapResp); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrParsingJSON, err)
+		}
+
+		// Extract standard OAuth2 error fields if present
+		if errStr, ok := mapResp["error"].(string); ok {
+			oauth2Err.ErrorType = errStr
+			if desc, ok := mapResp["error_description"].(string); ok {
+				oauth2Err.ErrorDescription = desc
+			}
+			return nil, fmt.Errorf("%w: %v", ErrOAuthError, oauth2Err)
+		}
+
+		return nil, fmt.Errorf("%w: %v", ErrHTTPFailure, oauth2Err)
+	}
+
+	var cibaResp BackchannelAuthenticationResponse
+	if err := json.Unmarshal(resp.Body, &cibaResp); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrParsingJSON, err)
+	}
+	if cibaResp.AuthReqID == "" || cibaResp.ExpiresIn <= 0 {
+		return nil, fmt.Errorf("%w: auth_req_id and expires_in are required", ErrParsingJSON)
+	}
+	return &cibaResp, nil
+}
+
+// CreateCIBATokenRequest creates a token request for the CIBA grant
+func CreateCIBATokenRequest(clientID, clientSecret string, authMethod AuthMethod, authReqID string) *TokenRequest {
+	params := url.Values{}
+	params.Set("auth_req_id", authReqID)
+
+	return &TokenRequest{
+		GrantType:    GrantTypeCIBA,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		AuthMethod:   authMethod,
+		Params:       params,
+	}
+}
@@ -0,0 +1,152 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestExecuteBackchannelAuthenticationRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        *BackchannelAuthenticationRequest
+		wantParams map[string]string
+		wantAbsent []string
+	}{
+		{
+			name: "poll mode with login hint",
+			req: &BackchannelAuthenticationRequest{
+				ClientID:        "test-client",
+				ClientSecret:    "test-secret",
+				AuthMethod:      AuthMethodPost,
+				Scope:           "openid",
+				LoginHint:       "alice@example.com",
+				BindingMessage:  "W4SCT",
+				RequestedExpiry: 120,
+			},
+			wantParams: map[string]string{
+				"scope":            "openid",
+				"login_hint":       "alice@example.com",
+				"binding_message":  "W4SCT",
+				"requested_expiry": "120",
+				"client_id":        "test-client",
+				"client_secret":    "test-secret",
+			},
+			wantAbsent: []string{"id_token_hint", "login_hint_token", "client_notification_token"},
+		},
+		{
+			name: "ping mode with id token hint",
+			req: &BackchannelAuthenticationRequest{
+				ClientID:                "test-client",
+				ClientSecret:            "test-secret",
+				AuthMethod:              AuthMethodBasic,
+				Scope:                   "openid profile",
+				IDTokenHint:             "id-token",
+				ClientNotificationToken: "notification-token",
+				CustomArgs:              &CustomArgs{"acr_values": "urn:mfa"},
+			},
+			wantParams: map[string]string{
+				"scope":                     "openid profile",
+				"id_token_hint":             "id-token",
+				"client_notification_token": "notification-token",
+				"acr_values":                "urn:mfa",
+			},
+			wantAbsent: []string{"login_hint", "requested_expiry", "client_secret"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				for key, want := range tt.wantParams {
+					if got := r.FormValue(key); got != want {
+						t.Errorf("got param %s=%q, want %q", key, got, want)
+					}
+				}
+				for _, key := range tt.wantAbsent {
+					if r.Form.Has(key) {
+						t.Errorf("got param %s, want it absent", key)
+					}
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"auth_req_id":"1c266114","expires_in":120}`))
+			}))
+			defer ts.Close()
+
+			client := NewClient(nil)
+			resp, err := client.ExecuteBackchannelAuthenticationRequest(context.Background(), ts.URL, tt.req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !resp.IsSuccess() {
+				t.Errorf("Expected successful response, got status %d", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestParseBackchannelAuthenticationResponse(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		wantErrMsg string
+		wantData   *BackchannelAuthenticationResponse
+	}{
+		{
+			name:       "successful response",
+			statusCode: 200,
+			body:       `{"auth_req_id":"1c266114-a1be-4252-8ad1-04986c5b9ac1","expires_in":120,"interval":2}`,
+			wantData: &BackchannelAuthenticationResponse{
+				AuthReqID: "1c266114-a1be-4252-8ad1-04986c5b9ac1",
+				ExpiresIn: 120,
+				Interval:  2,
+			},
+		},
+		{
+			name:       "missing expires_in",
+			statusCode: 200,
+			body:       `{"auth_req_id":"1c266114"}`,
+			wantErrMsg: "json parsing error",
+		},
+		{
+			name:       "oauth2 error response",
+			statusCode: 400,
+			body:       `{"error":"unknown_user_id","error_description":"No such user"}`,
+			wantErrMsg: "oauth protocol error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cibaResp, err := ParseBackchannelAuthenticationResponse(&Response{StatusCode: tt.statusCode, Body: []byte(tt.body)})
+			if tt.wantErrMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrMsg) {
+					t.Errorf("got error %v, want error containing %q", err, tt.wantErrMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if *cibaResp != *tt.wantData {
+				t.Errorf("got %+v, want %+v", *cibaResp, *tt.wantData)
+			}
+		})
+	}
+}
+
+func TestCreateCIBATokenRequest(t *testing.T) {
+	req := CreateCIBATokenRequest("client", "secret", AuthMethodBasic, "1c266114")
+
+	if req.GrantType != GrantTypeCIBA {
+		t.Errorf("got GrantType %q, want %q", req.GrantType, GrantTypeCIBA)
+	}
+	if got := req.Params.Get("auth_req_id"); got != "1c266114" {
+		t.Errorf("got auth_req_id %q, want %q", got, "1c266114")
+	}
+}
@@ -10,19 +10,25 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 )
 
 // Config holds HTTP client configuration.
 type Config struct {
-	SkipTLSVerify bool              // Skip TLS verification for HTTP requests
-	Timeout       time.Duration     // Timeout for HTTP requests
-	Transport     http.RoundTripper // Custom HTTP transport, if any
+	SkipTLSVerify     bool              // Skip TLS verification for HTTP requests
+	ClientCertificate *tls.Certificate  // Client certificate for mutual TLS, if any
+	Timeout           time.Duration     // Timeout for HTTP requests
+	Transport         http.RoundTripper // Custom HTTP transport, if any
 }
 
 // Client is a wrapper around http.Client with utility methods
 type Client struct {
-	client *http.Client
+	client     *http.Client
+	clientCert *tls.Certificate
+
+	mu         sync.Mutex
+	dpopNonces map[string]string // latest DPoP-Nonce of each server, by origin
 }
 
 // Response represents an HTTP response with convenience methods
@@ -44,21 +50,36 @@ func NewClient(cfg *Config) *Client {
 		cfg.Timeout = 10 * time.Second
 	}
 
+	c := &Client{clientCert: cfg.ClientCertificate}
+
 	transport := cfg.Transport
 	if transport == nil {
 		transport = &http.Transport{
 			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: cfg.SkipTLSVerify,
+				InsecureSkipVerify:   cfg.SkipTLSVerify,
+				GetClientCertificate: c.getClientCertificate,
 			},
 		}
 	}
 
-	return &Client{
-		client: &http.Client{
-			Transport: transport,
-			Timeout:   cfg.Timeout,
-		},
+	c.client = &http.Client{
+		Transport: transport,
+		Timeout:   cfg.Timeout,
 	}
+	return c
+}
+
+// SetClientCertificate sets the certificate presented when a server requests mutual TLS (RFC 8705)
+func (c *Client) SetClientCertificate(cert *tls.Certificate) {
+	c.clientCert = cert
+}
+
+func (c *Client) getClientCertificate(_ *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	if c.clientCert == nil {
+		// An empty certificate continues the handshake without client authentication
+		return &tls.Certificate{}, nil
+	}
+	return c.clientCert, nil
 }
 
 // Do performs an HTTP request and handles response processing
@@ -132,6 +153,26 @@ func (c *Client) PostJSON(ctx context.Context, url string, data interface{}, hea
 	return c.Post(ctx, url, bytes.NewReader(jsonData), headers)
 }
 
+// PutJSON sends a JSON PUT request
+func (c *Client) PutJSON(ctx context.Context, url string, data interface{}, headers map[string]string) (*Response, error) {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	if headers == nil {
+		headers = make(map[string]string)
+	}
+	headers["Content-Type"] = "application/json"
+
+	return c.Do(ctx, http.MethodPut, url, bytes.NewReader(jsonData), headers)
+}
+
+// Delete performs an HTTP DELETE request
+func (c *Client) Delete(ctx context.Context, url string, headers map[string]string) (*Response, error) {
+	return c.Do(ctx, http.MethodDelete, url, nil, headers)
+}
+
 // JSON unmarshals the response body into the provided value
 func (r *Response) JSON(v interface{}) error {
 	return json.Unmarshal(r.Body, v)
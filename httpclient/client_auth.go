@@ -0,0 +1,107 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/jentz/oidc-cli/crypto"
+)
+
+// ClientAssertion holds the parameters for signing JWT client assertions
+type ClientAssertion struct {
+	PrivateKey any    // signing key for private_key_jwt, client_secret_jwt uses the client secret
+	KeyID      string // not set for client_secret_jwt
+	Algorithm  string // derived from the key if empty, HS256 for client_secret_jwt
+	Audience   string // defaults to the endpoint the request is sent to
+
+	// Provider returns an externally issued assertion, such as a federated workload identity
+	// token, to send instead of signing one. It is called for every request.
+	Provider func(ctx context.Context) (string, error)
+}
+
+// applyClientAuth authenticates the client to an endpoint, either through the Authorization
+// header or by adding the client credentials to the request parameters
+func applyClientAuth(ctx context.Context, endpoint string, params url.Values, headers map[string]string, method AuthMethod, clientID, clientSecret string, assertion *ClientAssertion) error {
+	switch method {
+	case AuthMethodBasic:
+		// Use HTTP Basic Auth
+		auth := base64.StdEncoding.EncodeToString([]byte(clientID + ":" + clientSecret))
+		headers["Authorization"] = "Basic " + auth
+	case AuthMethodPost:
+		// Include credentials in request body
+		params.Set("client_id", clientID)
+		if clientSecret != "" {
+			params.Set("client_secret", clientSecret)
+		}
+	case AuthMethodSecretJWT:
+		// Sign a client assertion with the client secret (OIDC Core section 9)
+		if clientSecret == "" {
+			return errors.New("client_secret_jwt requires a client secret")
+		}
+		var secretAssertion ClientAssertion
+		if assertion != nil {
+			secretAssertion = *assertion
+		}
+		if secretAssertion.Algorithm == "" {
+			secretAssertion.Algorithm = "HS256"
+		}
+		if !strings.HasPrefix(secretAssertion.Algorithm, "HS") {
+			return fmt.Errorf("client_secret_jwt requires an HMAC algorithm, got %q", secretAssertion.Algorithm)
+		}
+		secretAssertion.PrivateKey = []byte(clientSecret)
+		secretAssertion.KeyID = ""
+		return setClientAssertion(endpoint, params, clientID, &secretAssertion)
+	case AuthMethodPrivateKeyJWT:
+		if assertion != nil && assertion.Provider != nil {
+			return setProvidedClientAssertion(ctx, params, clientID, assertion.Provider)
+		}
+		// Sign a client assertion with the private key (RFC 7523 section 2.2)
+		if assertion == nil || assertion.PrivateKey == nil {
+			return errors.New("private_key_jwt requires a private key")
+		}
+		return setClientAssertion(endpoint, params, clientID, assertion)
+	case AuthMethodTLSClientAuth, AuthMethodSelfSignedTLSClientAuth:
+		// The client authenticates with its certificate in the TLS handshake
+		params.Set("client_id", clientID)
+	case AuthMethodNone:
+		// Just include client_id in request body, if the client is identified at all
+		if clientID != "" {
+			params.Set("client_id", clientID)
+		}
+	}
+	return nil
+}
+
+// setProvidedClientAssertion adds an externally issued client assertion to the request parameters
+func setProvidedClientAssertion(ctx context.Context, params url.Values, clientID string, provider func(ctx context.Context) (string, error)) error {
+	jwt, err := provider(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get client assertion: %w", err)
+	}
+	if clientID != "" {
+		params.Set("client_id", clientID)
+	}
+	params.Set("client_assertion_type", crypto.ClientAssertionType)
+	params.Set("client_assertion", jwt)
+	return nil
+}
+
+// setClientAssertion signs a client assertion and adds it to the request parameters
+func setClientAssertion(endpoint string, params url.Values, clientID string, assertion *ClientAssertion) error {
+	audience := assertion.Audience
+	if audience == "" {
+		audience = endpoint
+	}
+	jwt, err := crypto.NewClientAssertion(clientID, audience, assertion.PrivateKey, assertion.KeyID, assertion.Algorithm)
+	if err != nil {
+		return fmt.Errorf("failed to create client assertion: %w", err)
+	}
+	params.Set("client_id", clientID)
+	params.Set("client_assertion_type", crypto.ClientAssertionType)
+	params.Set("client_assertion", jwt)
+	return nil
+}
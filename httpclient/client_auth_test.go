@@ -0,0 +1,154 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/url"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jentz/oidc-cli/crypto"
+)
+
+func TestApplyClientAuthPrivateKeyJWT(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	tests := []struct {
+		name      string
+		assertion *ClientAssertion
+		wantAud   string
+		wantKid   string
+		wantAlg   string
+		wantErr   bool
+	}{
+		{
+			name:      "defaults",
+			assertion: &ClientAssertion{PrivateKey: key},
+			wantAud:   "https://example.com/token",
+			wantAlg:   "ES256",
+		},
+		{
+			name:      "custom audience and key id",
+			assertion: &ClientAssertion{PrivateKey: key, KeyID: "key-1", Audience: "https://example.com"},
+			wantAud:   "https://example.com",
+			wantKid:   "key-1",
+			wantAlg:   "ES256",
+		},
+		{
+			name:      "algorithm not matching key",
+			assertion: &ClientAssertion{PrivateKey: key, Algorithm: "RS256"},
+			wantErr:   true,
+		},
+		{
+			name:      "missing key",
+			assertion: &ClientAssertion{},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := url.Values{}
+			headers := map[string]string{}
+			err := applyClientAuth(context.Background(), "https://example.com/token", params, headers, AuthMethodPrivateKeyJWT, "client-id", "", tt.assertion)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyClientAuth() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got := params.Get("client_assertion_type"); got != crypto.ClientAssertionType {
+				t.Errorf("client_assertion_type = %q, want %q", got, crypto.ClientAssertionType)
+			}
+			if _, ok := headers["Authorization"]; ok {
+				t.Error("unexpected Authorization header")
+			}
+			token, err := jwt.Parse(params.Get("client_assertion"), func(*jwt.Token) (interface{}, error) {
+				return &key.PublicKey, nil
+			}, jwt.WithAudience(tt.wantAud), jwt.WithIssuer("client-id"), jwt.WithSubject("client-id"), jwt.WithExpirationRequired())
+			if err != nil {
+				t.Fatalf("invalid client assertion: %v", err)
+			}
+			if token.Header["alg"] != tt.wantAlg {
+				t.Errorf("alg = %v, want %v", token.Header["alg"], tt.wantAlg)
+			}
+			if kid, _ := token.Header["kid"].(string); kid != tt.wantKid {
+				t.Errorf("kid = %q, want %q", kid, tt.wantKid)
+			}
+			if jti, _ := token.Claims.(jwt.MapClaims)["jti"].(string); jti == "" {
+				t.Error("jti is missing")
+			}
+		})
+	}
+}
+
+func TestApplyClientAuthSecretJWT(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		assertion *ClientAssertion
+		wantAlg   string
+		wantErr   bool
+	}{
+		{"default algorithm", "client-secret", nil, "HS256", false},
+		{"selected algorithm", "client-secret", &ClientAssertion{Algorithm: "HS512", KeyID: "ignored"}, "HS512", false},
+		{"asymmetric algorithm", "client-secret", &ClientAssertion{Algorithm: "RS256"}, "", true},
+		{"missing secret", "", nil, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := url.Values{}
+			err := applyClientAuth(context.Background(), "https://example.com/introspect", params, map[string]string{}, AuthMethodSecretJWT, "client-id", tt.secret, tt.assertion)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyClientAuth() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if params.Has("client_secret") {
+				t.Error("client secret must not be sent")
+			}
+			token, err := jwt.Parse(params.Get("client_assertion"), func(*jwt.Token) (interface{}, error) {
+				return []byte(tt.secret), nil
+			}, jwt.WithAudience("https://example.com/introspect"), jwt.WithIssuer("client-id"))
+			if err != nil {
+				t.Fatalf("invalid client assertion: %v", err)
+			}
+			if token.Header["alg"] != tt.wantAlg {
+				t.Errorf("alg = %v, want %v", token.Header["alg"], tt.wantAlg)
+			}
+			if _, ok := token.Header["kid"]; ok {
+				t.Error("unexpected kid header")
+			}
+		})
+	}
+}
+
+func TestApplyClientAuthProvidedAssertion(t *testing.T) {
+	calls := 0
+	assertion := &ClientAssertion{Provider: func(context.Context) (string, error) {
+		calls++
+		return "federated-token", nil
+	}}
+
+	for i := 0; i < 2; i++ {
+		params := url.Values{}
+		if err := applyClientAuth(context.Background(), "https://example.com/token", params, map[string]string{}, AuthMethodPrivateKeyJWT, "client-id", "", assertion); err != nil {
+			t.Fatalf("applyClientAuth() error = %v", err)
+		}
+		if got := params.Get("client_assertion"); got != "federated-token" {
+			t.Errorf("client_assertion = %q, want %q", got, "federated-token")
+		}
+		if got := params.Get("client_assertion_type"); got != crypto.ClientAssertionType {
+			t.Errorf("client_assertion_type = %q, want %q", got, crypto.ClientAssertionType)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("provider called %d times, want once per request", calls)
+	}
+}
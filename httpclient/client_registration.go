@@ -0,0 +1,71 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+)
+
+// ClientRegistrationRequest registers a client with the client metadata (RFC 7591 section 3.1)
+type ClientRegistrationRequest struct {
+	Metadata           map[string]interface{}
+	InitialAccessToken string // authorizes the registration, if the server requires it
+}
+
+// ExecuteClientRegistrationRequest sends a client registration request to the registration endpoint
+func (c *Client) ExecuteClientRegistrationRequest(ctx context.Context, endpoint string, req *ClientRegistrationRequest) (*Response, error) {
+	headers := map[string]string{"Accept": "application/json"}
+	if req.InitialAccessToken != "" {
+		headers["Authorization"] = "Bearer " + req.InitialAccessToken
+	}
+	return c.PostJSON(ctx, endpoint, req.Metadata, headers)
+}
+
+// ExecuteClientReadRequest reads the current configuration of a client from its
+// client configuration endpoint (RFC 7592 section 2.1)
+func (c *Client) ExecuteClientReadRequest(ctx context.Context, clientURI, registrationAccessToken string) (*Response, error) {
+	return c.Get(ctx, clientURI, managementHeaders(registrationAccessToken))
+}
+
+// ExecuteClientUpdateRequest replaces the metadata of a client at its client configuration
+// endpoint (RFC 7592 section 2.2). Metadata that is left out is removed or reset by the server.
+func (c *Client) ExecuteClientUpdateRequest(ctx context.Context, clientURI, registrationAccessToken string, metadata map[string]interface{}) (*Response, error) {
+	return c.PutJSON(ctx, clientURI, metadata, managementHeaders(registrationAccessToken))
+}
+
+// ExecuteClientDeleteRequest deprovisions a client at its client configuration endpoint (RFC 7592 section 2.3)
+func (c *Client) ExecuteClientDeleteRequest(ctx context.Context, clientURI, registrationAccessToken string) (*Response, error) {
+	return c.Delete(ctx, clientURI, managementHeaders(registrationAccessToken))
+}
+
+func managementHeaders(registrationAccessToken string) map[string]string {
+	return map[string]string{
+		"Accept":        "application/json",
+		"Authorization": "Bearer " + registrationAccessToken,
+	}
+}
+
+// ParseClientRegistrationResponse parses the client information response of a registration,
+// read or update request (RFC 7591 section 3.2), or the error response of the server
+func ParseClientRegistrationResponse(resp *Response) (map[string]interface{}, error) {
+	if !resp.IsSuccess() {
+		return nil, responseError(resp)
+	}
+
+	var mapResp map[string]interface{}
+	if err := resp.JSON(&mapResp); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrParsingJSON, err)
+	}
+	if clientID, _ := mapResp["client_id"].(string); clientID == "" {
+		return nil, fmt.Errorf("%w: client information response has no client_id", ErrParsingJSON)
+	}
+	return mapResp, nil
+}
+
+// ParseClientDeleteResponse checks the response of a delete request for errors.
+// A successful delete has no response body (RFC 7592 section 2.3).
+func ParseClientDeleteResponse(resp *Response) error {
+	if resp.IsSuccess() {
+		return nil
+	}
+	return responseError(resp)
+}
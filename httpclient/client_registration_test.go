@@ -0,0 +1,137 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientRegistrationRequests(t *testing.T) {
+	var gotMethod, gotAuth string
+	var gotBody map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotAuth, gotBody = r.Method, r.Header.Get("Authorization"), nil
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"client_id":"client-1","client_secret":"secret"}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(nil)
+	ctx := context.Background()
+	metadata := map[string]interface{}{"client_name": "test"}
+
+	tests := []struct {
+		name       string
+		execute    func() (*Response, error)
+		wantMethod string
+		wantAuth   string
+		wantBody   bool
+	}{
+		{
+			name: "register with initial access token",
+			execute: func() (*Response, error) {
+				return client.ExecuteClientRegistrationRequest(ctx, ts.URL, &ClientRegistrationRequest{Metadata: metadata, InitialAccessToken: "iat"})
+			},
+			wantMethod: http.MethodPost,
+			wantAuth:   "Bearer iat",
+			wantBody:   true,
+		},
+		{
+			name: "open registration",
+			execute: func() (*Response, error) {
+				return client.ExecuteClientRegistrationRequest(ctx, ts.URL, &ClientRegistrationRequest{Metadata: metadata})
+			},
+			wantMethod: http.MethodPost,
+			wantBody:   true,
+		},
+		{
+			name:       "read",
+			execute:    func() (*Response, error) { return client.ExecuteClientReadRequest(ctx, ts.URL, "rat") },
+			wantMethod: http.MethodGet,
+			wantAuth:   "Bearer rat",
+		},
+		{
+			name:       "update",
+			execute:    func() (*Response, error) { return client.ExecuteClientUpdateRequest(ctx, ts.URL, "rat", metadata) },
+			wantMethod: http.MethodPut,
+			wantAuth:   "Bearer rat",
+			wantBody:   true,
+		},
+		{
+			name:       "delete",
+			execute:    func() (*Response, error) { return client.ExecuteClientDeleteRequest(ctx, ts.URL, "rat") },
+			wantMethod: http.MethodDelete,
+			wantAuth:   "Bearer rat",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := tt.execute()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if gotMethod != tt.wantMethod || gotAuth != tt.wantAuth {
+				t.Errorf("got %s with Authorization %q, want %s with %q", gotMethod, gotAuth, tt.wantMethod, tt.wantAuth)
+			}
+			if tt.wantBody && gotBody["client_name"] != "test" {
+				t.Errorf("got body %v, want the client metadata", gotBody)
+			}
+			if tt.wantMethod == http.MethodDelete {
+				if err := ParseClientDeleteResponse(resp); err != nil {
+					t.Errorf("ParseClientDeleteResponse() error = %v", err)
+				}
+				return
+			}
+			client, err := ParseClientRegistrationResponse(resp)
+			if err != nil {
+				t.Fatalf("ParseClientRegistrationResponse() error = %v", err)
+			}
+			if client["client_id"] != "client-1" {
+				t.Errorf("got client %v, want client_id client-1", client)
+			}
+		})
+	}
+}
+
+func TestParseClientRegistrationResponseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		resp    *Response
+		wantErr error
+	}{
+		{
+			name:    "registration error",
+			resp:    &Response{StatusCode: http.StatusBadRequest, Body: []byte(`{"error":"invalid_redirect_uri","error_description":"not allowed"}`)},
+			wantErr: ErrOAuthError,
+		},
+		{
+			name:    "unauthorized without body",
+			resp:    &Response{StatusCode: http.StatusUnauthorized},
+			wantErr: ErrHTTPFailure,
+		},
+		{
+			name:    "missing client_id",
+			resp:    &Response{StatusCode: http.StatusCreated, Body: []byte(`{"client_secret":"secret"}`)},
+			wantErr: ErrParsingJSON,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseClientRegistrationResponse(tt.resp)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseClientRegistrationResponse() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
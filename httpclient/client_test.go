@@ -2,8 +2,15 @@ package httpclient
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
 	"io"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -394,3 +401,50 @@ func TestDo_ContextCanceled(t *testing.T) {
 		t.Error("Expected context cancellation error, got nil")
 	}
 }
+
+func TestClientCertificate(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	cert := &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	ts.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+	ts.StartTLS()
+	defer ts.Close()
+
+	client := NewClient(&Config{SkipTLSVerify: true})
+	resp, err := client.Get(context.Background(), ts.URL, nil)
+	if err != nil {
+		t.Fatalf("Get() without certificate error = %v", err)
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("status without certificate = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+
+	client.SetClientCertificate(cert)
+	// Close the idle connection so the next request performs a new handshake
+	client.client.CloseIdleConnections()
+	resp, err = client.Get(context.Background(), ts.URL, nil)
+	if err != nil {
+		t.Fatalf("Get() with certificate error = %v", err)
+	}
+	if resp.String() != "client" {
+		t.Errorf("server saw certificate %q, want %q", resp.String(), "client")
+	}
+}
@@ -0,0 +1,93 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// Device authorization polling errors as defined in RFC 8628 section 3.5
+const (
+	DeviceErrorAuthorizationPending = "authorization_pending"
+	DeviceErrorSlowDown             = "slow_down"
+	DeviceErrorAccessDenied         = "access_denied"
+	DeviceErrorExpiredToken         = "expired_token"
+)
+
+type DeviceAuthorizationRequest struct {
+	ClientID        string
+	ClientSecret    string
+	ClientAssertion *ClientAssertion
+	AuthMethod      AuthMethod
+	Scope           string
+	CustomArgs      *CustomArgs
+}
+
+type DeviceAuthorizationResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete,omitempty"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval,omitempty"`
+}
+
+// ExecuteDeviceAuthorizationRequest sends a device authorization request (RFC 8628 section 3.1)
+func (c *Client) ExecuteDeviceAuthorizationRequest(ctx context.Context, endpoint string, req *DeviceAuthorizationRequest) (*Response, error) {
+	headers := make(map[string]string)
+
+	params := url.Values{}
+	if req.Scope != "" {
+		params.Set("scope", req.Scope)
+	}
+	// Add custom args
+	if req.CustomArgs != nil {
+		for k, v := range *req.CustomArgs {
+			params.Set(k, v)
+		}
+	}
+
+	// Apply authentication method
+	if err := applyClientAuth(ctx, endpoint, params, headers, req.AuthMethod, req.ClientID, req.ClientSecret, req.ClientAssertion); err != nil {
+		return nil, err
+	}
+
+	// Execute the request
+	return c.PostForm(ctx, endpoint, params, headers)
+}
+
+// ParseDeviceAuthorizationResponse parses the device authorization response
+func ParseDeviceAuthorizationResponse(resp *Response) (*DeviceAuthorizationResponse, error) {
+	if !resp.IsSuccess() {
+		oauth2Err := &Error{
+			StatusCode: resp.StatusCode,
+			RawBody:    resp.String(),
+		}
+		var mapResp map[string]interface{}
+
+		if err := json.Unmarshal(resp.Body, &mapResp); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrParsingJSON, err)
+		}
+
+		// Extract standard OAuth2 error fields if present
+		if errStr, ok := mapResp["error"].(string); ok {
+			oauth2Err.ErrorType = errStr
+			if desc, ok := mapResp["error_description"].(string); ok {
+				oauth2Err.ErrorDescription = desc
+			}
+			return nil, fmt.Errorf("%w: %v", ErrOAuthError, oauth2Err)
+		}
+
+		return nil, fmt.Errorf("%w: %v", ErrHTTPFailure, oauth2Err)
+	}
+
+	var deviceResp DeviceAuthorizationResponse
+	if err := json.Unmarshal(resp.Body, &deviceResp); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrParsingJSON, err)
+	}
+	if deviceResp.DeviceCode == "" || deviceResp.UserCode == "" || deviceResp.VerificationURI == "" {
+		return nil, fmt.Errorf("%w: device_code, user_code and verification_uri are required", ErrParsingJSON)
+	}
+	return &deviceResp, nil
+}
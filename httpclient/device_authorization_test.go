@@ -0,0 +1,196 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestExecuteDeviceAuthorizationRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        *DeviceAuthorizationRequest
+		wantParams map[string]string
+		wantAuth   string
+	}{
+		{
+			name: "basic auth method",
+			req: &DeviceAuthorizationRequest{
+				ClientID:     "test-client",
+				ClientSecret: "test-secret",
+				AuthMethod:   AuthMethodBasic,
+				Scope:        "openid profile",
+			},
+			wantParams: map[string]string{
+				"scope": "openid profile",
+			},
+			wantAuth: "Basic dGVzdC1jbGllbnQ6dGVzdC1zZWNyZXQ=", // base64 of test-client:test-secret
+		},
+		{
+			name: "post auth method",
+			req: &DeviceAuthorizationRequest{
+				ClientID:     "test-client",
+				ClientSecret: "test-secret",
+				AuthMethod:   AuthMethodPost,
+				Scope:        "openid",
+			},
+			wantParams: map[string]string{
+				"scope":         "openid",
+				"client_id":     "test-client",
+				"client_secret": "test-secret",
+			},
+		},
+		{
+			name: "none auth method with custom args",
+			req: &DeviceAuthorizationRequest{
+				ClientID:   "public-client",
+				AuthMethod: AuthMethodNone,
+				CustomArgs: &CustomArgs{"audience": "api"},
+			},
+			wantParams: map[string]string{
+				"client_id": "public-client",
+				"audience":  "api",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost {
+					t.Errorf("Expected POST method, got %s", r.Method)
+				}
+
+				// Check auth header if expected
+				if tt.wantAuth != "" {
+					gotAuth := r.Header.Get("Authorization")
+					if gotAuth != tt.wantAuth {
+						t.Errorf("got Authorization header %q, want %q", gotAuth, tt.wantAuth)
+					}
+				}
+
+				// Parse form and check parameters
+				_ = r.ParseForm()
+				for key, want := range tt.wantParams {
+					got := r.FormValue(key)
+					if got != want {
+						t.Errorf("got param %s=%q, want %q", key, got, want)
+					}
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write([]byte(`{"device_code":"dc","user_code":"UC","verification_uri":"https://example.com/device","expires_in":600}`))
+			}))
+			defer ts.Close()
+
+			client := NewClient(nil)
+			resp, err := client.ExecuteDeviceAuthorizationRequest(context.Background(), ts.URL, tt.req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if !resp.IsSuccess() {
+				t.Errorf("Expected successful response, got status %d", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestParseDeviceAuthorizationResponse(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		wantErr    bool
+		wantErrMsg string
+		wantData   *DeviceAuthorizationResponse
+	}{
+		{
+			name:       "successful response",
+			statusCode: 200,
+			body: `{"device_code":"GmRhmhcxhwAzkoEqiMEg_DnyEysNkuNhszIySk9eS","user_code":"WDJB-MJHT",` +
+				`"verification_uri":"https://example.com/device","verification_uri_complete":"https://example.com/device?user_code=WDJB-MJHT",` +
+				`"expires_in":1800,"interval":5}`,
+			wantData: &DeviceAuthorizationResponse{
+				DeviceCode:              "GmRhmhcxhwAzkoEqiMEg_DnyEysNkuNhszIySk9eS",
+				UserCode:                "WDJB-MJHT",
+				VerificationURI:         "https://example.com/device",
+				VerificationURIComplete: "https://example.com/device?user_code=WDJB-MJHT",
+				ExpiresIn:               1800,
+				Interval:                5,
+			},
+		},
+		{
+			name:       "successful response without interval",
+			statusCode: 200,
+			body:       `{"device_code":"dc","user_code":"UC","verification_uri":"https://example.com/device","expires_in":600}`,
+			wantData: &DeviceAuthorizationResponse{
+				DeviceCode:      "dc",
+				UserCode:        "UC",
+				VerificationURI: "https://example.com/device",
+				ExpiresIn:       600,
+			},
+		},
+		{
+			name:       "missing required fields",
+			statusCode: 200,
+			body:       `{"device_code":"dc","expires_in":600}`,
+			wantErr:    true,
+			wantErrMsg: "json parsing error",
+		},
+		{
+			name:       "oauth2 error response",
+			statusCode: 400,
+			body:       `{"error":"invalid_client","error_description":"Unknown client"}`,
+			wantErr:    true,
+			wantErrMsg: "oauth protocol error",
+		},
+		{
+			name:       "http error without oauth2 format",
+			statusCode: 500,
+			body:       `{"message":"Internal server error"}`,
+			wantErr:    true,
+			wantErrMsg: "oauth http failure",
+		},
+		{
+			name:       "invalid json",
+			statusCode: 200,
+			body:       `invalid json`,
+			wantErr:    true,
+			wantErrMsg: "json parsing error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &Response{
+				StatusCode: tt.statusCode,
+				Body:       []byte(tt.body),
+			}
+
+			deviceResp, err := ParseDeviceAuthorizationResponse(resp)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected error, got nil")
+					return
+				}
+				if !strings.Contains(err.Error(), tt.wantErrMsg) {
+					t.Errorf("Expected error containing %q, got %q", tt.wantErrMsg, err.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if *deviceResp != *tt.wantData {
+				t.Errorf("got %+v, want %+v", *deviceResp, *tt.wantData)
+			}
+		})
+	}
+}
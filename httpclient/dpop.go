@@ -0,0 +1,95 @@
+package httpclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jentz/oidc-cli/log"
+)
+
+// ErrorUseDPoPNonce is the error a server returns when a DPoP proof lacks its current nonce (RFC 9449 section 12.2)
+const ErrorUseDPoPNonce = "use_dpop_nonce"
+
+// DPoPProofFunc creates a fresh DPoP proof for a request, with the nonce claim set if nonce is not empty
+type DPoPProofFunc func(nonce string) (string, error)
+
+// ExecuteWithDPoP sends a request with send, adding a DPoP proof created with proof to the headers.
+// The DPoP-Nonce returned by a server is kept for later requests to the same server, and a request the
+// server rejects with use_dpop_nonce is retried once with a fresh proof carrying the new nonce
+// (RFC 9449 sections 8 and 9). Without a proof function the request is sent as is.
+func (c *Client) ExecuteWithDPoP(endpoint string, proof DPoPProofFunc, send func(headers map[string]string) (*Response, error)) (*Response, error) {
+	if proof == nil {
+		return send(make(map[string]string))
+	}
+
+	var resp *Response
+	for attempt := 0; attempt < 2; attempt++ {
+		nonce := c.DPoPNonce(endpoint)
+		dpopProof, err := proof(nonce)
+		if err != nil {
+			return nil, err
+		}
+		resp, err = send(map[string]string{"DPoP": dpopProof})
+		if err != nil {
+			return nil, err
+		}
+
+		newNonce := resp.Headers.Get("DPoP-Nonce")
+		if newNonce != "" {
+			c.SetDPoPNonce(endpoint, newNonce)
+		}
+		if !isUseDPoPNonceError(resp) || newNonce == "" || newNonce == nonce {
+			return resp, nil
+		}
+		log.Printf("server requires DPoP nonce %q, retrying with a fresh proof\n", newNonce)
+	}
+	return resp, nil
+}
+
+// DPoPNonce returns the latest DPoP nonce of the server of the endpoint, or an empty string if it has not sent one
+func (c *Client) DPoPNonce(endpoint string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.dpopNonces[origin(endpoint)]
+}
+
+// SetDPoPNonce keeps the DPoP nonce sent by the server of the endpoint for later requests to it
+func (c *Client) SetDPoPNonce(endpoint, nonce string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.dpopNonces == nil {
+		c.dpopNonces = make(map[string]string)
+	}
+	c.dpopNonces[origin(endpoint)] = nonce
+}
+
+// origin returns the scheme and host of an endpoint, which identify the server a nonce belongs to
+func origin(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return endpoint
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// isUseDPoPNonceError reports whether the server asked for a DPoP proof with its nonce, either in the
+// error response of an authorization server (RFC 9449 section 8) or in the WWW-Authenticate challenge
+// of a protected resource (RFC 9449 section 9)
+func isUseDPoPNonceError(resp *Response) bool {
+	switch resp.StatusCode {
+	case http.StatusBadRequest:
+		var errResp struct {
+			Error string `json:"error"`
+		}
+		return json.Unmarshal(resp.Body, &errResp) == nil && errResp.Error == ErrorUseDPoPNonce
+	case http.StatusUnauthorized:
+		for _, challenge := range ParseWWWAuthenticate(resp.Headers.Values("WWW-Authenticate")) {
+			if strings.EqualFold(challenge.Scheme, TokenTypeDPoP) && challenge.Param("error") == ErrorUseDPoPNonce {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// nonceServer requires DPoP proofs with its current nonce, like an authorization server
+// (status 400) or a protected resource (status 401), and records the proofs it received
+func nonceServer(t *testing.T, status int, nonce string, proofs *[]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proof := r.Header.Get("DPoP")
+		*proofs = append(*proofs, proof)
+		w.Header().Set("DPoP-Nonce", nonce)
+		if proof == "proof-"+nonce {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		if status == http.StatusUnauthorized {
+			w.Header().Set("WWW-Authenticate", `DPoP error="use_dpop_nonce", error_description="Resource server requires nonce in DPoP proof"`)
+			w.WriteHeader(status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"error":"use_dpop_nonce","error_description":"Authorization server requires nonce in DPoP proof"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func proofWithNonce(nonce string) (string, error) {
+	return "proof-" + nonce, nil
+}
+
+func TestExecuteWithDPoP(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+	}{
+		{name: "authorization server", status: http.StatusBadRequest},
+		{name: "protected resource", status: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var proofs []string
+			server := nonceServer(t, tt.status, "n-1", &proofs)
+			client := NewClient(nil)
+			send := func(headers map[string]string) (*Response, error) {
+				return client.Do(context.Background(), http.MethodPost, server.URL+"/token", nil, headers)
+			}
+
+			resp, err := client.ExecuteWithDPoP(server.URL+"/token", proofWithNonce, send)
+			if err != nil {
+				t.Fatalf("ExecuteWithDPoP() error = %v", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("StatusCode = %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+			if want := []string{"proof-", "proof-n-1"}; len(proofs) != 2 || proofs[0] != want[0] || proofs[1] != want[1] {
+				t.Errorf("proofs = %q, want %q", proofs, want)
+			}
+
+			// The nonce is kept for later requests to any endpoint of the server
+			if got := client.DPoPNonce(server.URL + "/par"); got != "n-1" {
+				t.Errorf("DPoPNonce() = %q, want %q", got, "n-1")
+			}
+			proofs = nil
+			if _, err := client.ExecuteWithDPoP(server.URL+"/par", proofWithNonce, send); err != nil {
+				t.Fatalf("ExecuteWithDPoP() error = %v", err)
+			}
+			if len(proofs) != 1 || proofs[0] != "proof-n-1" {
+				t.Errorf("proofs = %q, want a single proof with the kept nonce", proofs)
+			}
+		})
+	}
+}
+
+func TestExecuteWithDPoPRetriesOnce(t *testing.T) {
+	var proofs []string
+	server := nonceServer(t, http.StatusBadRequest, "n-1", &proofs)
+	client := NewClient(nil)
+	// A proof that ignores the nonce is rejected every time
+	proof := func(string) (string, error) { return "proof-stale", nil }
+
+	resp, err := client.ExecuteWithDPoP(server.URL, proof, func(headers map[string]string) (*Response, error) {
+		return client.Do(context.Background(), http.MethodPost, server.URL, nil, headers)
+	})
+	if err != nil {
+		t.Fatalf("ExecuteWithDPoP() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("StatusCode = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+	if len(proofs) != 2 {
+		t.Errorf("requests = %d, want 2", len(proofs))
+	}
+}
+
+func TestExecuteWithDPoPWithoutProof(t *testing.T) {
+	var proofs []string
+	server := nonceServer(t, http.StatusBadRequest, "n-1", &proofs)
+	client := NewClient(nil)
+
+	resp, err := client.ExecuteWithDPoP(server.URL, nil, func(headers map[string]string) (*Response, error) {
+		return client.Do(context.Background(), http.MethodPost, server.URL, nil, headers)
+	})
+	if err != nil {
+		t.Fatalf("ExecuteWithDPoP() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest || len(proofs) != 1 || proofs[0] != "" {
+		t.Errorf("got status %d with proofs %q, want a single request without a proof", resp.StatusCode, proofs)
+	}
+}
@@ -0,0 +1,106 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/jentz/oidc-cli/log"
+	"github.com/jentz/oidc-cli/webflow"
+)
+
+// EndSessionRequest is a logout request to the end session endpoint
+// (OpenID Connect RP-Initiated Logout section 2)
+type EndSessionRequest struct {
+	IDTokenHint           string
+	LogoutHint            string
+	ClientID              string
+	PostLogoutRedirectURI string
+	State                 string
+	UILocales             string
+	CustomArgs            *CustomArgs
+}
+
+type EndSessionResponse struct {
+	State string
+}
+
+// CreateEndSessionRequestValues builds the query parameters of the logout request
+func CreateEndSessionRequestValues(req *EndSessionRequest) *url.Values {
+	values := &url.Values{}
+	if req.IDTokenHint != "" {
+		values.Set("id_token_hint", req.IDTokenHint)
+	}
+	if req.LogoutHint != "" {
+		values.Set("logout_hint", req.LogoutHint)
+	}
+	if req.ClientID != "" {
+		values.Set("client_id", req.ClientID)
+	}
+	if req.PostLogoutRedirectURI != "" {
+		values.Set("post_logout_redirect_uri", req.PostLogoutRedirectURI)
+	}
+	if req.State != "" {
+		values.Set("state", req.State)
+	}
+	if req.UILocales != "" {
+		values.Set("ui_locales", req.UILocales)
+	}
+
+	// Add custom args
+	if req.CustomArgs != nil {
+		for k, v := range *req.CustomArgs {
+			values.Set(k, v)
+		}
+	}
+	return values
+}
+
+// ExecuteEndSessionRequest opens the logout request in the browser. If a callback is given, it waits
+// for the post-logout redirect to the callback and returns the state it carries, otherwise it returns
+// a nil response once the browser has been opened.
+func (c *Client) ExecuteEndSessionRequest(ctx context.Context, endpoint string, callback string, req *EndSessionRequest) (*EndSessionResponse, error) {
+	var callbackServer *webflow.CallbackServer
+	if callback != "" {
+		var err error
+		callbackServer, err = webflow.NewPostLogoutCallbackServer(callback)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create callback server: %w", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	if callbackServer != nil {
+		if err := startCallbackServer(ctx, callbackServer); err != nil {
+			return nil, err
+		}
+	}
+
+	requestURL, err := CreateAuthorizationCodeRequestURL(endpoint, CreateEndSessionRequestValues(req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create logout request URL: %w", err)
+	}
+	log.Printf("logout request: %s\n", requestURL)
+
+	browser := webflow.NewBrowser()
+	err = browser.Open(requestURL)
+	if err != nil {
+		log.Errorf("unable to open browser because %v, visit %s to continue\n", err, requestURL)
+	}
+
+	if callbackServer == nil {
+		return nil, nil
+	}
+	callbackResp, err := callbackServer.WaitForCallback(ctx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, errors.New("timeout waiting for the post-logout redirect")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("callback failed: %w", err)
+	}
+	return &EndSessionResponse{State: callbackResp.State}, nil
+}
@@ -0,0 +1,54 @@
+package httpclient
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCreateEndSessionRequestValues(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        *EndSessionRequest
+		wantParams map[string]string
+	}{
+		{
+			name:       "empty request",
+			req:        &EndSessionRequest{},
+			wantParams: map[string]string{},
+		},
+		{
+			name: "all fields",
+			req: &EndSessionRequest{
+				IDTokenHint:           "header.payload.signature",
+				LogoutHint:            "user@example.com",
+				ClientID:              "test-client",
+				PostLogoutRedirectURI: "http://localhost:9555/logout",
+				State:                 "random-state-123",
+				UILocales:             "en-US",
+				CustomArgs:            &CustomArgs{"custom_param": "custom_value"},
+			},
+			wantParams: map[string]string{
+				"id_token_hint":            "header.payload.signature",
+				"logout_hint":              "user@example.com",
+				"client_id":                "test-client",
+				"post_logout_redirect_uri": "http://localhost:9555/logout",
+				"state":                    "random-state-123",
+				"ui_locales":               "en-US",
+				"custom_param":             "custom_value",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := CreateEndSessionRequestValues(tt.req)
+			got := make(map[string]string)
+			for k := range *values {
+				got[k] = values.Get(k)
+			}
+			if !reflect.DeepEqual(got, tt.wantParams) {
+				t.Errorf("CreateEndSessionRequestValues() = %v, want %v", got, tt.wantParams)
+			}
+		})
+	}
+}
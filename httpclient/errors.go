@@ -1,6 +1,7 @@
 package httpclient
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 )
@@ -43,3 +44,26 @@ func WrapError(err error, operation string) error {
 		return fmt.Errorf("%s error: %w", operation, err)
 	}
 }
+
+// responseError returns the error of an unsuccessful response that is not expected to carry
+// a body otherwise, as an OAuth2 error if the body has one
+func responseError(resp *Response) error {
+	oauth2Err := &Error{
+		StatusCode: resp.StatusCode,
+		RawBody:    resp.String(),
+	}
+
+	// Extract standard OAuth2 error fields if present
+	var mapResp map[string]interface{}
+	if err := json.Unmarshal(resp.Body, &mapResp); err == nil {
+		if errStr, ok := mapResp["error"].(string); ok {
+			oauth2Err.ErrorType = errStr
+			if desc, ok := mapResp["error_description"].(string); ok {
+				oauth2Err.ErrorDescription = desc
+			}
+			return fmt.Errorf("%w: %v", ErrOAuthError, oauth2Err)
+		}
+	}
+
+	return fmt.Errorf("%w: %v", ErrHTTPFailure, oauth2Err)
+}
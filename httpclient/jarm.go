@@ -0,0 +1,105 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jentz/oidc-cli/crypto"
+	"github.com/jentz/oidc-cli/webflow"
+)
+
+// JARMVerifier holds what a JWT Secured Authorization Response Mode (JARM) response is verified against
+type JARMVerifier struct {
+	Issuer        string
+	ClientID      string
+	ClientSecret  string                                            // verifies responses signed with HMAC
+	DecryptionKey any                                               // decrypts encrypted responses
+	Keys          func(ctx context.Context) (*crypto.JWKSet, error) // the issuer's signing keys
+	ClockSkew     time.Duration
+}
+
+// IsJARMResponseMode reports whether a response mode returns the authorization response as a JWT (JARM section 2.3)
+func IsJARMResponseMode(responseMode string) bool {
+	return responseMode == "jwt" || strings.HasSuffix(responseMode, ".jwt")
+}
+
+// VerifyJARMResponse decrypts the response JWT if it is encrypted, verifies its signature, iss, aud and exp,
+// and returns the authorization response parameters carried in its claims (JARM section 2.4)
+func VerifyJARMResponse(ctx context.Context, response string, v *JARMVerifier) (*webflow.CallbackResponse, error) {
+	token := response
+	if crypto.IsJWE(token) {
+		if v.DecryptionKey == nil {
+			return nil, errors.New("response is encrypted but no private key is configured")
+		}
+		plaintext, _, err := crypto.DecryptJWE(token, v.DecryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt response: %w", err)
+		}
+		token = strings.TrimSpace(string(plaintext))
+	}
+
+	unverified, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	var parsed *jwt.Token
+	if alg, _ := unverified.Header["alg"].(string); strings.HasPrefix(alg, "HS") {
+		parsed, err = crypto.VerifyJWTHMAC(token, []byte(v.ClientSecret))
+	} else {
+		var keys *crypto.JWKSet
+		if keys, err = v.Keys(ctx); err != nil {
+			return nil, err
+		}
+		parsed, _, err = crypto.VerifyJWTSignature(token, keys)
+	}
+	if err != nil {
+		return nil, err
+	}
+	claims := parsed.Claims.(jwt.MapClaims)
+
+	if iss, _ := claims.GetIssuer(); iss != v.Issuer {
+		return nil, fmt.Errorf("iss %q does not match issuer %q", iss, v.Issuer)
+	}
+	if aud, _ := claims.GetAudience(); !slices.Contains(aud, v.ClientID) {
+		return nil, fmt.Errorf("aud %v does not contain client id %q", aud, v.ClientID)
+	}
+	exp, err := claims.GetExpirationTime()
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("invalid exp: %w", err)
+	case exp == nil:
+		return nil, errors.New("exp is missing")
+	case !time.Now().Add(-v.ClockSkew).Before(exp.Time):
+		return nil, fmt.Errorf("expired at %s", exp.Time.UTC().Format(time.RFC3339))
+	}
+
+	return &webflow.CallbackResponse{
+		Code:             jarmParam(claims, "code"),
+		State:            jarmParam(claims, "state"),
+		Issuer:           jarmParam(claims, "iss"),
+		IDToken:          jarmParam(claims, "id_token"),
+		AccessToken:      jarmParam(claims, "access_token"),
+		TokenType:        jarmParam(claims, "token_type"),
+		ExpiresIn:        jarmParam(claims, "expires_in"),
+		Scope:            jarmParam(claims, "scope"),
+		ErrorMsg:         jarmParam(claims, "error"),
+		ErrorDescription: jarmParam(claims, "error_description"),
+	}, nil
+}
+
+// jarmParam returns an authorization response parameter from the claims of a response JWT
+func jarmParam(claims jwt.MapClaims, name string) string {
+	switch v := claims[name].(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
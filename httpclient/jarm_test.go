@@ -0,0 +1,106 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jentz/oidc-cli/crypto"
+)
+
+func TestVerifyJARMResponse(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	otherKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	encryptionKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	jwk, _ := crypto.NewJWK(&key.PublicKey, "k1")
+
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":   "https://example.com",
+			"aud":   "client-id",
+			"exp":   time.Now().Add(time.Minute).Unix(),
+			"code":  "abc123",
+			"state": "xyz",
+		}
+	}
+	with := func(name string, value any) jwt.MapClaims {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+		return claims
+	}
+	sign := func(claims jwt.MapClaims, signingKey *ecdsa.PrivateKey) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+		token.Header["kid"] = "k1"
+		signed, err := token.SignedString(signingKey)
+		if err != nil {
+			t.Fatalf("failed to sign response: %v", err)
+		}
+		return signed
+	}
+	encrypted, err := crypto.EncryptJWE([]byte(sign(validClaims(), key)), &encryptionKey.PublicKey, crypto.JWEAlgECDHES, crypto.JWEEncA128GCM, map[string]any{"cty": "JWT"})
+	if err != nil {
+		t.Fatalf("EncryptJWE() error = %v", err)
+	}
+	hmacSigned, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims()).SignedString([]byte("client-secret"))
+
+	tests := []struct {
+		name          string
+		response      string
+		decryptionKey any
+		wantErr       string
+		wantCode      string
+		wantError     string
+	}{
+		{"valid response", sign(validClaims(), key), nil, "", "abc123", ""},
+		{"response without code", sign(with("code", nil), key), nil, "", "", ""},
+		{"access denied", sign(with("error", "access_denied"), key), nil, "", "abc123", "access_denied"},
+		{"signed with client secret", hmacSigned, nil, "", "abc123", ""},
+		{"encrypted response", encrypted, encryptionKey, "", "abc123", ""},
+		{"encrypted without key", encrypted, nil, "no private key", "", ""},
+		{"wrong signing key", sign(validClaims(), otherKey), nil, "signature verification failed", "", ""},
+		{"wrong issuer", sign(with("iss", "https://evil.example.com"), key), nil, "iss", "", ""},
+		{"wrong audience", sign(with("aud", "other"), key), nil, "aud", "", ""},
+		{"expired", sign(with("exp", time.Now().Add(-time.Hour).Unix()), key), nil, "expired", "", ""},
+		{"missing exp", sign(with("exp", nil), key), nil, "exp is missing", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &JARMVerifier{
+				Issuer:        "https://example.com",
+				ClientID:      "client-id",
+				ClientSecret:  "client-secret",
+				DecryptionKey: tt.decryptionKey,
+				Keys: func(context.Context) (*crypto.JWKSet, error) {
+					return &crypto.JWKSet{Keys: []crypto.JWK{*jwk}}, nil
+				},
+				ClockSkew: time.Minute,
+			}
+			resp, err := VerifyJARMResponse(context.Background(), tt.response, v)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("VerifyJARMResponse() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifyJARMResponse() error = %v", err)
+			}
+			if resp.Code != tt.wantCode || resp.ErrorMsg != tt.wantError {
+				t.Errorf("got code %q error %q, want code %q error %q", resp.Code, resp.ErrorMsg, tt.wantCode, tt.wantError)
+			}
+			if resp.State != "xyz" || resp.Issuer != "https://example.com" {
+				t.Errorf("got state %q iss %q, want xyz and https://example.com", resp.State, resp.Issuer)
+			}
+		})
+	}
+}
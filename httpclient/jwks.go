@@ -0,0 +1,32 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jentz/oidc-cli/crypto"
+)
+
+// ExecuteJWKSRequest fetches the JSON Web Key Set published at the jwks_uri
+func (c *Client) ExecuteJWKSRequest(ctx context.Context, endpoint string) (*Response, error) {
+	headers := map[string]string{
+		"Accept": "application/jwk-set+json, application/json",
+	}
+	return c.Get(ctx, endpoint, headers)
+}
+
+// ParseJWKSResponse parses a JSON Web Key Set response
+func ParseJWKSResponse(resp *Response) (*crypto.JWKSet, error) {
+	if !resp.IsSuccess() {
+		return nil, fmt.Errorf("%w: %v", ErrHTTPFailure, &Error{
+			StatusCode: resp.StatusCode,
+			RawBody:    resp.String(),
+		})
+	}
+
+	keys, err := crypto.ParseJWKSet(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrParsingJSON, err)
+	}
+	return keys, nil
+}
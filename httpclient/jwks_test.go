@@ -0,0 +1,55 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExecuteJWKSRequest(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected GET method, got %s", r.Method)
+		}
+		w.Header().Set("Content-Type", "application/jwk-set+json")
+		_, _ = w.Write([]byte(`{"keys":[{"kty":"EC","kid":"k1","crv":"P-256","x":"AA","y":"AA"}]}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(nil)
+	resp, err := client.ExecuteJWKSRequest(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	keys, err := ParseJWKSResponse(resp)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(keys.Keys) != 1 || keys.Keys[0].Kid != "k1" {
+		t.Errorf("ParseJWKSResponse() = %+v, want one key with kid k1", keys)
+	}
+}
+
+func TestParseJWKSResponseError(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		wantErr    error
+	}{
+		{"not found", 404, `Not Found`, ErrHTTPFailure},
+		{"invalid json", 200, `not json`, ErrParsingJSON},
+		{"missing keys", 200, `{}`, ErrParsingJSON},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseJWKSResponse(&Response{StatusCode: tt.statusCode, Body: []byte(tt.body)})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseJWKSResponse() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
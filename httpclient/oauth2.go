@@ -26,14 +26,31 @@ const (
 	AuthMethodBasic AuthMethod = "client_secret_basic"
 	// AuthMethodPost includes client credentials in the request body
 	AuthMethodPost AuthMethod = "client_secret_post"
+	// AuthMethodSecretJWT authenticates with a JWT signed with the client secret
+	AuthMethodSecretJWT AuthMethod = "client_secret_jwt"
+	// AuthMethodPrivateKeyJWT authenticates with a JWT signed by the client's private key
+	AuthMethodPrivateKeyJWT AuthMethod = "private_key_jwt"
+	// AuthMethodTLSClientAuth authenticates with a CA-issued client certificate (RFC 8705 section 2.1)
+	AuthMethodTLSClientAuth AuthMethod = "tls_client_auth"
+	// AuthMethodSelfSignedTLSClientAuth authenticates with a self-signed client certificate (RFC 8705 section 2.2)
+	AuthMethodSelfSignedTLSClientAuth AuthMethod = "self_signed_tls_client_auth"
 	// AuthMethodNone doesn't include client authentication
 	AuthMethodNone AuthMethod = "none"
 )
 
 var validAuthMethods = map[AuthMethod]bool{
-	AuthMethodBasic: true,
-	AuthMethodPost:  true,
-	AuthMethodNone:  true,
+	AuthMethodBasic:                   true,
+	AuthMethodPost:                    true,
+	AuthMethodSecretJWT:               true,
+	AuthMethodPrivateKeyJWT:           true,
+	AuthMethodTLSClientAuth:           true,
+	AuthMethodSelfSignedTLSClientAuth: true,
+	AuthMethodNone:                    true,
+}
+
+// IsMutualTLS reports whether the client authenticates with its TLS client certificate
+func (a *AuthMethod) IsMutualTLS() bool {
+	return *a == AuthMethodTLSClientAuth || *a == AuthMethodSelfSignedTLSClientAuth
 }
 
 // IsValid checks if the AuthMethod is valid
@@ -49,8 +66,9 @@ func (a *AuthMethod) String() string {
 func (a *AuthMethod) Set(value string) error {
 	method := AuthMethod(value)
 	if !method.IsValid() {
-		return fmt.Errorf("invalid auth method %q, valid values are: %s, %s, %s",
-			value, AuthMethodBasic, AuthMethodPost, AuthMethodNone)
+		return fmt.Errorf("invalid auth method %q, valid values are: %s, %s, %s, %s, %s, %s, %s",
+			value, AuthMethodBasic, AuthMethodPost, AuthMethodSecretJWT, AuthMethodPrivateKeyJWT,
+			AuthMethodTLSClientAuth, AuthMethodSelfSignedTLSClientAuth, AuthMethodNone)
 	}
 	*a = method
 	return nil
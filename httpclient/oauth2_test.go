@@ -69,6 +69,9 @@ func TestAuthMethod_IsValid(t *testing.T) {
 	}{
 		{"valid basic", AuthMethodBasic, true},
 		{"valid post", AuthMethodPost, true},
+		{"valid client_secret_jwt", AuthMethodSecretJWT, true},
+		{"valid private_key_jwt", AuthMethodPrivateKeyJWT, true},
+		{"valid tls_client_auth", AuthMethodTLSClientAuth, true},
 		{"valid none", AuthMethodNone, true},
 		{"invalid method", AuthMethod("invalid"), false},
 		{"empty method", AuthMethod(""), false},
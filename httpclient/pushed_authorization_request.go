@@ -2,17 +2,17 @@ package httpclient
 
 import (
 	"context"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/url"
 )
 
 type PushedAuthorizationRequest struct {
-	ClientID     string
-	ClientSecret string
-	AuthMethod   AuthMethod
-	Params       *url.Values
+	ClientID        string
+	ClientSecret    string
+	ClientAssertion *ClientAssertion
+	AuthMethod      AuthMethod
+	Params          *url.Values
 }
 
 type PushedAuthorizationResponse struct {
@@ -20,24 +20,14 @@ type PushedAuthorizationResponse struct {
 	ExpiresIn  int    `json:"expires_in"`
 }
 
-func (c *Client) ExecutePushedAuthorizationRequest(ctx context.Context, endpoint string, req *PushedAuthorizationRequest) (*Response, error) {
-	headers := make(map[string]string)
+func (c *Client) ExecutePushedAuthorizationRequest(ctx context.Context, endpoint string, req *PushedAuthorizationRequest, headers map[string]string) (*Response, error) {
+	if headers == nil {
+		headers = make(map[string]string)
+	}
 
 	// Apply authentication method
-	switch req.AuthMethod {
-	case AuthMethodBasic:
-		// Use HTTP Basic Auth
-		auth := base64.StdEncoding.EncodeToString([]byte(req.ClientID + ":" + req.ClientSecret))
-		headers["Authorization"] = "Basic " + auth
-	case AuthMethodPost:
-		// Include credentials in request body
-		req.Params.Set("client_id", req.ClientID)
-		if req.ClientSecret != "" {
-			req.Params.Set("client_secret", req.ClientSecret)
-		}
-	case AuthMethodNone:
-		// Just include client_id in request body
-		req.Params.Set("client_id", req.ClientID)
+	if err := applyClientAuth(ctx, endpoint, *req.Params, headers, req.AuthMethod, req.ClientID, req.ClientSecret, req.ClientAssertion); err != nil {
+		return nil, err
 	}
 
 	// Execute the request
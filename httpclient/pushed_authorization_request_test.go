@@ -132,7 +132,7 @@ func TestExecutePushedAuthorizationRequest(t *testing.T) {
 			defer ts.Close()
 
 			client := NewClient(nil)
-			resp, err := client.ExecutePushedAuthorizationRequest(context.Background(), ts.URL, tt.req)
+			resp, err := client.ExecutePushedAuthorizationRequest(context.Background(), ts.URL, tt.req, nil)
 			if err != nil {
 				t.Fatalf("Unexpected error: %v", err)
 			}
@@ -300,7 +300,7 @@ func TestPushedAuthorizationRequest_Integration(t *testing.T) {
 	}
 
 	// Execute the PAR request
-	resp, err := client.ExecutePushedAuthorizationRequest(context.Background(), ts.URL, req)
+	resp, err := client.ExecutePushedAuthorizationRequest(context.Background(), ts.URL, req, nil)
 	if err != nil {
 		t.Fatalf("Failed to execute PAR request: %v", err)
 	}
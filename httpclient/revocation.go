@@ -0,0 +1,52 @@
+package httpclient
+
+import (
+	"context"
+	"net/url"
+)
+
+type RevocationRequest struct {
+	Token           string
+	TokenTypeHint   string
+	CustomArgs      *CustomArgs
+	AuthMethod      AuthMethod
+	ClientID        string
+	ClientSecret    string
+	ClientAssertion *ClientAssertion
+}
+
+// ExecuteRevocationRequest sends a token revocation request (RFC 7009 section 2.1)
+func (c *Client) ExecuteRevocationRequest(ctx context.Context, endpoint string, req *RevocationRequest, headers map[string]string) (*Response, error) {
+	if headers == nil {
+		headers = make(map[string]string)
+	}
+
+	params := url.Values{}
+	params.Set("token", req.Token)
+	if req.TokenTypeHint != "" {
+		params.Set("token_type_hint", req.TokenTypeHint)
+	}
+	// Add custom args
+	if req.CustomArgs != nil {
+		for k, v := range *req.CustomArgs {
+			params.Set(k, v)
+		}
+	}
+
+	// Apply authentication method
+	if err := applyClientAuth(ctx, endpoint, params, headers, req.AuthMethod, req.ClientID, req.ClientSecret, req.ClientAssertion); err != nil {
+		return nil, err
+	}
+
+	// Execute the request
+	return c.PostForm(ctx, endpoint, params, headers)
+}
+
+// ParseRevocationResponse checks the revocation response for errors.
+// A successful revocation has no meaningful response body (RFC 7009 section 2.2).
+func ParseRevocationResponse(resp *Response) error {
+	if resp.IsSuccess() {
+		return nil
+	}
+	return responseError(resp)
+}
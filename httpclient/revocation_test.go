@@ -0,0 +1,165 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestExecuteRevocationRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        *RevocationRequest
+		wantParams map[string]string
+		wantAuth   string
+	}{
+		{
+			name: "basic auth method",
+			req: &RevocationRequest{
+				Token:         "refresh-token",
+				TokenTypeHint: "refresh_token",
+				ClientID:      "test-client",
+				ClientSecret:  "test-secret",
+				AuthMethod:    AuthMethodBasic,
+			},
+			wantParams: map[string]string{
+				"token":           "refresh-token",
+				"token_type_hint": "refresh_token",
+			},
+			wantAuth: "Basic dGVzdC1jbGllbnQ6dGVzdC1zZWNyZXQ=", // base64 of test-client:test-secret
+		},
+		{
+			name: "post auth method",
+			req: &RevocationRequest{
+				Token:        "access-token",
+				ClientID:     "test-client",
+				ClientSecret: "test-secret",
+				AuthMethod:   AuthMethodPost,
+				CustomArgs:   &CustomArgs{"foo": "bar"},
+			},
+			wantParams: map[string]string{
+				"token":           "access-token",
+				"token_type_hint": "",
+				"client_id":       "test-client",
+				"client_secret":   "test-secret",
+				"foo":             "bar",
+			},
+		},
+		{
+			name: "none auth method",
+			req: &RevocationRequest{
+				Token:      "access-token",
+				ClientID:   "public-client",
+				AuthMethod: AuthMethodNone,
+			},
+			wantParams: map[string]string{
+				"token":         "access-token",
+				"client_id":     "public-client",
+				"client_secret": "",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost {
+					t.Errorf("Expected POST method, got %s", r.Method)
+				}
+
+				// Check auth header if expected
+				if tt.wantAuth != "" {
+					gotAuth := r.Header.Get("Authorization")
+					if gotAuth != tt.wantAuth {
+						t.Errorf("got Authorization header %q, want %q", gotAuth, tt.wantAuth)
+					}
+				}
+
+				// Parse form and check parameters
+				_ = r.ParseForm()
+				for key, want := range tt.wantParams {
+					got := r.FormValue(key)
+					if got != want {
+						t.Errorf("got param %s=%q, want %q", key, got, want)
+					}
+				}
+
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer ts.Close()
+
+			client := NewClient(nil)
+			resp, err := client.ExecuteRevocationRequest(context.Background(), ts.URL, tt.req, nil)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if !resp.IsSuccess() {
+				t.Errorf("Expected successful response, got status %d", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestParseRevocationResponse(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		wantErr    bool
+		wantErrMsg string
+	}{
+		{
+			name:       "successful response with empty body",
+			statusCode: 200,
+			body:       ``,
+		},
+		{
+			name:       "successful response with unexpected body",
+			statusCode: 200,
+			body:       `not json`,
+		},
+		{
+			name:       "oauth2 error response",
+			statusCode: 400,
+			body:       `{"error":"unsupported_token_type","error_description":"Token type not supported"}`,
+			wantErr:    true,
+			wantErrMsg: "oauth protocol error",
+		},
+		{
+			name:       "service unavailable",
+			statusCode: 503,
+			body:       `Service Unavailable`,
+			wantErr:    true,
+			wantErrMsg: "oauth http failure",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &Response{
+				StatusCode: tt.statusCode,
+				Body:       []byte(tt.body),
+			}
+
+			err := ParseRevocationResponse(resp)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected error, got nil")
+					return
+				}
+				if !strings.Contains(err.Error(), tt.wantErrMsg) {
+					t.Errorf("Expected error containing %q, got %q", tt.wantErrMsg, err.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
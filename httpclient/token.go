@@ -2,18 +2,19 @@ package httpclient
 
 import (
 	"context"
-	"encoding/base64"
 	"fmt"
 	"net/url"
 )
 
 // TokenRequest represents an OAuth2 token request
 type TokenRequest struct {
-	GrantType    string
-	ClientID     string
-	ClientSecret string
-	AuthMethod   AuthMethod
-	Params       url.Values
+	GrantType       string
+	ClientID        string
+	ClientSecret    string
+	ClientAssertion *ClientAssertion
+	AuthMethod      AuthMethod
+	Resources       []string // resource indicators (RFC 8707 section 2.2)
+	Params          url.Values
 }
 
 // ExecuteTokenRequest sends a token request to the specified endpoint
@@ -28,22 +29,13 @@ func (c *Client) ExecuteTokenRequest(ctx context.Context, tokenEndpoint string,
 
 	// Set grant type
 	req.Params.Set("grant_type", req.GrantType)
+	if len(req.Resources) > 0 {
+		req.Params["resource"] = req.Resources
+	}
 
 	// Apply authentication method
-	switch req.AuthMethod {
-	case AuthMethodBasic:
-		// Use HTTP Basic Auth
-		auth := base64.StdEncoding.EncodeToString([]byte(req.ClientID + ":" + req.ClientSecret))
-		headers["Authorization"] = "Basic " + auth
-	case AuthMethodPost:
-		// Include credentials in request body
-		req.Params.Set("client_id", req.ClientID)
-		if req.ClientSecret != "" {
-			req.Params.Set("client_secret", req.ClientSecret)
-		}
-	case AuthMethodNone:
-		// Just include client_id in request body
-		req.Params.Set("client_id", req.ClientID)
+	if err := applyClientAuth(ctx, tokenEndpoint, req.Params, headers, req.AuthMethod, req.ClientID, req.ClientSecret, req.ClientAssertion); err != nil {
+		return nil, err
 	}
 
 	// Execute the request
@@ -101,6 +93,24 @@ func CreateClientCredentialsRequest(clientID, clientSecret string, authMethod Au
 	}
 }
 
+// CreatePasswordRequest creates a token request for the resource owner password credentials grant (RFC 6749 section 4.3)
+func CreatePasswordRequest(clientID, clientSecret string, authMethod AuthMethod, username, password, scope string) *TokenRequest {
+	params := url.Values{}
+	params.Set("username", username)
+	params.Set("password", password)
+	if scope != "" {
+		params.Set("scope", scope)
+	}
+
+	return &TokenRequest{
+		GrantType:    "password",
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		AuthMethod:   authMethod,
+		Params:       params,
+	}
+}
+
 // CreateDeviceCodeTokenRequest creates a token request for the device code grant
 func CreateDeviceCodeTokenRequest(clientID, clientSecret string, authMethod AuthMethod, deviceCode string) *TokenRequest {
 	params := url.Values{}
@@ -115,6 +125,26 @@ func CreateDeviceCodeTokenRequest(clientID, clientSecret string, authMethod Auth
 	}
 }
 
+// GrantTypeJWTBearer is the grant type for using a JWT as an authorization grant (RFC 7523 section 2.1)
+const GrantTypeJWTBearer = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+
+// CreateJWTBearerRequest creates a token request for the JWT bearer grant
+func CreateJWTBearerRequest(clientID, clientSecret string, authMethod AuthMethod, assertion, scope string) *TokenRequest {
+	params := url.Values{}
+	params.Set("assertion", assertion)
+	if scope != "" {
+		params.Set("scope", scope)
+	}
+
+	return &TokenRequest{
+		GrantType:    GrantTypeJWTBearer,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		AuthMethod:   authMethod,
+		Params:       params,
+	}
+}
+
 // ParseTokenResponse parses the standard OAuth2 token response
 func ParseTokenResponse(resp *Response) (map[string]interface{}, error) {
 	var tokenResp map[string]interface{}
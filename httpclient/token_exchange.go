@@ -0,0 +1,80 @@
+package httpclient
+
+import (
+	"net/url"
+	"strings"
+)
+
+// GrantTypeTokenExchange is the grant type of token exchange requests (RFC 8693 section 2.1)
+const GrantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+
+// Token type identifiers (RFC 8693 section 3)
+const (
+	TokenTypeIdentifierAccessToken  = "urn:ietf:params:oauth:token-type:access_token"
+	TokenTypeIdentifierRefreshToken = "urn:ietf:params:oauth:token-type:refresh_token"
+	TokenTypeIdentifierIDToken      = "urn:ietf:params:oauth:token-type:id_token"
+	TokenTypeIdentifierSAML1        = "urn:ietf:params:oauth:token-type:saml1"
+	TokenTypeIdentifierSAML2        = "urn:ietf:params:oauth:token-type:saml2"
+	TokenTypeIdentifierJWT          = "urn:ietf:params:oauth:token-type:jwt"
+)
+
+var tokenTypeIdentifiers = map[string]string{
+	"access_token":  TokenTypeIdentifierAccessToken,
+	"refresh_token": TokenTypeIdentifierRefreshToken,
+	"id_token":      TokenTypeIdentifierIDToken,
+	"saml1":         TokenTypeIdentifierSAML1,
+	"saml2":         TokenTypeIdentifierSAML2,
+	"jwt":           TokenTypeIdentifierJWT,
+}
+
+// ExpandTokenType expands a short token type such as access_token or jwt to its
+// token type identifier URI. Other values are returned unchanged.
+func ExpandTokenType(tokenType string) string {
+	if uri, ok := tokenTypeIdentifiers[strings.ToLower(tokenType)]; ok {
+		return uri
+	}
+	return tokenType
+}
+
+// TokenExchange holds the parameters of a token exchange request
+type TokenExchange struct {
+	SubjectToken       string
+	SubjectTokenType   string
+	ActorToken         string
+	ActorTokenType     string
+	RequestedTokenType string
+	Audiences          []string
+	Resources          []string
+	Scope              string
+}
+
+// CreateTokenExchangeRequest creates a token request for the token exchange grant (RFC 8693 section 2.1)
+func CreateTokenExchangeRequest(clientID, clientSecret string, authMethod AuthMethod, exchange *TokenExchange) *TokenRequest {
+	params := url.Values{}
+	params.Set("subject_token", exchange.SubjectToken)
+	params.Set("subject_token_type", exchange.SubjectTokenType)
+	if exchange.ActorToken != "" {
+		params.Set("actor_token", exchange.ActorToken)
+		params.Set("actor_token_type", exchange.ActorTokenType)
+	}
+	if exchange.RequestedTokenType != "" {
+		params.Set("requested_token_type", exchange.RequestedTokenType)
+	}
+	for _, audience := range exchange.Audiences {
+		params.Add("audience", audience)
+	}
+	for _, resource := range exchange.Resources {
+		params.Add("resource", resource)
+	}
+	if exchange.Scope != "" {
+		params.Set("scope", exchange.Scope)
+	}
+
+	return &TokenRequest{
+		GrantType:    GrantTypeTokenExchange,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		AuthMethod:   authMethod,
+		Params:       params,
+	}
+}
@@ -0,0 +1,61 @@
+package httpclient
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCreateTokenExchangeRequest(t *testing.T) {
+	req := CreateTokenExchangeRequest("client-id", "client-secret", AuthMethodBasic, &TokenExchange{
+		SubjectToken:       "subject",
+		SubjectTokenType:   TokenTypeIdentifierAccessToken,
+		ActorToken:         "actor",
+		ActorTokenType:     TokenTypeIdentifierJWT,
+		RequestedTokenType: TokenTypeIdentifierRefreshToken,
+		Audiences:          []string{"svc-a", "svc-b"},
+		Resources:          []string{"https://api.example.com"},
+		Scope:              "read",
+	})
+
+	if req.GrantType != GrantTypeTokenExchange {
+		t.Errorf("got GrantType %q, want %q", req.GrantType, GrantTypeTokenExchange)
+	}
+	want := map[string][]string{
+		"subject_token":        {"subject"},
+		"subject_token_type":   {TokenTypeIdentifierAccessToken},
+		"actor_token":          {"actor"},
+		"actor_token_type":     {TokenTypeIdentifierJWT},
+		"requested_token_type": {TokenTypeIdentifierRefreshToken},
+		"audience":             {"svc-a", "svc-b"},
+		"resource":             {"https://api.example.com"},
+		"scope":                {"read"},
+	}
+	for key, values := range want {
+		if got := req.Params[key]; !reflect.DeepEqual(got, values) {
+			t.Errorf("got param %s=%v, want %v", key, got, values)
+		}
+	}
+
+	req = CreateTokenExchangeRequest("client-id", "", AuthMethodNone, &TokenExchange{
+		SubjectToken:     "subject",
+		SubjectTokenType: TokenTypeIdentifierIDToken,
+	})
+	for _, key := range []string{"actor_token", "actor_token_type", "requested_token_type", "audience", "resource", "scope"} {
+		if req.Params.Has(key) {
+			t.Errorf("unexpected param %s", key)
+		}
+	}
+}
+
+func TestExpandTokenType(t *testing.T) {
+	tests := map[string]string{
+		"access_token":                  TokenTypeIdentifierAccessToken,
+		"JWT":                           TokenTypeIdentifierJWT,
+		"urn:example:token-type:custom": "urn:example:token-type:custom",
+	}
+	for in, want := range tests {
+		if got := ExpandTokenType(in); got != want {
+			t.Errorf("ExpandTokenType(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
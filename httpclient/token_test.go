@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -103,6 +104,28 @@ func TestExecuteTokenRequest(t *testing.T) {
 	}
 }
 
+func TestExecuteTokenRequestResources(t *testing.T) {
+	want := []string{"https://api.example.com", "https://payments.example.com"}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if got := r.PostForm["resource"]; !reflect.DeepEqual(got, want) {
+			t.Errorf("got resource %v, want %v", got, want)
+		}
+		_, _ = w.Write([]byte(`{"access_token":"token123","token_type":"Bearer"}`))
+	}))
+	defer ts.Close()
+
+	req := CreateClientCredentialsRequest("test-client", "test-secret", AuthMethodBasic, "")
+	req.Resources = want
+	client := NewClient(nil)
+	// The request is sent twice, as when it is retried, without repeating the resources
+	for range 2 {
+		if _, err := client.ExecuteTokenRequest(context.Background(), ts.URL, req, nil); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+}
+
 func TestCreateAuthCodeTokenRequest(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -291,6 +314,23 @@ func TestCreateClientCredentialsRequest(t *testing.T) {
 	}
 }
 
+func TestCreatePasswordRequest(t *testing.T) {
+	req := CreatePasswordRequest("legacy-client", "", AuthMethodNone, "alice", "s3cret", "openid")
+
+	if req.GrantType != "password" {
+		t.Errorf("got GrantType %q, want %q", req.GrantType, "password")
+	}
+	if got := req.Params.Get("username"); got != "alice" {
+		t.Errorf("got username %q, want %q", got, "alice")
+	}
+	if got := req.Params.Get("password"); got != "s3cret" {
+		t.Errorf("got password %q, want %q", got, "s3cret")
+	}
+	if got := req.Params.Get("scope"); got != "openid" {
+		t.Errorf("got scope %q, want %q", got, "openid")
+	}
+}
+
 func TestCreateDeviceCodeTokenRequest(t *testing.T) {
 	req := CreateDeviceCodeTokenRequest("device-client", "device-secret", AuthMethodBasic, "device123")
 
@@ -319,6 +359,20 @@ func TestCreateDeviceCodeTokenRequest(t *testing.T) {
 	}
 }
 
+func TestCreateJWTBearerRequest(t *testing.T) {
+	req := CreateJWTBearerRequest("", "", AuthMethodNone, "assertion-jwt", "read")
+
+	if req.GrantType != GrantTypeJWTBearer {
+		t.Errorf("got GrantType %q, want %q", req.GrantType, GrantTypeJWTBearer)
+	}
+	if got := req.Params.Get("assertion"); got != "assertion-jwt" {
+		t.Errorf("got assertion %q, want %q", got, "assertion-jwt")
+	}
+	if got := req.Params.Get("scope"); got != "read" {
+		t.Errorf("got scope %q, want %q", got, "read")
+	}
+}
+
 func TestParseTokenResponse(t *testing.T) {
 	tests := []struct {
 		name       string
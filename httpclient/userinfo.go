@@ -0,0 +1,89 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+const (
+	TokenTypeBearer = "Bearer"
+	TokenTypeDPoP   = "DPoP"
+)
+
+// UserinfoRequest represents a request to the OpenID Connect UserInfo endpoint
+type UserinfoRequest struct {
+	AccessToken string
+	TokenType   string // Bearer (default) or DPoP, the DPoP proof is passed in the headers
+}
+
+// UserinfoResponse holds the claims returned by the UserInfo endpoint.
+// When the endpoint responds with application/jwt, JWT holds the signed and/or encrypted token
+// and Claims is left empty for the caller to fill in after verification.
+type UserinfoResponse struct {
+	Claims map[string]interface{}
+	JWT    string
+}
+
+// ExecuteUserinfoRequest sends the access token to the UserInfo endpoint
+func (c *Client) ExecuteUserinfoRequest(ctx context.Context, endpoint string, req *UserinfoRequest, headers map[string]string) (*Response, error) {
+	if req.AccessToken == "" {
+		return nil, errors.New("access token is required")
+	}
+
+	if headers == nil {
+		headers = make(map[string]string)
+	}
+
+	tokenType := req.TokenType
+	if tokenType == "" {
+		tokenType = TokenTypeBearer
+	}
+	headers["Authorization"] = tokenType + " " + req.AccessToken
+	headers["Accept"] = "application/json, application/jwt"
+
+	return c.Do(ctx, http.MethodGet, endpoint, nil, headers)
+}
+
+// ParseUserinfoResponse parses a UserInfo response. Errors signalled through the
+// WWW-Authenticate header are returned as a *ChallengeError.
+func ParseUserinfoResponse(resp *Response) (*UserinfoResponse, error) {
+	if !resp.IsSuccess() {
+		if challenges := ParseWWWAuthenticate(resp.Headers.Values("WWW-Authenticate")); len(challenges) > 0 {
+			return nil, fmt.Errorf("%w: %w", ErrOAuthError, &ChallengeError{
+				StatusCode: resp.StatusCode,
+				Challenges: challenges,
+			})
+		}
+
+		oauth2Err := &Error{
+			StatusCode: resp.StatusCode,
+			RawBody:    resp.String(),
+		}
+		var errResp map[string]interface{}
+		if err := resp.JSON(&errResp); err == nil {
+			if errStr, ok := errResp["error"].(string); ok {
+				oauth2Err.ErrorType = errStr
+				if desc, ok := errResp["error_description"].(string); ok {
+					oauth2Err.ErrorDescription = desc
+				}
+				return nil, fmt.Errorf("%w: %v", ErrOAuthError, oauth2Err)
+			}
+		}
+		return nil, fmt.Errorf("%w: %v", ErrHTTPFailure, oauth2Err)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Headers.Get("Content-Type"))
+	if mediaType == "application/jwt" {
+		return &UserinfoResponse{JWT: strings.TrimSpace(resp.String())}, nil
+	}
+
+	var claims map[string]interface{}
+	if err := resp.JSON(&claims); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrParsingJSON, err)
+	}
+	return &UserinfoResponse{Claims: claims}, nil
+}
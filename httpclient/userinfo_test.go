@@ -0,0 +1,160 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestExecuteUserinfoRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      *UserinfoRequest
+		headers  map[string]string
+		wantAuth string
+		wantDPoP string
+	}{
+		{
+			name:     "bearer token by default",
+			req:      &UserinfoRequest{AccessToken: "access-token"},
+			wantAuth: "Bearer access-token",
+		},
+		{
+			name:     "dpop token with proof",
+			req:      &UserinfoRequest{AccessToken: "access-token", TokenType: TokenTypeDPoP},
+			headers:  map[string]string{"DPoP": "proof"},
+			wantAuth: "DPoP access-token",
+			wantDPoP: "proof",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet {
+					t.Errorf("Expected GET method, got %s", r.Method)
+				}
+				if got := r.Header.Get("Authorization"); got != tt.wantAuth {
+					t.Errorf("got Authorization header %q, want %q", got, tt.wantAuth)
+				}
+				if got := r.Header.Get("DPoP"); got != tt.wantDPoP {
+					t.Errorf("got DPoP header %q, want %q", got, tt.wantDPoP)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"sub":"alice"}`))
+			}))
+			defer ts.Close()
+
+			client := NewClient(nil)
+			resp, err := client.ExecuteUserinfoRequest(context.Background(), ts.URL, tt.req, tt.headers)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !resp.IsSuccess() {
+				t.Errorf("Expected successful response, got status %d", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestExecuteUserinfoRequestMissingToken(t *testing.T) {
+	client := NewClient(nil)
+	if _, err := client.ExecuteUserinfoRequest(context.Background(), "http://localhost", &UserinfoRequest{}, nil); err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
+
+func TestParseUserinfoResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		statusCode  int
+		contentType string
+		wwwAuth     []string
+		body        string
+		want        *UserinfoResponse
+		wantErr     error
+		wantChall   []Challenge
+	}{
+		{
+			name:        "json claims",
+			statusCode:  200,
+			contentType: "application/json; charset=utf-8",
+			body:        `{"sub":"alice","email":"alice@example.com"}`,
+			want:        &UserinfoResponse{Claims: map[string]interface{}{"sub": "alice", "email": "alice@example.com"}},
+		},
+		{
+			name:        "jwt response",
+			statusCode:  200,
+			contentType: "application/jwt",
+			body:        "header.payload.signature\n",
+			want:        &UserinfoResponse{JWT: "header.payload.signature"},
+		},
+		{
+			name:        "invalid json",
+			statusCode:  200,
+			contentType: "application/json",
+			body:        `not json`,
+			wantErr:     ErrParsingJSON,
+		},
+		{
+			name:       "www-authenticate challenge",
+			statusCode: 401,
+			wwwAuth:    []string{`Bearer error="invalid_token", error_description="expired"`},
+			wantErr:    ErrOAuthError,
+			wantChall: []Challenge{
+				{Scheme: "Bearer", Params: map[string]string{"error": "invalid_token", "error_description": "expired"}},
+			},
+		},
+		{
+			name:       "oauth2 error body",
+			statusCode: 400,
+			body:       `{"error":"invalid_request"}`,
+			wantErr:    ErrOAuthError,
+		},
+		{
+			name:       "server error",
+			statusCode: 500,
+			body:       `Internal Server Error`,
+			wantErr:    ErrHTTPFailure,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := http.Header{}
+			if tt.contentType != "" {
+				headers.Set("Content-Type", tt.contentType)
+			}
+			for _, v := range tt.wwwAuth {
+				headers.Add("WWW-Authenticate", v)
+			}
+			resp := &Response{StatusCode: tt.statusCode, Headers: headers, Body: []byte(tt.body)}
+
+			got, err := ParseUserinfoResponse(resp)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseUserinfoResponse() error = %v, want %v", err, tt.wantErr)
+				}
+				if tt.wantChall != nil {
+					var challengeErr *ChallengeError
+					if !errors.As(err, &challengeErr) {
+						t.Fatalf("ParseUserinfoResponse() error = %v, want ChallengeError", err)
+					}
+					if !reflect.DeepEqual(challengeErr.Challenges, tt.wantChall) {
+						t.Errorf("Challenges = %v, want %v", challengeErr.Challenges, tt.wantChall)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseUserinfoResponse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
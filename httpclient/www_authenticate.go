@@ -0,0 +1,176 @@
+package httpclient
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Challenge is a single authentication challenge from a WWW-Authenticate header (RFC 9110 section 11.6.1).
+// Parameter names are lowercased, token68 credentials are stored under the empty name.
+type Challenge struct {
+	Scheme string            `json:"scheme"`
+	Params map[string]string `json:"params,omitempty"`
+}
+
+// Param returns the value of the named parameter, or an empty string if it is absent
+func (c *Challenge) Param(name string) string {
+	return c.Params[strings.ToLower(name)]
+}
+
+// ChallengeError is returned when a protected resource rejects a request with one or more
+// WWW-Authenticate challenges, e.g. an invalid_token or insufficient_scope error (RFC 6750 section 3).
+type ChallengeError struct {
+	StatusCode int
+	Challenges []Challenge
+}
+
+func (e *ChallengeError) Error() string {
+	for _, c := range e.Challenges {
+		if errType := c.Param("error"); errType != "" {
+			if desc := c.Param("error_description"); desc != "" {
+				return fmt.Sprintf("%s error: %s - %s, status: %d", c.Scheme, errType, desc, e.StatusCode)
+			}
+			return fmt.Sprintf("%s error: %s, status: %d", c.Scheme, errType, e.StatusCode)
+		}
+	}
+	schemes := make([]string, 0, len(e.Challenges))
+	for _, c := range e.Challenges {
+		schemes = append(schemes, c.Scheme)
+	}
+	return fmt.Sprintf("authentication required (%s), status: %d", strings.Join(schemes, ", "), e.StatusCode)
+}
+
+// ParseWWWAuthenticate parses the values of one or more WWW-Authenticate headers into challenges.
+// Malformed input is skipped rather than rejected so that as much information as possible is kept.
+func ParseWWWAuthenticate(values []string) []Challenge {
+	var challenges []Challenge
+	for _, value := range values {
+		p := &challengeParser{s: value}
+		challenges = append(challenges, p.parse()...)
+	}
+	return challenges
+}
+
+type challengeParser struct {
+	s   string
+	pos int
+}
+
+func (p *challengeParser) parse() []Challenge {
+	var challenges []Challenge
+	var current *Challenge
+	for {
+		p.skip(" \t,")
+		if p.pos >= len(p.s) {
+			break
+		}
+		name := p.token()
+		if name == "" {
+			// Not a token character, skip it
+			p.pos++
+			continue
+		}
+		p.skip(" \t")
+		if p.peek() == '=' && current != nil {
+			p.pos++
+			p.skip(" \t")
+			current.Params[strings.ToLower(name)] = p.value()
+			continue
+		}
+		if p.peek() == '=' {
+			// Parameter without a scheme, skip its value
+			p.pos++
+			p.skip(" \t")
+			p.value()
+			continue
+		}
+
+		challenges = append(challenges, Challenge{Scheme: name, Params: map[string]string{}})
+		current = &challenges[len(challenges)-1]
+		if t68, ok := p.token68(); ok {
+			current.Params[""] = t68
+		}
+	}
+	for i := range challenges {
+		if len(challenges[i].Params) == 0 {
+			challenges[i].Params = nil
+		}
+	}
+	return challenges
+}
+
+func (p *challengeParser) peek() byte {
+	if p.pos >= len(p.s) {
+		return 0
+	}
+	return p.s[p.pos]
+}
+
+func (p *challengeParser) skip(chars string) {
+	for p.pos < len(p.s) && strings.IndexByte(chars, p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *challengeParser) token() string {
+	start := p.pos
+	for p.pos < len(p.s) && isTokenChar(p.s[p.pos]) {
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+// token68 consumes a token68 credential if one follows the scheme
+func (p *challengeParser) token68() (string, bool) {
+	i := p.pos
+	for i < len(p.s) && isToken68Char(p.s[i]) {
+		i++
+	}
+	if i == p.pos {
+		return "", false
+	}
+	for i < len(p.s) && p.s[i] == '=' {
+		i++
+	}
+	end := i
+	for i < len(p.s) && (p.s[i] == ' ' || p.s[i] == '\t') {
+		i++
+	}
+	if i < len(p.s) && p.s[i] != ',' {
+		return "", false
+	}
+	value := p.s[p.pos:end]
+	p.pos = i
+	return value, true
+}
+
+func (p *challengeParser) value() string {
+	if p.peek() != '"' {
+		return p.token()
+	}
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.s) {
+		ch := p.s[p.pos]
+		p.pos++
+		switch {
+		case ch == '\\' && p.pos < len(p.s):
+			b.WriteByte(p.s[p.pos])
+			p.pos++
+		case ch == '"':
+			return b.String()
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	return b.String()
+}
+
+func isTokenChar(c byte) bool {
+	return c > ' ' && c < 0x7f && !strings.ContainsRune("\"(),/:;<=>?@[\\]{}", rune(c))
+}
+
+func isToken68Char(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') ||
+		strings.IndexByte("-._~+/", c) >= 0
+}
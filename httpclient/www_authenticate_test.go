@@ -0,0 +1,112 @@
+package httpclient
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseWWWAuthenticate(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   []Challenge
+	}{
+		{
+			name:   "bearer with error",
+			values: []string{`Bearer realm="example", error="invalid_token", error_description="The access token expired"`},
+			want: []Challenge{
+				{Scheme: "Bearer", Params: map[string]string{
+					"realm":             "example",
+					"error":             "invalid_token",
+					"error_description": "The access token expired",
+				}},
+			},
+		},
+		{
+			name:   "scheme without params",
+			values: []string{`Bearer`},
+			want:   []Challenge{{Scheme: "Bearer"}},
+		},
+		{
+			name:   "multiple challenges in one header",
+			values: []string{`DPoP algs="ES256 PS256", error=use_dpop_nonce, Bearer realm="example"`},
+			want: []Challenge{
+				{Scheme: "DPoP", Params: map[string]string{"algs": "ES256 PS256", "error": "use_dpop_nonce"}},
+				{Scheme: "Bearer", Params: map[string]string{"realm": "example"}},
+			},
+		},
+		{
+			name:   "multiple headers",
+			values: []string{`Bearer scope="openid profile"`, `DPoP algs="ES256"`},
+			want: []Challenge{
+				{Scheme: "Bearer", Params: map[string]string{"scope": "openid profile"}},
+				{Scheme: "DPoP", Params: map[string]string{"algs": "ES256"}},
+			},
+		},
+		{
+			name:   "token68",
+			values: []string{`Negotiate abc+/de==`},
+			want:   []Challenge{{Scheme: "Negotiate", Params: map[string]string{"": "abc+/de=="}}},
+		},
+		{
+			name:   "escaped quotes and mixed case names",
+			values: []string{`Bearer Error_Description = "say \"hi\"", ERROR=invalid_request`},
+			want: []Challenge{
+				{Scheme: "Bearer", Params: map[string]string{"error_description": `say "hi"`, "error": "invalid_request"}},
+			},
+		},
+		{
+			name:   "empty header",
+			values: []string{``},
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseWWWAuthenticate(tt.values)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseWWWAuthenticate() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChallengeError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *ChallengeError
+		want string
+	}{
+		{
+			name: "error with description",
+			err: &ChallengeError{StatusCode: 401, Challenges: []Challenge{
+				{Scheme: "Bearer", Params: map[string]string{"error": "invalid_token", "error_description": "expired"}},
+			}},
+			want: "Bearer error: invalid_token - expired, status: 401",
+		},
+		{
+			name: "error without description",
+			err: &ChallengeError{StatusCode: 403, Challenges: []Challenge{
+				{Scheme: "Bearer", Params: map[string]string{"error": "insufficient_scope"}},
+			}},
+			want: "Bearer error: insufficient_scope, status: 403",
+		},
+		{
+			name: "no error parameter",
+			err: &ChallengeError{StatusCode: 401, Challenges: []Challenge{
+				{Scheme: "Bearer"}, {Scheme: "DPoP"},
+			}},
+			want: "authentication required (Bearer, DPoP), status: 401",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); !strings.Contains(got, tt.want) {
+				t.Errorf("Error() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,7 +3,13 @@ package oidc
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/jentz/oidc-cli/crypto"
 	"github.com/jentz/oidc-cli/httpclient"
@@ -16,26 +22,37 @@ type AuthorizationCodeFlow struct {
 }
 
 type AuthorizationCodeFlowConfig struct {
-	Scopes      string
-	CallbackURI string
-	RedirectURI string
-	Prompt      string
-	AcrValues   string
-	LoginHint   string
-	MaxAge      string
-	UILocales   string
-	State       string
-	CustomArgs  *httpclient.CustomArgs
-	PKCE        bool
-	PAR         bool
-	DPoP        bool
+	Scopes               string
+	ResponseType         string // defaults to code, see ValidateResponseType
+	ResponseMode         string
+	CallbackURI          string
+	RedirectURI          string
+	Prompt               string
+	AcrValues            string
+	LoginHint            string
+	MaxAge               string
+	UILocales            string
+	AuthorizationDetails string // compact JSON, see ParseAuthorizationDetails
+	Resources            []string
+	Claims               ClaimsRequest
+	State                string
+	Nonce                string
+	ClockSkew            time.Duration
+	CustomArgs           *httpclient.CustomArgs
+	PKCE                 bool
+	PAR                  bool
+	DPoP                 bool
+	RevokeOnExit         bool
+
+	RequestObject        bool // send the parameters in a signed request object (RFC 9101)
+	EncryptRequestObject bool // encrypt the request object to the issuer's encryption key
 }
 
 func (c *AuthorizationCodeFlow) setupPKCE() (string, error) {
 	if !c.FlowConfig.PKCE {
 		return "", nil
 	}
-	if c.Config.ClientSecret == "" {
+	if !c.Config.hasClientCredentials() {
 		c.Config.AuthMethod = httpclient.AuthMethodNone
 	}
 	codeVerifier, err := crypto.GeneratePKCECodeVerifier()
@@ -46,17 +63,49 @@ func (c *AuthorizationCodeFlow) setupPKCE() (string, error) {
 }
 
 func (c *AuthorizationCodeFlow) createAuthCodeRequest(ctx context.Context, codeVerifier string) (*httpclient.AuthorizationCodeRequest, error) {
+	// Generate a state unless the user has set one, so that it is always verified on callback
+	state := c.FlowConfig.State
+	if state == "" {
+		var err error
+		state, err = crypto.GenerateRandomValue()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate state: %w", err)
+		}
+	}
+	// Likewise, always send a nonce so it can be checked in the ID token
+	nonce := c.FlowConfig.Nonce
+	if nonce == "" {
+		var err error
+		nonce, err = crypto.GenerateRandomValue()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate nonce: %w", err)
+		}
+	}
 	req := &httpclient.AuthorizationCodeRequest{
-		ClientID:    c.Config.ClientID,
-		Scope:       c.FlowConfig.Scopes,
-		RedirectURI: c.FlowConfig.RedirectURI,
-		Prompt:      c.FlowConfig.Prompt,
-		AcrValues:   c.FlowConfig.AcrValues,
-		LoginHint:   c.FlowConfig.LoginHint,
-		MaxAge:      c.FlowConfig.MaxAge,
-		UILocales:   c.FlowConfig.UILocales,
-		State:       c.FlowConfig.State,
-		CustomArgs:  c.FlowConfig.CustomArgs,
+		ClientID:             c.Config.ClientID,
+		ResponseType:         c.FlowConfig.ResponseType,
+		ResponseMode:         c.FlowConfig.ResponseMode,
+		Scope:                c.FlowConfig.Scopes,
+		RedirectURI:          c.FlowConfig.RedirectURI,
+		Prompt:               c.FlowConfig.Prompt,
+		AcrValues:            c.FlowConfig.AcrValues,
+		LoginHint:            c.FlowConfig.LoginHint,
+		MaxAge:               c.FlowConfig.MaxAge,
+		UILocales:            c.FlowConfig.UILocales,
+		AuthorizationDetails: c.FlowConfig.AuthorizationDetails,
+		Resources:            c.FlowConfig.Resources,
+		Claims:               c.FlowConfig.Claims.String(),
+		State:                state,
+		Nonce:                nonce,
+		CustomArgs:           c.FlowConfig.CustomArgs,
+		JARM: &httpclient.JARMVerifier{
+			Issuer:        c.Config.IssuerURL,
+			ClientID:      c.Config.ClientID,
+			ClientSecret:  c.Config.ClientSecret,
+			DecryptionKey: c.Config.PrivateKey,
+			Keys:          c.Config.JWKS,
+			ClockSkew:     c.FlowConfig.ClockSkew,
+		},
 	}
 	// If the user has not explicitly set a redirect URI, use the callback URI
 	if c.FlowConfig.RedirectURI == "" {
@@ -66,18 +115,30 @@ func (c *AuthorizationCodeFlow) createAuthCodeRequest(ctx context.Context, codeV
 		req.CodeChallenge = crypto.GeneratePKCECodeChallenge(codeVerifier)
 		req.CodeChallengeMethod = "S256"
 	}
+	if c.FlowConfig.RequestObject {
+		requestObject, err := c.createRequestObject(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		req.Request = requestObject
+	}
 	if c.FlowConfig.PAR {
 		parParams, err := httpclient.CreateAuthorizationCodeRequestValues(req)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create authorization code request values: %w", err)
 		}
 		parReq := &httpclient.PushedAuthorizationRequest{
-			ClientID:     c.Config.ClientID,
-			ClientSecret: c.Config.ClientSecret,
-			AuthMethod:   c.Config.AuthMethod,
-			Params:       parParams,
+			ClientID:        c.Config.ClientID,
+			ClientSecret:    c.Config.ClientSecret,
+			AuthMethod:      c.Config.AuthMethod,
+			ClientAssertion: c.Config.clientAssertion(),
+			Params:          parParams,
 		}
-		resp, err := c.Config.Client.ExecutePushedAuthorizationRequest(ctx, c.Config.PushedAuthorizationRequestEndpoint, parReq)
+		// A DPoP proof on the pushed request binds the authorization code to the DPoP key (RFC 9449 section 10.1)
+		endpoint := c.Config.PushedAuthorizationRequestEndpoint
+		resp, err := c.Config.Client.ExecuteWithDPoP(endpoint, c.dpopProof(endpoint), func(headers map[string]string) (*httpclient.Response, error) {
+			return c.Config.Client.ExecutePushedAuthorizationRequest(ctx, endpoint, parReq, headers)
+		})
 		if err != nil {
 			return nil, fmt.Errorf("pushed authorization request failed: %w", err)
 		}
@@ -86,6 +147,7 @@ func (c *AuthorizationCodeFlow) createAuthCodeRequest(ctx context.Context, codeV
 			return nil, httpclient.WrapError(err, "pushed authorization")
 		}
 		req.RequestURI = parResp.RequestURI
+		req.Request = "" // the request object has been pushed
 	}
 	return req, nil
 }
@@ -98,24 +160,36 @@ func (c *AuthorizationCodeFlow) executeAuthCodeRequest(ctx context.Context, req
 	return resp, nil
 }
 
-func (c *AuthorizationCodeFlow) setupDPoPHeaders() (map[string]string, error) {
-	headers := make(map[string]string)
-	if c.FlowConfig.DPoP {
-		dpopProof, err := crypto.NewDPoPProof(
-			c.Config.PublicKey,
-			c.Config.PrivateKey,
-			"POST",
-			c.Config.TokenEndpoint,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create DPoP proof: %w", err)
+// validateAuthResponse checks that the callback carries the state that was sent,
+// and the expected issuer when the authorization server identifies itself (RFC 9207).
+func (c *AuthorizationCodeFlow) validateAuthResponse(req *httpclient.AuthorizationCodeRequest, resp *httpclient.AuthorizationCodeResponse) error {
+	if resp.State != req.State {
+		return fmt.Errorf("state mismatch: sent %q but callback returned %q", req.State, resp.State)
+	}
+	log.Printf("state verified\n")
+
+	switch {
+	case resp.Issuer != "":
+		if resp.Issuer != c.Config.IssuerURL {
+			return fmt.Errorf("issuer mismatch: callback returned iss %q but expected %q", resp.Issuer, c.Config.IssuerURL)
 		}
-		headers["DPoP"] = dpopProof.String()
+		log.Printf("iss verified\n")
+	case c.Config.AuthorizationResponseIssSupported:
+		return errors.New("callback is missing the iss parameter although the issuer advertises authorization_response_iss_parameter_supported")
 	}
-	return headers, nil
+	return nil
 }
 
-func (c *AuthorizationCodeFlow) executeTokenRequest(ctx context.Context, code, codeVerifier string, headers map[string]string) (map[string]interface{}, error) {
+// dpopProof returns the function creating the DPoP proofs for a POST to the endpoint,
+// or nil if DPoP is not used
+func (c *AuthorizationCodeFlow) dpopProof(endpoint string) httpclient.DPoPProofFunc {
+	if !c.FlowConfig.DPoP {
+		return nil
+	}
+	return c.Config.dpopProof(http.MethodPost, endpoint, "")
+}
+
+func (c *AuthorizationCodeFlow) executeTokenRequest(ctx context.Context, code, codeVerifier string) (map[string]interface{}, error) {
 	tokenRequest := httpclient.CreateAuthCodeTokenRequest(
 		c.Config.ClientID,
 		c.Config.ClientSecret,
@@ -124,7 +198,11 @@ func (c *AuthorizationCodeFlow) executeTokenRequest(ctx context.Context, code, c
 		c.FlowConfig.CallbackURI,
 		codeVerifier,
 	)
-	resp, err := c.Config.Client.ExecuteTokenRequest(ctx, c.Config.TokenEndpoint, tokenRequest, headers)
+	tokenRequest.ClientAssertion = c.Config.clientAssertion()
+	tokenRequest.Resources = c.FlowConfig.Resources
+	resp, err := c.Config.Client.ExecuteWithDPoP(c.Config.TokenEndpoint, c.dpopProof(c.Config.TokenEndpoint), func(headers map[string]string) (*httpclient.Response, error) {
+		return c.Config.Client.ExecuteTokenRequest(ctx, c.Config.TokenEndpoint, tokenRequest, headers)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("token request failed: %w", err)
 	}
@@ -151,13 +229,33 @@ func (c *AuthorizationCodeFlow) Run(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	// Handle DPoP
-	headers, err := c.setupDPoPHeaders()
-	if err != nil {
+	if err := c.validateAuthResponse(authCodeReq, authResp); err != nil {
 		return err
 	}
+	// The implicit and hybrid flows return tokens from the authorization endpoint
+	frontChannel := frontChannelResponse(authResp)
+	frontChannelErr := c.validateFrontChannelIDToken(ctx, authCodeReq, authResp)
+	if !ResponseTypeIncludes(c.FlowConfig.ResponseType, "code") {
+		prettyJSON, err := json.MarshalIndent(frontChannel, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format authorization response: %w", err)
+		}
+		log.Outputf("%s\n", string(prettyJSON))
+		c.reportRequestedClaims(ctx, authResp.IDToken, authResp.AccessToken)
+		return frontChannelErr
+	}
+	if authResp.Code == "" {
+		return errors.New("authorization response is missing the code although the response type includes code")
+	}
+	if len(frontChannel) > 0 {
+		prettyJSON, err := json.MarshalIndent(frontChannel, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format authorization response: %w", err)
+		}
+		log.Errorf("front-channel response:\n%s\n", string(prettyJSON))
+	}
 	// Exchange authorization code for access token
-	tokenData, err := c.executeTokenRequest(ctx, authResp.Code, codeVerifier, headers)
+	tokenData, err := c.executeTokenRequest(ctx, authResp.Code, codeVerifier)
 	if err != nil {
 		return err
 	}
@@ -168,5 +266,114 @@ func (c *AuthorizationCodeFlow) Run(ctx context.Context) error {
 		return fmt.Errorf("failed to format token response: %w", err)
 	}
 	log.Outputf("%s\n", string(prettyJSON))
+	reportAuthorizationDetails(c.FlowConfig.AuthorizationDetails, tokenData)
+	warnAudienceMismatch(c.FlowConfig.Resources, tokenData)
+
+	// Validate the ID token after printing, so the response can be inspected even if validation fails
+	validationErr := errors.Join(
+		frontChannelErr,
+		c.Config.checkCertificateBinding(tokenData),
+		c.validateIDToken(ctx, authCodeReq, tokenData),
+	)
+
+	idToken, _ := tokenData["id_token"].(string)
+	if idToken == "" {
+		idToken = authResp.IDToken
+	}
+	accessToken, _ := tokenData["access_token"].(string)
+	c.reportRequestedClaims(ctx, idToken, accessToken)
+
+	if c.FlowConfig.RevokeOnExit {
+		if err := c.revokeRefreshToken(ctx, tokenData); err != nil {
+			return errors.Join(validationErr, err)
+		}
+	}
+	return validationErr
+}
+
+// validateIDToken validates the ID token from the token response and prints a report of each check
+func (c *AuthorizationCodeFlow) validateIDToken(ctx context.Context, req *httpclient.AuthorizationCodeRequest, tokenData map[string]interface{}) error {
+	idToken, ok := tokenData["id_token"].(string)
+	if !ok || idToken == "" {
+		if slices.Contains(strings.Fields(req.Scope), "openid") {
+			return errors.New("token response is missing the id_token although the openid scope was requested")
+		}
+		return nil
+	}
+
+	accessToken, _ := tokenData["access_token"].(string)
+	report, _ := c.Config.validateIDToken(ctx, idToken, &idTokenExpectations{
+		Nonce:       req.Nonce,
+		AccessToken: accessToken,
+		MaxAge:      req.MaxAge,
+		ClockSkew:   c.FlowConfig.ClockSkew,
+	})
+	report.Print()
+	return report.Err()
+}
+
+// frontChannelResponse returns the tokens from the authorization response in the form of a token response
+func frontChannelResponse(resp *httpclient.AuthorizationCodeResponse) map[string]interface{} {
+	data := make(map[string]interface{})
+	for name, value := range map[string]string{
+		"id_token":     resp.IDToken,
+		"access_token": resp.AccessToken,
+		"token_type":   resp.TokenType,
+		"scope":        resp.Scope,
+	} {
+		if value != "" {
+			data[name] = value
+		}
+	}
+	if expiresIn, err := strconv.Atoi(resp.ExpiresIn); err == nil {
+		data["expires_in"] = expiresIn
+	}
+	if resp.AccessToken == "" && resp.IDToken == "" {
+		return nil
+	}
+	return data
+}
+
+// validateFrontChannelIDToken validates the ID token from the authorization response, including
+// the c_hash and at_hash of the code and access token issued with it, and prints a report of each check
+func (c *AuthorizationCodeFlow) validateFrontChannelIDToken(ctx context.Context, req *httpclient.AuthorizationCodeRequest, resp *httpclient.AuthorizationCodeResponse) error {
+	if resp.IDToken == "" {
+		if ResponseTypeIncludes(req.ResponseType, "id_token") {
+			return errors.New("authorization response is missing the id_token although the response type includes id_token")
+		}
+		return nil
+	}
+
+	report, _ := c.Config.validateIDToken(ctx, resp.IDToken, &idTokenExpectations{
+		Nonce:        req.Nonce,
+		AccessToken:  resp.AccessToken,
+		Code:         resp.Code,
+		MaxAge:       req.MaxAge,
+		ClockSkew:    c.FlowConfig.ClockSkew,
+		FrontChannel: true,
+	})
+	report.Print()
+	return report.Err()
+}
+
+// revokeRefreshToken revokes the refresh token from the token response, if any,
+// so that test sessions do not leave refresh tokens behind.
+func (c *AuthorizationCodeFlow) revokeRefreshToken(ctx context.Context, tokenData map[string]interface{}) error {
+	refreshToken, ok := tokenData["refresh_token"].(string)
+	if !ok || refreshToken == "" {
+		log.Printf("no refresh token to revoke\n")
+		return nil
+	}
+	revokeFlow := &RevokeFlow{
+		Config: c.Config,
+		FlowConfig: &RevokeFlowConfig{
+			Token:         refreshToken,
+			TokenTypeHint: "refresh_token",
+		},
+	}
+	if err := revokeFlow.revoke(ctx); err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+	log.Printf("refresh token revoked\n")
 	return nil
 }
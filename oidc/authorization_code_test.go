@@ -0,0 +1,65 @@
+package oidc
+
+import (
+	"testing"
+
+	"github.com/jentz/oidc-cli/httpclient"
+)
+
+func TestValidateAuthResponse(t *testing.T) {
+	tests := []struct {
+		name         string
+		issSupported bool
+		state        string
+		issuer       string
+		wantErr      bool
+	}{
+		{"matching state without iss", false, "state-123", "", false},
+		{"matching state and iss", true, "state-123", "https://example.com", false},
+		{"state mismatch", false, "other", "", true},
+		{"missing state", false, "", "", true},
+		{"iss mismatch", false, "state-123", "https://evil.example.com", true},
+		{"iss missing but advertised", true, "state-123", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flow := &AuthorizationCodeFlow{
+				Config: &Config{
+					IssuerURL:                         "https://example.com",
+					AuthorizationResponseIssSupported: tt.issSupported,
+				},
+				FlowConfig: &AuthorizationCodeFlowConfig{},
+			}
+			req := &httpclient.AuthorizationCodeRequest{State: "state-123"}
+			resp := &httpclient.AuthorizationCodeResponse{Code: "code", State: tt.state, Issuer: tt.issuer}
+			err := flow.validateAuthResponse(req, resp)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateAuthResponse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetupPKCEPublicClientWithDPoP(t *testing.T) {
+	// A public client's DPoP key must not be used for private_key_jwt, even if the server supports it
+	config := &Config{ClientID: "client-id", PrivateKeyFile: "private.pem", PrivateKeyForDPoP: true}
+	config.AuthMethod = config.selectAuthMethod([]string{"private_key_jwt", "client_secret_basic"})
+	flow := &AuthorizationCodeFlow{Config: config, FlowConfig: &AuthorizationCodeFlowConfig{PKCE: true, DPoP: true}}
+
+	if _, err := flow.setupPKCE(); err != nil {
+		t.Fatalf("setupPKCE() error = %v", err)
+	}
+	if config.AuthMethod != httpclient.AuthMethodNone {
+		t.Errorf("AuthMethod = %q, want %q", config.AuthMethod, httpclient.AuthMethodNone)
+	}
+
+	// With auth-method private_key_jwt the key authenticates the client as well
+	config.AuthMethod = httpclient.AuthMethodPrivateKeyJWT
+	if _, err := flow.setupPKCE(); err != nil {
+		t.Fatalf("setupPKCE() error = %v", err)
+	}
+	if config.AuthMethod != httpclient.AuthMethodPrivateKeyJWT {
+		t.Errorf("AuthMethod = %q, want %q", config.AuthMethod, httpclient.AuthMethodPrivateKeyJWT)
+	}
+}
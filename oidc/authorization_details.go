@@ -0,0 +1,53 @@
+package oidc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jentz/oidc-cli/log"
+)
+
+// ParseAuthorizationDetails checks that the value is a JSON array of authorization details objects,
+// each with a type (RFC 9396 section 2), and returns it in compact form for sending.
+func ParseAuthorizationDetails(value string) (string, error) {
+	var details []map[string]any
+	if err := json.Unmarshal([]byte(value), &details); err != nil {
+		return "", fmt.Errorf("authorization-details must be a JSON array of objects: %w", err)
+	}
+	if len(details) == 0 {
+		return "", errors.New("authorization-details must not be empty")
+	}
+	for i, detail := range details {
+		if typ, _ := detail["type"].(string); typ == "" {
+			return "", fmt.Errorf("authorization-details entry %d is missing the type", i)
+		}
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, []byte(value)); err != nil {
+		return "", err
+	}
+	return compact.String(), nil
+}
+
+// reportAuthorizationDetails prints the requested authorization details next to the ones
+// granted in the token response (RFC 9396 section 7), which may differ from the request
+func reportAuthorizationDetails(requested string, tokenData map[string]interface{}) {
+	if requested == "" {
+		return
+	}
+	var details any
+	_ = json.Unmarshal([]byte(requested), &details)
+	prettyJSON, _ := json.MarshalIndent(details, "", "  ")
+	log.Errorf("requested authorization_details:\n%s\n", string(prettyJSON))
+
+	granted, ok := tokenData["authorization_details"]
+	if !ok {
+		log.Errorf("token response has no authorization_details\n")
+		return
+	}
+	prettyJSON, _ = json.MarshalIndent(granted, "", "  ")
+	log.Errorf("granted authorization_details:\n%s\n", string(prettyJSON))
+}
@@ -0,0 +1,37 @@
+package oidc
+
+import "testing"
+
+func TestParseAuthorizationDetails(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{
+			"compacted",
+			`[ {"type": "payment_initiation", "instructedAmount": {"currency": "EUR", "amount": "123.50"}} ]`,
+			`[{"type":"payment_initiation","instructedAmount":{"currency":"EUR","amount":"123.50"}}]`,
+			false,
+		},
+		{"several types", `[{"type":"account_information"},{"type":"payment_initiation"}]`, `[{"type":"account_information"},{"type":"payment_initiation"}]`, false},
+		{"not json", `type=payment_initiation`, "", true},
+		{"object instead of array", `{"type":"payment_initiation"}`, "", true},
+		{"empty array", `[]`, "", true},
+		{"missing type", `[{"actions":["read"]}]`, "", true},
+		{"type is not a string", `[{"type":1}]`, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAuthorizationDetails(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAuthorizationDetails() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseAuthorizationDetails() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jentz/oidc-cli/crypto"
+	"github.com/jentz/oidc-cli/httpclient"
+	"github.com/jentz/oidc-cli/log"
+	"github.com/jentz/oidc-cli/webflow"
+)
+
+// CIBA token delivery modes (OpenID Connect CIBA Core section 5). Push mode is not supported,
+// as it delivers the tokens to the notification endpoint without a token request.
+const (
+	CIBAModePoll = "poll"
+	CIBAModePing = "ping"
+)
+
+// cibaDefaultInterval is the polling interval used when the server does not return one
+// (OpenID Connect CIBA Core section 7.3). It can be replaced in tests.
+var cibaDefaultInterval = 5 * time.Second
+
+type CIBAFlow struct {
+	Config     *Config
+	FlowConfig *CIBAFlowConfig
+}
+
+type CIBAFlowConfig struct {
+	Scopes               string
+	Resources            []string
+	LoginHint            string
+	IDTokenHint          string
+	LoginHintToken       string
+	BindingMessage       string
+	RequestedExpiry      int
+	Mode                 string
+	NotificationEndpoint string // client notification endpoint registered for ping mode
+	NotificationListen   string // local address to listen on, defaults to the host of the endpoint
+	NotificationToken    string // bearer token the server must present, generated if empty
+	CustomArgs           *httpclient.CustomArgs
+}
+
+func (c *CIBAFlow) Run(ctx context.Context) error {
+	if c.Config.BackchannelAuthenticationEndpoint == "" {
+		return errors.New("backchannel authentication endpoint is not available, use --backchannel-authentication-url to set it")
+	}
+
+	// Start listening before the request is sent, so the ping cannot be missed
+	var notifications *webflow.NotificationServer
+	if c.FlowConfig.Mode == CIBAModePing {
+		var err error
+		notifications, err = c.startNotificationServer(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	cibaResp, err := c.authenticate(ctx)
+	if err != nil {
+		return err
+	}
+
+	log.Errorf("Waiting for the user to approve the authentication request on their device\n")
+	if c.FlowConfig.BindingMessage != "" {
+		log.Errorf("The device should show the binding message: %s\n", c.FlowConfig.BindingMessage)
+	}
+	log.Printf("auth_req_id %s expires in %ds\n", cibaResp.AuthReqID, cibaResp.ExpiresIn)
+
+	var tokenData map[string]interface{}
+	if notifications != nil {
+		tokenData, err = c.waitForPing(ctx, notifications, cibaResp)
+	} else {
+		tokenData, err = c.pollTokenEndpoint(ctx, cibaResp)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Print available response data
+	prettyJSON, err := json.MarshalIndent(tokenData, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to format token response: %w", err)
+	}
+	log.Outputf("%s\n", string(prettyJSON))
+	warnAudienceMismatch(c.FlowConfig.Resources, tokenData)
+	return c.Config.checkCertificateBinding(tokenData)
+}
+
+func (c *CIBAFlow) authenticate(ctx context.Context) (*httpclient.BackchannelAuthenticationResponse, error) {
+	req := &httpclient.BackchannelAuthenticationRequest{
+		ClientID:        c.Config.ClientID,
+		ClientSecret:    c.Config.ClientSecret,
+		AuthMethod:      c.Config.AuthMethod,
+		ClientAssertion: c.Config.clientAssertion(),
+		Scope:           c.FlowConfig.Scopes,
+		LoginHint:       c.FlowConfig.LoginHint,
+		IDTokenHint:     c.FlowConfig.IDTokenHint,
+		LoginHintToken:  c.FlowConfig.LoginHintToken,
+		BindingMessage:  c.FlowConfig.BindingMessage,
+		RequestedExpiry: c.FlowConfig.RequestedExpiry,
+		CustomArgs:      c.FlowConfig.CustomArgs,
+	}
+	if c.FlowConfig.Mode == CIBAModePing {
+		req.ClientNotificationToken = c.FlowConfig.NotificationToken
+	}
+	resp, err := c.Config.Client.ExecuteBackchannelAuthenticationRequest(ctx, c.Config.BackchannelAuthenticationEndpoint, req)
+	if err != nil {
+		return nil, fmt.Errorf("backchannel authentication request failed: %w", err)
+	}
+	cibaResp, err := httpclient.ParseBackchannelAuthenticationResponse(resp)
+	if err != nil {
+		return nil, httpclient.WrapError(err, "backchannel authentication")
+	}
+	return cibaResp, nil
+}
+
+// startNotificationServer starts the local client notification endpoint for ping mode.
+// The server is stopped when the context is done.
+func (c *CIBAFlow) startNotificationServer(ctx context.Context) (*webflow.NotificationServer, error) {
+	if c.FlowConfig.NotificationToken == "" {
+		token, err := crypto.GenerateRandomValue()
+		if err != nil {
+			return nil, err
+		}
+		c.FlowConfig.NotificationToken = token
+	}
+
+	server, err := webflow.NewNotificationServer(c.FlowConfig.NotificationEndpoint, c.FlowConfig.NotificationListen, c.FlowConfig.NotificationToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create notification server: %w", err)
+	}
+
+	serverErrChan := make(chan error, 1)
+	go func() {
+		if err := server.Start(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErrChan <- err
+		}
+	}()
+
+	// Give the server a moment to start or fail
+	select {
+	case err := <-serverErrChan:
+		return nil, fmt.Errorf("notification server failed to start: %w", err)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(100 * time.Millisecond):
+		// Server started successfully
+	}
+	log.Printf("listening for ping callbacks on %s\n", c.FlowConfig.NotificationEndpoint)
+	return server, nil
+}
+
+// waitForPing waits for the ping callback for the authentication request and then fetches the tokens
+func (c *CIBAFlow) waitForPing(ctx context.Context, server *webflow.NotificationServer, cibaResp *httpclient.BackchannelAuthenticationResponse) (map[string]interface{}, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(cibaResp.ExpiresIn)*time.Second)
+	defer cancel()
+
+	for {
+		notification, err := server.WaitForNotification(ctx)
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, errors.New("authentication request expired before a ping callback was received")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if notification.AuthReqID == cibaResp.AuthReqID {
+			break
+		}
+		log.Errorf("ignoring ping callback for unknown auth_req_id %s\n", notification.AuthReqID)
+	}
+	log.Printf("received ping callback, fetching tokens\n")
+
+	tokenData, err := c.requestToken(ctx, cibaResp.AuthReqID)
+	if err != nil {
+		return nil, cibaTokenError(tokenData, err)
+	}
+	return tokenData, nil
+}
+
+// pollTokenEndpoint polls the token endpoint until the user has approved the request,
+// the request has expired or the user has denied it.
+func (c *CIBAFlow) pollTokenEndpoint(ctx context.Context, cibaResp *httpclient.BackchannelAuthenticationResponse) (map[string]interface{}, error) {
+	interval := time.Duration(cibaResp.Interval) * time.Second
+	if interval <= 0 {
+		interval = cibaDefaultInterval
+	}
+	expiry := time.Now().Add(time.Duration(cibaResp.ExpiresIn) * time.Second)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		if time.Now().After(expiry) {
+			return nil, errors.New("authentication request expired before it was approved")
+		}
+
+		tokenData, err := c.requestToken(ctx, cibaResp.AuthReqID)
+		if err == nil {
+			return tokenData, nil
+		}
+		if !errors.Is(err, httpclient.ErrOAuthError) {
+			return nil, cibaTokenError(tokenData, err)
+		}
+
+		// CIBA uses the polling errors of the device authorization grant (OpenID Connect CIBA Core section 11)
+		switch tokenData["error"] {
+		case httpclient.DeviceErrorAuthorizationPending:
+			log.Printf("authorization pending, polling again in %s\n", interval)
+		case httpclient.DeviceErrorSlowDown:
+			interval += deviceSlowDownIncrement
+			log.Printf("server requested slow down, polling again in %s\n", interval)
+		default:
+			return nil, cibaTokenError(tokenData, err)
+		}
+	}
+}
+
+func (c *CIBAFlow) requestToken(ctx context.Context, authReqID string) (map[string]interface{}, error) {
+	req := httpclient.CreateCIBATokenRequest(
+		c.Config.ClientID,
+		c.Config.ClientSecret,
+		c.Config.AuthMethod,
+		authReqID,
+	)
+	req.ClientAssertion = c.Config.clientAssertion()
+	req.Resources = c.FlowConfig.Resources
+	resp, err := c.Config.Client.ExecuteTokenRequest(ctx, c.Config.TokenEndpoint, req, nil /* no custom headers */)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	return httpclient.ParseTokenResponse(resp)
+}
+
+// cibaTokenError describes the terminal errors of a CIBA token request
+func cibaTokenError(tokenData map[string]interface{}, err error) error {
+	switch tokenData["error"] {
+	case httpclient.DeviceErrorExpiredToken:
+		return errors.New("authentication request expired before it was approved")
+	case httpclient.DeviceErrorAccessDenied:
+		return errors.New("authentication request was denied by the user")
+	}
+	if errors.Is(err, httpclient.ErrOAuthError) || errors.Is(err, httpclient.ErrHTTPFailure) || errors.Is(err, httpclient.ErrParsingJSON) {
+		return httpclient.WrapError(err, "token")
+	}
+	return err
+}
@@ -0,0 +1,166 @@
+package oidc
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jentz/oidc-cli/httpclient"
+)
+
+// cibaTestServer serves the backchannel authentication and token endpoints. The token endpoint
+// answers with the queued token responses in order.
+func cibaTestServer(t *testing.T, onAuthenticate func(r *http.Request), tokenResponses ...string) *httptest.Server {
+	var mu sync.Mutex
+	mux := http.NewServeMux()
+	mux.HandleFunc("/bc-authorize", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if onAuthenticate != nil {
+			onAuthenticate(r)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"auth_req_id":"req-1","expires_in":60}`))
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if got := r.FormValue("grant_type"); got != httpclient.GrantTypeCIBA {
+			t.Errorf("grant_type = %q, want %q", got, httpclient.GrantTypeCIBA)
+		}
+		if got := r.FormValue("auth_req_id"); got != "req-1" {
+			t.Errorf("auth_req_id = %q, want req-1", got)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if len(tokenResponses) == 0 {
+			t.Fatal("unexpected token request")
+		}
+		body := tokenResponses[0]
+		tokenResponses = tokenResponses[1:]
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(body, `"error"`) {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		_, _ = w.Write([]byte(body))
+	})
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func newCIBATestFlow(ts *httptest.Server, flowConf *CIBAFlowConfig) *CIBAFlow {
+	return &CIBAFlow{
+		Config: &Config{
+			ClientID:                          "client-id",
+			ClientSecret:                      "client-secret",
+			AuthMethod:                        httpclient.AuthMethodBasic,
+			BackchannelAuthenticationEndpoint: ts.URL + "/bc-authorize",
+			TokenEndpoint:                     ts.URL + "/token",
+			Client:                            httpclient.NewClient(nil),
+		},
+		FlowConfig: flowConf,
+	}
+}
+
+func TestCIBAFlowPoll(t *testing.T) {
+	cibaDefaultInterval = 10 * time.Millisecond
+	defer func() { cibaDefaultInterval = 5 * time.Second }()
+
+	tests := []struct {
+		name           string
+		tokenResponses []string
+		wantErr        string
+	}{
+		{
+			"approved after pending",
+			[]string{`{"error":"authorization_pending"}`, `{"access_token":"at","token_type":"Bearer"}`},
+			"",
+		},
+		{
+			"denied",
+			[]string{`{"error":"authorization_pending"}`, `{"error":"access_denied"}`},
+			"denied by the user",
+		},
+		{
+			"expired",
+			[]string{`{"error":"expired_token"}`},
+			"expired",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := cibaTestServer(t, func(r *http.Request) {
+				if got := r.FormValue("login_hint"); got != "alice" {
+					t.Errorf("login_hint = %q, want alice", got)
+				}
+				if r.Form.Has("client_notification_token") {
+					t.Error("client_notification_token sent in poll mode")
+				}
+			}, tt.tokenResponses...)
+			flow := newCIBATestFlow(ts, &CIBAFlowConfig{Scopes: "openid", LoginHint: "alice", Mode: CIBAModePoll})
+
+			err := flow.Run(context.Background())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Run() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Run() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCIBAFlowPing(t *testing.T) {
+	// Reserve a free port for the notification endpoint
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to reserve port: %v", err)
+	}
+	notificationEndpoint := "http://" + listener.Addr().String() + "/ciba"
+	_ = listener.Close()
+
+	ts := cibaTestServer(t, func(r *http.Request) {
+		token := r.FormValue("client_notification_token")
+		if token == "" {
+			t.Error("client_notification_token missing in ping mode")
+		}
+		// Ping the client once the response has been sent
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			for _, id := range []string{"other-req", "req-1"} {
+				req, _ := http.NewRequest(http.MethodPost, notificationEndpoint, strings.NewReader(`{"auth_req_id":"`+id+`"}`))
+				req.Header.Set("Authorization", "Bearer "+token)
+				req.Header.Set("Content-Type", "application/json")
+				resp, err := http.DefaultClient.Do(req)
+				if err != nil {
+					t.Errorf("ping failed: %v", err)
+					return
+				}
+				_ = resp.Body.Close()
+				if resp.StatusCode != http.StatusNoContent {
+					t.Errorf("ping status = %d, want %d", resp.StatusCode, http.StatusNoContent)
+				}
+			}
+		}()
+	}, `{"access_token":"at","token_type":"Bearer"}`)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	flow := newCIBATestFlow(ts, &CIBAFlowConfig{
+		Scopes:               "openid",
+		IDTokenHint:          "id-token",
+		Mode:                 CIBAModePing,
+		NotificationEndpoint: notificationEndpoint,
+	})
+	if err := flow.Run(ctx); err != nil {
+		t.Errorf("Run() error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,23 @@
+package oidc
+
+// audienceContains reports whether the aud claim, which may be a single
+// string or an array of strings, contains the given audience.
+func audienceContains(aud any, audience string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == audience
+	case []interface{}:
+		for _, a := range v {
+			if s, ok := a.(string); ok && s == audience {
+				return true
+			}
+		}
+	case []string:
+		for _, s := range v {
+			if s == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jentz/oidc-cli/crypto"
+	"github.com/jentz/oidc-cli/log"
+)
+
+// Targets of individual claim requests (OpenID Connect Core section 5.5)
+const (
+	ClaimTargetIDToken  = "id_token"
+	ClaimTargetUserinfo = "userinfo"
+)
+
+// ClaimsRequest is the claims request parameter (OpenID Connect Core section 5.5). It maps each
+// target to the requested claims, where a nil request asks for the claim in the default manner.
+type ClaimsRequest map[string]map[string]*ClaimRequest
+
+// ClaimRequest describes how a single claim is requested (OpenID Connect Core section 5.5.1)
+type ClaimRequest struct {
+	Essential bool  `json:"essential,omitempty"`
+	Value     any   `json:"value,omitempty"`
+	Values    []any `json:"values,omitempty"`
+}
+
+// Add adds a claim request in the form target:name[:essential][=value]. The target is id_token
+// or userinfo. A value that is a JSON array requests one of its values, any other value is used
+// like a claim argument, as JSON if it is valid JSON and as a string otherwise.
+func (r ClaimsRequest) Add(arg string) error {
+	spec, value, hasValue := strings.Cut(arg, "=")
+	target, name, _ := strings.Cut(spec, ":")
+	if target != ClaimTargetIDToken && target != ClaimTargetUserinfo {
+		return fmt.Errorf("invalid claim %q, must start with id_token: or userinfo:", arg)
+	}
+
+	req := &ClaimRequest{}
+	if n, ok := strings.CutSuffix(name, ":essential"); ok {
+		name = n
+		req.Essential = true
+	}
+	if name == "" {
+		return fmt.Errorf("invalid claim %q, the claim name is missing", arg)
+	}
+	if hasValue {
+		var parsed any
+		if err := json.Unmarshal([]byte(value), &parsed); err != nil {
+			parsed = value
+		}
+		if values, ok := parsed.([]any); ok {
+			if len(values) == 0 {
+				return fmt.Errorf("invalid claim %q, the list of values is empty", arg)
+			}
+			req.Values = values
+		} else {
+			req.Value = parsed
+		}
+	}
+	if !req.Essential && !hasValue {
+		req = nil
+	}
+
+	if _, ok := r[target][name]; ok {
+		return fmt.Errorf("claim %s is requested more than once for %s", name, target)
+	}
+	if r[target] == nil {
+		r[target] = make(map[string]*ClaimRequest)
+	}
+	r[target][name] = req
+	return nil
+}
+
+// String returns the claims request parameter as JSON, or an empty string if no claims are requested
+func (r ClaimsRequest) String() string {
+	if len(r) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(r)
+	return string(data)
+}
+
+// reportRequestedClaims prints for each requested claim whether it was returned in the ID token or
+// by the UserInfo endpoint. An OP may omit even essential claims, so nothing is treated as a failure.
+func (c *AuthorizationCodeFlow) reportRequestedClaims(ctx context.Context, idToken, accessToken string) {
+	claims := c.FlowConfig.Claims
+	if len(claims) == 0 {
+		return
+	}
+	log.Errorf("Requested claims:\n")
+
+	if requested := claims[ClaimTargetIDToken]; len(requested) > 0 {
+		returned, err := c.Config.idTokenClaims(idToken)
+		if err != nil {
+			log.Errorf("  id_token: not checked, %v\n", err)
+		} else {
+			printClaimReport(ClaimTargetIDToken, requested, returned)
+		}
+	}
+
+	if requested := claims[ClaimTargetUserinfo]; len(requested) > 0 {
+		var returned map[string]interface{}
+		err := errors.New("no access token was issued")
+		if accessToken != "" {
+			returned, err = c.Config.fetchUserinfo(ctx, accessToken, c.FlowConfig.DPoP)
+		}
+		if err != nil {
+			log.Errorf("  userinfo: not checked, %v\n", err)
+		} else {
+			printClaimReport(ClaimTargetUserinfo, requested, returned)
+		}
+	}
+}
+
+// printClaimReport prints one line per requested claim, in the order of the claim names
+func printClaimReport(target string, requested map[string]*ClaimRequest, returned map[string]interface{}) {
+	names := make([]string, 0, len(requested))
+	for name := range requested {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		req := requested[name]
+		kind := "voluntary"
+		if req != nil && req.Essential {
+			kind = "essential"
+		}
+		value, ok := returned[name]
+		if !ok {
+			log.Errorf("  [MISSING] %s %s (%s)\n", target, name, kind)
+			continue
+		}
+		formatted, _ := json.Marshal(value)
+		if req != nil && !req.matches(value) {
+			log.Errorf("  [RETURNED] %s %s (%s): %s, not the requested value\n", target, name, kind, formatted)
+		} else {
+			log.Errorf("  [RETURNED] %s %s (%s): %s\n", target, name, kind, formatted)
+		}
+	}
+}
+
+// matches reports whether a returned claim value is the requested value, or one of the requested values
+func (r *ClaimRequest) matches(value any) bool {
+	switch {
+	case r.Value != nil:
+		return reflect.DeepEqual(r.Value, value)
+	case r.Values != nil:
+		return slices.ContainsFunc(r.Values, func(v any) bool { return reflect.DeepEqual(v, value) })
+	}
+	return true
+}
+
+// idTokenClaims returns the claims of an ID token without verifying it, decrypting it if needed
+func (c *Config) idTokenClaims(token string) (jwt.MapClaims, error) {
+	if token == "" {
+		return nil, errors.New("no ID token was issued")
+	}
+	if crypto.IsJWE(token) {
+		if c.PrivateKey == nil {
+			return nil, errors.New("ID token is encrypted but no private key is configured")
+		}
+		plaintext, _, err := crypto.DecryptJWE(token, c.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		token = strings.TrimSpace(string(plaintext))
+	}
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		return nil, err
+	}
+	return parsed.Claims.(jwt.MapClaims), nil
+}
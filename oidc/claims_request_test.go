@@ -0,0 +1,90 @@
+package oidc
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jentz/oidc-cli/log"
+)
+
+func TestClaimsRequestAdd(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{"voluntary", []string{"userinfo:given_name"}, `{"userinfo":{"given_name":null}}`, false},
+		{"essential", []string{"id_token:email:essential"}, `{"id_token":{"email":{"essential":true}}}`, false},
+		{
+			"value containing colons",
+			[]string{"id_token:acr:essential=urn:mace:incommon:iap:silver"},
+			`{"id_token":{"acr":{"essential":true,"value":"urn:mace:incommon:iap:silver"}}}`,
+			false,
+		},
+		{"json value", []string{"userinfo:email_verified=true"}, `{"userinfo":{"email_verified":{"value":true}}}`, false},
+		{"values", []string{`id_token:acr=["loa2","loa3"]`}, `{"id_token":{"acr":{"values":["loa2","loa3"]}}}`, false},
+		{"claim name with colons", []string{"userinfo:https://example.com/groups"}, `{"userinfo":{"https://example.com/groups":null}}`, false},
+		{
+			"both targets",
+			[]string{"id_token:email", "userinfo:email"},
+			`{"id_token":{"email":null},"userinfo":{"email":null}}`,
+			false,
+		},
+		{"unknown target", []string{"access_token:email"}, "", true},
+		{"missing target", []string{"email"}, "", true},
+		{"missing name", []string{"id_token:"}, "", true},
+		{"missing name before essential", []string{"id_token::essential"}, "", true},
+		{"empty values", []string{"id_token:acr=[]"}, "", true},
+		{"duplicate", []string{"id_token:email", "id_token:email:essential"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := ClaimsRequest{}
+			var err error
+			for _, arg := range tt.args {
+				if err = claims.Add(arg); err != nil {
+					break
+				}
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Add() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && claims.String() != tt.want {
+				t.Errorf("String() = %s, want %s", claims.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintClaimReport(t *testing.T) {
+	var stderr bytes.Buffer
+	log.SetDefaultLogger(log.WithStderr(&stderr), log.WithStdout(io.Discard))
+	defer log.SetDefaultLogger(log.WithVerbose(true), log.WithStderr(os.Stderr), log.WithStdout(os.Stdout))
+
+	claims := ClaimsRequest{}
+	for _, arg := range []string{"id_token:email:essential", "id_token:acr=loa3", "id_token:amr", "id_token:nickname"} {
+		if err := claims.Add(arg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	printClaimReport(ClaimTargetIDToken, claims[ClaimTargetIDToken], map[string]interface{}{
+		"email": "alice@example.com",
+		"acr":   "loa2",
+		"amr":   []interface{}{"pwd"},
+	})
+
+	want := []string{
+		"  [RETURNED] id_token acr (voluntary): \"loa2\", not the requested value\n",
+		"  [RETURNED] id_token amr (voluntary): [\"pwd\"]\n",
+		"  [RETURNED] id_token email (essential): \"alice@example.com\"\n",
+		"  [MISSING] id_token nickname (voluntary)\n",
+	}
+	if got := stderr.String(); got != strings.Join(want, "") {
+		t.Errorf("report = %q, want %q", got, strings.Join(want, ""))
+	}
+}
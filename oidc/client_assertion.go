@@ -0,0 +1,57 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// readClientAssertion reads an externally issued client assertion from the configured file or
+// command. It is read again for every request, so that rotated tokens are picked up.
+func (c *Config) readClientAssertion(ctx context.Context) (string, error) {
+	var assertion string
+	switch {
+	case c.ClientAssertionFile != "":
+		data, err := os.ReadFile(c.ClientAssertionFile)
+		if err != nil {
+			return "", fmt.Errorf("could not read client assertion file: %w", err)
+		}
+		assertion = string(data)
+	case c.ClientAssertionCmd != "":
+		cmd := shellCommand(ctx, c.ClientAssertionCmd)
+		cmd.Stderr = os.Stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("client assertion command failed: %w", err)
+		}
+		assertion = string(out)
+	}
+	assertion = strings.TrimSpace(assertion)
+	if assertion == "" {
+		return "", errors.New("client assertion is empty")
+	}
+	return assertion, nil
+}
+
+// hasClientAssertionSource reports whether client assertions are read from a file or command
+func (c *Config) hasClientAssertionSource() bool {
+	return c.ClientAssertionFile != "" || c.ClientAssertionCmd != ""
+}
+
+// shellCommand runs a command line through the shell of the platform. When the context is done,
+// the shell is killed and its output is no longer waited for, even if a child process still holds it.
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.WaitDelay = time.Second
+	return cmd
+}
@@ -0,0 +1,77 @@
+package oidc
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestReadClientAssertionFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "token")
+	cfg := &Config{ClientAssertionFile: file}
+
+	if _, err := cfg.readClientAssertion(context.Background()); err == nil {
+		t.Error("readClientAssertion() for missing file error = nil, want error")
+	}
+
+	// The file is read for every request, so that rotated tokens are picked up
+	for _, token := range []string{"first-token", "rotated-token"} {
+		if err := os.WriteFile(file, []byte(token+"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		got, err := cfg.readClientAssertion(context.Background())
+		if err != nil {
+			t.Fatalf("readClientAssertion() error = %v", err)
+		}
+		if got != token {
+			t.Errorf("readClientAssertion() = %q, want %q", got, token)
+		}
+	}
+
+	if err := os.WriteFile(file, []byte("\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cfg.readClientAssertion(context.Background()); err == nil {
+		t.Error("readClientAssertion() for empty file error = nil, want error")
+	}
+}
+
+func TestReadClientAssertionCmd(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+
+	cfg := &Config{ClientAssertionCmd: "echo 'command-token'"}
+	got, err := cfg.readClientAssertion(context.Background())
+	if err != nil {
+		t.Fatalf("readClientAssertion() error = %v", err)
+	}
+	if got != "command-token" {
+		t.Errorf("readClientAssertion() = %q, want %q", got, "command-token")
+	}
+
+	cfg.ClientAssertionCmd = "exit 1"
+	if _, err := cfg.readClientAssertion(context.Background()); err == nil {
+		t.Error("readClientAssertion() for failing command error = nil, want error")
+	}
+}
+
+func TestReadClientAssertionCmdCanceled(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	cfg := &Config{ClientAssertionCmd: "exec sleep 10"}
+	start := time.Now()
+	if _, err := cfg.readClientAssertion(ctx); err == nil {
+		t.Error("readClientAssertion() for canceled command error = nil, want error")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("readClientAssertion() returned after %v, want the command to be killed", elapsed)
+	}
+}
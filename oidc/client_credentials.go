@@ -15,7 +15,9 @@ type ClientCredentialsFlow struct {
 }
 
 type ClientCredentialsFlowConfig struct {
-	Scopes string
+	Scopes               string
+	Resources            []string
+	AuthorizationDetails string // compact JSON, see ParseAuthorizationDetails
 }
 
 func (c *ClientCredentialsFlow) Run(ctx context.Context) error {
@@ -27,6 +29,11 @@ func (c *ClientCredentialsFlow) Run(ctx context.Context) error {
 		c.Config.AuthMethod,
 		c.FlowConfig.Scopes,
 	)
+	req.ClientAssertion = c.Config.clientAssertion()
+	req.Resources = c.FlowConfig.Resources
+	if c.FlowConfig.AuthorizationDetails != "" {
+		req.Params.Set("authorization_details", c.FlowConfig.AuthorizationDetails)
+	}
 
 	resp, err := client.ExecuteTokenRequest(ctx, c.Config.TokenEndpoint, req, nil /* no custom headers */)
 	if err != nil {
@@ -44,5 +51,7 @@ func (c *ClientCredentialsFlow) Run(ctx context.Context) error {
 		return fmt.Errorf("failed to format token response: %w", err)
 	}
 	log.Outputf("%s\n", string(prettyJSON))
-	return nil
+	reportAuthorizationDetails(c.FlowConfig.AuthorizationDetails, tokenData)
+	warnAudienceMismatch(c.FlowConfig.Resources, tokenData)
+	return c.Config.checkCertificateBinding(tokenData)
 }
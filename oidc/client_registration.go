@@ -0,0 +1,278 @@
+package oidc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+
+	"github.com/jentz/oidc-cli/httpclient"
+	"github.com/jentz/oidc-cli/log"
+)
+
+type RegisterFlow struct {
+	Config     *Config
+	FlowConfig *RegisterFlowConfig
+}
+
+type RegisterFlowConfig struct {
+	Metadata           map[string]interface{}
+	InitialAccessToken string
+	RegistrationDir    string // directory the registration is stored in for the management commands, empty to not store it
+}
+
+// ClientManagementFlowConfig configures the management of a registered client (RFC 7592). The client
+// configuration endpoint and registration access token are taken from the stored registration of the
+// client unless they are given.
+type ClientManagementFlowConfig struct {
+	RegistrationClientURI   string
+	RegistrationAccessToken string
+	RegistrationDir         string
+	Metadata                map[string]interface{} // metadata to change, for updates only
+}
+
+type ClientReadFlow struct {
+	Config     *Config
+	FlowConfig *ClientManagementFlowConfig
+}
+
+type ClientUpdateFlow struct {
+	Config     *Config
+	FlowConfig *ClientManagementFlowConfig
+}
+
+type ClientDeleteFlow struct {
+	Config     *Config
+	FlowConfig *ClientManagementFlowConfig
+}
+
+// readOnlyClientMetadata lists the client information that is issued by the server and must not
+// be sent in an update request (RFC 7592 section 2.2)
+var readOnlyClientMetadata = []string{"registration_access_token", "registration_client_uri", "client_secret_expires_at", "client_id_issued_at"}
+
+func (c *RegisterFlow) Run(ctx context.Context) error {
+	if c.Config.RegistrationEndpoint == "" {
+		return errors.New("registration endpoint is not available, use --registration-url to set it")
+	}
+
+	req := &httpclient.ClientRegistrationRequest{
+		Metadata:           c.FlowConfig.Metadata,
+		InitialAccessToken: c.FlowConfig.InitialAccessToken,
+	}
+	resp, err := c.Config.Client.ExecuteClientRegistrationRequest(ctx, c.Config.RegistrationEndpoint, req)
+	if err != nil {
+		return fmt.Errorf("client registration request failed: %w", err)
+	}
+	client, err := httpclient.ParseClientRegistrationResponse(resp)
+	if err != nil {
+		return httpclient.WrapError(err, "client registration")
+	}
+
+	if err := printClientInformation(client); err != nil {
+		return err
+	}
+	if _, ok := client["registration_client_uri"]; !ok {
+		log.Errorf("the server does not support managing the client, no registration_client_uri was returned\n")
+	}
+	storeRegistration(c.FlowConfig.RegistrationDir, c.Config.IssuerURL, client)
+	return nil
+}
+
+func (c *ClientReadFlow) Run(ctx context.Context) error {
+	uri, token, err := c.Config.clientConfiguration(c.FlowConfig)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Config.Client.ExecuteClientReadRequest(ctx, uri, token)
+	if err != nil {
+		return fmt.Errorf("client read request failed: %w", err)
+	}
+	client, err := httpclient.ParseClientRegistrationResponse(resp)
+	if err != nil {
+		return httpclient.WrapError(err, "client read")
+	}
+
+	if err := printClientInformation(client); err != nil {
+		return err
+	}
+	keepClientConfiguration(client, uri, token)
+	storeRegistration(c.FlowConfig.RegistrationDir, c.Config.IssuerURL, client)
+	return nil
+}
+
+// Run reads the current configuration of the client and sends it back with the given metadata
+// applied, as an update replaces all metadata of the client. A null value removes a field.
+func (c *ClientUpdateFlow) Run(ctx context.Context) error {
+	uri, token, err := c.Config.clientConfiguration(c.FlowConfig)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Config.Client.ExecuteClientReadRequest(ctx, uri, token)
+	if err != nil {
+		return fmt.Errorf("client read request failed: %w", err)
+	}
+	current, err := httpclient.ParseClientRegistrationResponse(resp)
+	if err != nil {
+		return httpclient.WrapError(err, "client read")
+	}
+
+	metadata := updatedClientMetadata(current, c.FlowConfig.Metadata)
+	resp, err = c.Config.Client.ExecuteClientUpdateRequest(ctx, uri, token, metadata)
+	if err != nil {
+		return fmt.Errorf("client update request failed: %w", err)
+	}
+	client, err := httpclient.ParseClientRegistrationResponse(resp)
+	if err != nil {
+		return httpclient.WrapError(err, "client update")
+	}
+
+	if err := printClientInformation(client); err != nil {
+		return err
+	}
+	keepClientConfiguration(client, uri, token)
+	storeRegistration(c.FlowConfig.RegistrationDir, c.Config.IssuerURL, client)
+	return nil
+}
+
+func (c *ClientDeleteFlow) Run(ctx context.Context) error {
+	uri, token, err := c.Config.clientConfiguration(c.FlowConfig)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Config.Client.ExecuteClientDeleteRequest(ctx, uri, token)
+	if err != nil {
+		return fmt.Errorf("client delete request failed: %w", err)
+	}
+	if err := httpclient.ParseClientDeleteResponse(resp); err != nil {
+		return httpclient.WrapError(err, "client delete")
+	}
+
+	log.Errorf("Client deleted\n")
+	if file := registrationFile(c.FlowConfig.RegistrationDir, c.Config.IssuerURL, c.Config.ClientID); file != "" {
+		if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Errorf("failed to remove the stored registration: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// clientConfiguration returns the client configuration endpoint and registration access token for
+// the client, from the flow configuration or else from the stored registration of the client
+func (c *Config) clientConfiguration(flow *ClientManagementFlowConfig) (uri, token string, err error) {
+	uri, token = flow.RegistrationClientURI, flow.RegistrationAccessToken
+	if uri != "" && token != "" {
+		return uri, token, nil
+	}
+
+	stored, err := readRegistration(flow.RegistrationDir, c.IssuerURL, c.ClientID)
+	if err != nil {
+		return "", "", err
+	}
+	if uri == "" {
+		uri, _ = stored["registration_client_uri"].(string)
+	}
+	if token == "" {
+		token, _ = stored["registration_access_token"].(string)
+	}
+	if uri == "" || token == "" {
+		return "", "", errors.New("the stored registration has no registration_client_uri and registration_access_token, use --registration-client-uri and --registration-access-token")
+	}
+	return uri, token, nil
+}
+
+// updatedClientMetadata applies the changes to the current client metadata and removes the
+// fields that are issued by the server
+func updatedClientMetadata(current, changes map[string]interface{}) map[string]interface{} {
+	metadata := maps.Clone(current)
+	for name, value := range changes {
+		if value == nil {
+			delete(metadata, name)
+		} else {
+			metadata[name] = value
+		}
+	}
+	for _, name := range readOnlyClientMetadata {
+		delete(metadata, name)
+	}
+	return metadata
+}
+
+// keepClientConfiguration adds the client configuration endpoint and registration access token
+// that were used to a response that does not repeat them, so the stored registration keeps working
+func keepClientConfiguration(client map[string]interface{}, uri, token string) {
+	if _, ok := client["registration_client_uri"]; !ok {
+		client["registration_client_uri"] = uri
+	}
+	if _, ok := client["registration_access_token"]; !ok {
+		client["registration_access_token"] = token
+	}
+}
+
+func printClientInformation(client map[string]interface{}) error {
+	prettyJSON, err := json.MarshalIndent(client, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to format client information: %w", err)
+	}
+	log.Outputf("%s\n", string(prettyJSON))
+	return nil
+}
+
+// registrationFile returns the file the registration of a client at an issuer is stored in,
+// or an empty string if registrations are not stored
+func registrationFile(dir, issuer, clientID string) string {
+	if dir == "" || clientID == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(issuer + " " + clientID))
+	return filepath.Join(dir, hex.EncodeToString(sum[:16])+".json")
+}
+
+func readRegistration(dir, issuer, clientID string) (map[string]interface{}, error) {
+	file := registrationFile(dir, issuer, clientID)
+	if file == "" {
+		return nil, errors.New("no stored registration to use, set --client-id and --registration-dir or use --registration-client-uri and --registration-access-token")
+	}
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("client %s has no stored registration for %s, use --registration-client-uri and --registration-access-token", clientID, issuer)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stored registration: %w", err)
+	}
+	var registration map[string]interface{}
+	if err := json.Unmarshal(data, &registration); err != nil {
+		return nil, fmt.Errorf("invalid stored registration %s: %w", file, err)
+	}
+	log.Printf("using registration stored in %s\n", file)
+	return registration, nil
+}
+
+// storeRegistration stores the client information for later management requests. It holds the
+// client credentials, so it is only readable by the user.
+func storeRegistration(dir, issuer string, client map[string]interface{}) {
+	clientID, _ := client["client_id"].(string)
+	file := registrationFile(dir, issuer, clientID)
+	if file == "" {
+		return
+	}
+	data, err := json.MarshalIndent(client, "", "  ")
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(file), 0o700)
+	}
+	if err == nil {
+		err = os.WriteFile(file, data, 0o600)
+	}
+	if err != nil {
+		log.Errorf("failed to store the registration: %v\n", err)
+		return
+	}
+	log.Printf("registration stored in %s\n", file)
+}
@@ -0,0 +1,104 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/jentz/oidc-cli/httpclient"
+	"github.com/jentz/oidc-cli/log"
+)
+
+func TestClientRegistrationFlows(t *testing.T) {
+	log.SetDefaultLogger(log.WithStderr(io.Discard), log.WithStdout(io.Discard))
+	defer log.SetDefaultLogger(log.WithVerbose(true), log.WithStderr(os.Stderr), log.WithStdout(os.Stdout))
+
+	var registered map[string]interface{}
+	var updates []map[string]interface{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/register", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&registered)
+		registered["client_id"] = "client-1"
+		registered["client_id_issued_at"] = 1700000000
+		registered["registration_access_token"] = "rat"
+		registered["registration_client_uri"] = "http://" + r.Host + "/register/client-1"
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(registered)
+	})
+	mux.HandleFunc("/register/client-1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer rat" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			var update map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&update)
+			updates = append(updates, update)
+			registered = update // the registration access token is not repeated in the response
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(registered)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	dir := t.TempDir()
+	config := &Config{
+		IssuerURL:            "https://example.com",
+		ClientID:             "client-1",
+		RegistrationEndpoint: ts.URL + "/register",
+		Client:               httpclient.NewClient(nil),
+	}
+	ctx := context.Background()
+
+	register := &RegisterFlow{Config: config, FlowConfig: &RegisterFlowConfig{
+		Metadata:        map[string]interface{}{"client_name": "test", "logo_uri": "https://example.com/logo.png"},
+		RegistrationDir: dir,
+	}}
+	if err := register.Run(ctx); err != nil {
+		t.Fatalf("register error = %v", err)
+	}
+	file := registrationFile(dir, config.IssuerURL, "client-1")
+	if info, err := os.Stat(file); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("registration not stored with mode 0600 in %s: %v", file, err)
+	}
+
+	update := &ClientUpdateFlow{Config: config, FlowConfig: &ClientManagementFlowConfig{
+		Metadata:        map[string]interface{}{"client_name": "renamed", "logo_uri": nil},
+		RegistrationDir: dir,
+	}}
+	if err := update.Run(ctx); err != nil {
+		t.Fatalf("update error = %v", err)
+	}
+	want := map[string]interface{}{"client_id": "client-1", "client_name": "renamed"}
+	if len(updates) != 1 || !reflect.DeepEqual(updates[0], want) {
+		t.Errorf("update request = %v, want %v", updates, want)
+	}
+
+	// The stored registration keeps the registration access token that was not returned by the update
+	read := &ClientReadFlow{Config: config, FlowConfig: &ClientManagementFlowConfig{RegistrationDir: dir}}
+	if err := read.Run(ctx); err != nil {
+		t.Fatalf("read error = %v", err)
+	}
+
+	remove := &ClientDeleteFlow{Config: config, FlowConfig: &ClientManagementFlowConfig{RegistrationDir: dir}}
+	if err := remove.Run(ctx); err != nil {
+		t.Fatalf("delete error = %v", err)
+	}
+	if _, err := os.Stat(file); !os.IsNotExist(err) {
+		t.Errorf("stored registration still exists after delete: %v", err)
+	}
+	if err := read.Run(ctx); err == nil {
+		t.Error("read after delete succeeded, want error for the missing registration")
+	}
+}
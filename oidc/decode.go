@@ -0,0 +1,252 @@
+package oidc
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jentz/oidc-cli/crypto"
+	"github.com/jentz/oidc-cli/log"
+)
+
+// maxNestingDepth limits how deep nested JWTs are decoded
+const maxNestingDepth = 5
+
+type DecodeFlow struct {
+	Config     *Config
+	FlowConfig *DecodeFlowConfig
+}
+
+type DecodeFlowConfig struct {
+	Input string // a JWT, or a JSON object such as a token response
+}
+
+// decodedJWT is the decoded form of a compact JWS or JWE. Nothing is verified.
+type decodedJWT struct {
+	Header       map[string]interface{} `json:"header"`
+	Payload      map[string]interface{} `json:"payload,omitempty"`
+	Raw          string                 `json:"raw_payload,omitempty"`
+	Times        map[string]string      `json:"times,omitempty"`
+	Confirmation string                 `json:"confirmation,omitempty"`
+	Nested       *decodedJWT            `json:"nested,omitempty"`
+	NestedClaims map[string]*decodedJWT `json:"nested_claims,omitempty"`
+	Note         string                 `json:"note,omitempty"`
+}
+
+func (c *DecodeFlow) Run(_ context.Context) error {
+	input := strings.TrimSpace(c.FlowConfig.Input)
+
+	var result any
+	if strings.HasPrefix(input, "{") {
+		decoded, err := c.Config.decodeJSONTokens(input)
+		if err != nil {
+			return err
+		}
+		result = decoded
+	} else {
+		decoded, err := c.Config.decodeJWT(input, 0)
+		if err != nil {
+			return err
+		}
+		result = decoded
+	}
+
+	prettyJSON, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to format decoded token: %w", err)
+	}
+	log.Outputf("%s\n", string(prettyJSON))
+	return nil
+}
+
+// decodeJSONTokens decodes every JWT in a JSON object, such as a token response
+func (c *Config) decodeJSONTokens(input string) (map[string]*decodedJWT, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(input), &fields); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON input: %w", err)
+	}
+
+	decoded := make(map[string]*decodedJWT)
+	for name, value := range fields {
+		s, ok := value.(string)
+		if !ok || !isCompactJWT(s) {
+			continue
+		}
+		token, err := c.decodeJWT(s, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", name, err)
+		}
+		decoded[name] = token
+	}
+	if len(decoded) == 0 {
+		return nil, errors.New("no JWTs found in JSON input")
+	}
+	return decoded, nil
+}
+
+// decodeJWT decodes a compact JWS or JWE without verifying it. Encrypted tokens are
+// decrypted if a private key is configured.
+func (c *Config) decodeJWT(token string, depth int) (*decodedJWT, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 && len(parts) != 5 {
+		return nil, fmt.Errorf("not a JWT: expected 3 or 5 parts, got %d", len(parts))
+	}
+
+	header, err := decodeJSONSegment(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid header: %w", err)
+	}
+	decoded := &decodedJWT{Header: header}
+
+	var payload []byte
+	if len(parts) == 5 {
+		if c.PrivateKey == nil {
+			decoded.Note = "encrypted, use --private-key to decrypt"
+			return decoded, nil
+		}
+		payload, _, err = crypto.DecryptJWE(token, c.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt: %w", err)
+		}
+	} else {
+		payload, err = base64.RawURLEncoding.DecodeString(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid payload: %w", err)
+		}
+	}
+
+	// The payload of a nested JWT is itself a JWT (RFC 7519 section 5.2)
+	if nested := strings.TrimSpace(string(payload)); isCompactJWT(nested) {
+		if depth >= maxNestingDepth {
+			return nil, errors.New("JWT is nested too deeply")
+		}
+		decoded.Nested, err = c.decodeJWT(nested, depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("invalid nested JWT: %w", err)
+		}
+		return decoded, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	if err := dec.Decode(&decoded.Payload); err != nil {
+		decoded.Raw = string(payload)
+		decoded.Note = "payload is not a JSON object"
+		return decoded, nil
+	}
+
+	decoded.Times = annotateTimes(decoded.Payload)
+	decoded.Confirmation = describeConfirmation(decoded.Payload)
+
+	// Claims may carry JWTs of their own, e.g. request objects or software statements
+	for name, value := range decoded.Payload {
+		s, ok := value.(string)
+		if !ok || !isCompactJWT(s) || depth >= maxNestingDepth {
+			continue
+		}
+		if nested, err := c.decodeJWT(s, depth+1); err == nil {
+			if decoded.NestedClaims == nil {
+				decoded.NestedClaims = make(map[string]*decodedJWT)
+			}
+			decoded.NestedClaims[name] = nested
+		}
+	}
+	return decoded, nil
+}
+
+// isCompactJWT reports whether s looks like a compact JWS or JWE, i.e. it has
+// 3 or 5 parts and the first part decodes to a JOSE header
+func isCompactJWT(s string) bool {
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 && len(parts) != 5 {
+		return false
+	}
+	header, err := decodeJSONSegment(parts[0])
+	if err != nil {
+		return false
+	}
+	_, ok := header["alg"]
+	return ok
+}
+
+func decodeJSONSegment(segment string) (map[string]interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// annotateTimes renders the time based claims as human readable times relative to now
+func annotateTimes(claims map[string]interface{}) map[string]string {
+	times := make(map[string]string)
+	now := timeNow()
+	for _, name := range []string{"exp", "iat", "nbf", "auth_time"} {
+		t, ok := numericClaim(claims, name)
+		if !ok {
+			continue
+		}
+		d := t.Sub(now).Round(time.Second)
+		var relative string
+		switch {
+		case name == "exp" && d > 0:
+			relative = "expires in " + d.String()
+		case name == "exp":
+			relative = "expired " + (-d).String() + " ago"
+		case d > 0:
+			relative = "in " + d.String()
+		default:
+			relative = (-d).String() + " ago"
+		}
+		times[name] = fmt.Sprintf("%s (%s)", formatTime(t), relative)
+	}
+	if len(times) == 0 {
+		return nil
+	}
+	return times
+}
+
+// describeConfirmation describes how a token is bound to a key through the cnf claim (RFC 7800)
+func describeConfirmation(claims map[string]interface{}) string {
+	cnf, ok := claims["cnf"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+
+	var descriptions []string
+	for method, value := range cnf {
+		switch method {
+		case "jkt":
+			descriptions = append(descriptions, fmt.Sprintf("DPoP-bound to key thumbprint %v", value))
+		case "x5t#S256":
+			descriptions = append(descriptions, fmt.Sprintf("certificate-bound to certificate thumbprint %v", value))
+		case "jwk":
+			description := "bound to an embedded key"
+			if data, err := json.Marshal(value); err == nil {
+				var jwk crypto.JWK
+				if json.Unmarshal(data, &jwk) == nil {
+					if thumbprint, err := jwk.Thumbprint(); err == nil {
+						description += " with thumbprint " + thumbprint
+					}
+				}
+			}
+			descriptions = append(descriptions, description)
+		default:
+			descriptions = append(descriptions, fmt.Sprintf("%s confirmation %v", method, value))
+		}
+	}
+	sort.Strings(descriptions)
+	return strings.Join(descriptions, "; ")
+}
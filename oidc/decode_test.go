@@ -0,0 +1,141 @@
+package oidc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jentz/oidc-cli/crypto"
+)
+
+func TestDecodeJWT(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	sign := func(claims jwt.MapClaims) string {
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodES256, claims).SignedString(key)
+		if err != nil {
+			t.Fatalf("failed to sign token: %v", err)
+		}
+		return signed
+	}
+
+	inner := sign(jwt.MapClaims{"sub": "alice"})
+	token := sign(jwt.MapClaims{
+		"sub":                "alice",
+		"exp":                now.Add(time.Hour).Unix(),
+		"iat":                now.Add(-time.Minute).Unix(),
+		"cnf":                map[string]any{"jkt": "0ZcOCORZNYy-DWpqq30jZyJGHTN0d2HglBV3uiguA4I"},
+		"software_statement": inner,
+	})
+
+	decoded, err := (&Config{}).decodeJWT(token, 0)
+	if err != nil {
+		t.Fatalf("decodeJWT() error = %v", err)
+	}
+	if decoded.Header["alg"] != "ES256" {
+		t.Errorf("header alg = %v, want ES256", decoded.Header["alg"])
+	}
+	if got := decoded.Times["exp"]; got != "2023-11-14T23:13:20Z (expires in 1h0m0s)" {
+		t.Errorf("exp = %q", got)
+	}
+	if got := decoded.Times["iat"]; got != "2023-11-14T22:12:20Z (1m0s ago)" {
+		t.Errorf("iat = %q", got)
+	}
+	if !strings.Contains(decoded.Confirmation, "DPoP-bound") {
+		t.Errorf("confirmation = %q, want DPoP binding", decoded.Confirmation)
+	}
+	if nested := decoded.NestedClaims["software_statement"]; nested == nil || nested.Payload["sub"] != "alice" {
+		t.Errorf("nested claim software_statement not decoded: %+v", decoded.NestedClaims)
+	}
+
+	if _, err := (&Config{}).decodeJWT("not-a-jwt", 0); err == nil {
+		t.Error("decodeJWT() error = nil, want error")
+	}
+}
+
+func TestDecodeJWTEncrypted(t *testing.T) {
+	signingKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	encryptionKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	signed, _ := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{"sub": "alice"}).SignedString(signingKey)
+	encrypted, err := crypto.EncryptJWE([]byte(signed), &encryptionKey.PublicKey, crypto.JWEAlgECDHES, crypto.JWEEncA128GCM, map[string]any{"cty": "JWT"})
+	if err != nil {
+		t.Fatalf("EncryptJWE() error = %v", err)
+	}
+
+	decoded, err := (&Config{}).decodeJWT(encrypted, 0)
+	if err != nil {
+		t.Fatalf("decodeJWT() without private key error = %v", err)
+	}
+	if decoded.Note == "" || decoded.Nested != nil {
+		t.Errorf("decodeJWT() without private key = %+v, want note only", decoded)
+	}
+
+	decoded, err = (&Config{PrivateKey: encryptionKey}).decodeJWT(encrypted, 0)
+	if err != nil {
+		t.Fatalf("decodeJWT() error = %v", err)
+	}
+	if decoded.Nested == nil || decoded.Nested.Payload["sub"] != "alice" {
+		t.Errorf("decodeJWT() nested = %+v, want decrypted inner JWT", decoded.Nested)
+	}
+}
+
+func TestDecodeJSONTokens(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	idToken, _ := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{"sub": "alice"}).SignedString(key)
+	response, _ := json.Marshal(map[string]any{
+		"access_token":  "opaque",
+		"refresh_token": "a.b.c",
+		"id_token":      idToken,
+		"expires_in":    3600,
+	})
+
+	decoded, err := (&Config{}).decodeJSONTokens(string(response))
+	if err != nil {
+		t.Fatalf("decodeJSONTokens() error = %v", err)
+	}
+	if len(decoded) != 1 || decoded["id_token"] == nil {
+		t.Errorf("decodeJSONTokens() = %v, want only id_token", decoded)
+	}
+
+	if _, err := (&Config{}).decodeJSONTokens(`{"access_token": "opaque"}`); err == nil {
+		t.Error("decodeJSONTokens() error = nil, want error")
+	}
+}
+
+func TestDescribeConfirmation(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims map[string]interface{}
+		want   string
+	}{
+		{"no cnf", map[string]interface{}{}, ""},
+		{"dpop", map[string]interface{}{"cnf": map[string]interface{}{"jkt": "abc"}}, "DPoP-bound to key thumbprint abc"},
+		{"mtls", map[string]interface{}{"cnf": map[string]interface{}{"x5t#S256": "def"}}, "certificate-bound to certificate thumbprint def"},
+		{
+			"jwk",
+			map[string]interface{}{"cnf": map[string]interface{}{"jwk": map[string]interface{}{
+				"kty": "RSA",
+				"n":   "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+				"e":   "AQAB",
+			}}},
+			"bound to an embedded key with thumbprint NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := describeConfirmation(tt.claims); got != tt.want {
+				t.Errorf("describeConfirmation() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
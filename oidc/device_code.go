@@ -0,0 +1,143 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jentz/oidc-cli/httpclient"
+	"github.com/jentz/oidc-cli/log"
+)
+
+const (
+	// deviceDefaultInterval is the polling interval used when the server does not return one (RFC 8628 section 3.2)
+	deviceDefaultInterval = 5 * time.Second
+	// deviceSlowDownIncrement is added to the polling interval on every slow_down error (RFC 8628 section 3.5)
+	deviceSlowDownIncrement = 5 * time.Second
+)
+
+type DeviceCodeFlow struct {
+	Config     *Config
+	FlowConfig *DeviceCodeFlowConfig
+}
+
+type DeviceCodeFlowConfig struct {
+	Scopes     string
+	Resources  []string
+	CustomArgs *httpclient.CustomArgs
+}
+
+func (c *DeviceCodeFlow) Run(ctx context.Context) error {
+	if c.Config.DeviceAuthorizationEndpoint == "" {
+		return errors.New("device authorization endpoint is not available, use --device-authorization-url to set it")
+	}
+	if !c.Config.hasClientCredentials() {
+		c.Config.AuthMethod = httpclient.AuthMethodNone
+	}
+
+	deviceResp, err := c.authorizeDevice(ctx)
+	if err != nil {
+		return err
+	}
+
+	log.Errorf("To sign in, visit %s and enter the code: %s\n", deviceResp.VerificationURI, deviceResp.UserCode)
+	if deviceResp.VerificationURIComplete != "" {
+		log.Errorf("Alternatively, visit %s\n", deviceResp.VerificationURIComplete)
+	}
+
+	tokenData, err := c.pollTokenEndpoint(ctx, deviceResp)
+	if err != nil {
+		return err
+	}
+
+	// Print available response data
+	prettyJSON, err := json.MarshalIndent(tokenData, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to format token response: %w", err)
+	}
+	log.Outputf("%s\n", string(prettyJSON))
+	warnAudienceMismatch(c.FlowConfig.Resources, tokenData)
+	return c.Config.checkCertificateBinding(tokenData)
+}
+
+func (c *DeviceCodeFlow) authorizeDevice(ctx context.Context) (*httpclient.DeviceAuthorizationResponse, error) {
+	req := &httpclient.DeviceAuthorizationRequest{
+		ClientID:        c.Config.ClientID,
+		ClientSecret:    c.Config.ClientSecret,
+		AuthMethod:      c.Config.AuthMethod,
+		ClientAssertion: c.Config.clientAssertion(),
+		Scope:           c.FlowConfig.Scopes,
+		CustomArgs:      c.FlowConfig.CustomArgs,
+	}
+	resp, err := c.Config.Client.ExecuteDeviceAuthorizationRequest(ctx, c.Config.DeviceAuthorizationEndpoint, req)
+	if err != nil {
+		return nil, fmt.Errorf("device authorization request failed: %w", err)
+	}
+	deviceResp, err := httpclient.ParseDeviceAuthorizationResponse(resp)
+	if err != nil {
+		return nil, httpclient.WrapError(err, "device authorization")
+	}
+	return deviceResp, nil
+}
+
+// pollTokenEndpoint polls the token endpoint until the user has completed the authorization,
+// the device code has expired or the authorization has been denied.
+func (c *DeviceCodeFlow) pollTokenEndpoint(ctx context.Context, deviceResp *httpclient.DeviceAuthorizationResponse) (map[string]interface{}, error) {
+	interval := time.Duration(deviceResp.Interval) * time.Second
+	if interval <= 0 {
+		interval = deviceDefaultInterval
+	}
+	var expiry time.Time
+	if deviceResp.ExpiresIn > 0 {
+		expiry = time.Now().Add(time.Duration(deviceResp.ExpiresIn) * time.Second)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		if !expiry.IsZero() && time.Now().After(expiry) {
+			return nil, errors.New("device code expired before authorization was completed")
+		}
+
+		req := httpclient.CreateDeviceCodeTokenRequest(
+			c.Config.ClientID,
+			c.Config.ClientSecret,
+			c.Config.AuthMethod,
+			deviceResp.DeviceCode,
+		)
+		req.ClientAssertion = c.Config.clientAssertion()
+		req.Resources = c.FlowConfig.Resources
+		resp, err := c.Config.Client.ExecuteTokenRequest(ctx, c.Config.TokenEndpoint, req, nil /* no custom headers */)
+		if err != nil {
+			return nil, fmt.Errorf("token request failed: %w", err)
+		}
+
+		tokenData, err := httpclient.ParseTokenResponse(resp)
+		if err == nil {
+			return tokenData, nil
+		}
+		if !errors.Is(err, httpclient.ErrOAuthError) {
+			return nil, httpclient.WrapError(err, "token")
+		}
+
+		switch tokenData["error"] {
+		case httpclient.DeviceErrorAuthorizationPending:
+			log.Printf("authorization pending, polling again in %s\n", interval)
+		case httpclient.DeviceErrorSlowDown:
+			interval += deviceSlowDownIncrement
+			log.Printf("server requested slow down, polling again in %s\n", interval)
+		case httpclient.DeviceErrorExpiredToken:
+			return nil, errors.New("device code expired before authorization was completed")
+		case httpclient.DeviceErrorAccessDenied:
+			return nil, errors.New("authorization request was denied by the user")
+		default:
+			return nil, httpclient.WrapError(err, "token")
+		}
+	}
+}
@@ -22,8 +22,24 @@ type DiscoveryConfiguration struct {
 	UserinfoEndpoint                   string   `json:"userinfo_endpoint,omitempty"`
 	RevocationEndpoint                 string   `json:"revocation_endpoint,omitempty"`
 	DeviceAuthorizationEndpoint        string   `json:"device_authorization_endpoint,omitempty"`
+	BackchannelAuthenticationEndpoint  string   `json:"backchannel_authentication_endpoint,omitempty"`
+	EndSessionEndpoint                 string   `json:"end_session_endpoint,omitempty"`
+	RegistrationEndpoint               string   `json:"registration_endpoint,omitempty"`
 	JwksURI                            string   `json:"jwks_uri,omitempty"`
 	TokenEndpointAuthMethods           []string `json:"token_endpoint_auth_methods_supported,omitempty"`
+	AuthorizationResponseIssSupported  bool     `json:"authorization_response_iss_parameter_supported,omitempty"`
+	TLSClientCertificateBoundTokens    bool     `json:"tls_client_certificate_bound_access_tokens,omitempty"`
+
+	// MTLSEndpointAliases are the endpoints to use with mutual TLS (RFC 8705 section 5)
+	MTLSEndpointAliases map[string]string `json:"mtls_endpoint_aliases,omitempty"`
+}
+
+// endpoint returns the mutual TLS alias of an endpoint if mtls is set and the server has one
+func (d *DiscoveryConfiguration) endpoint(name, value string, mtls bool) string {
+	if alias := d.MTLSEndpointAliases[name]; mtls && alias != "" {
+		return alias
+	}
+	return value
 }
 
 // Discover fetches OIDC configuration from the discovery endpoint
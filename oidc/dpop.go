@@ -0,0 +1,30 @@
+package oidc
+
+import (
+	"fmt"
+
+	"github.com/jentz/oidc-cli/crypto"
+	"github.com/jentz/oidc-cli/httpclient"
+)
+
+// dpopProof returns a function that creates a fresh DPoP proof for a request to the URL with the
+// client's key pair. The proof is bound to the access token, if one is given, for requests to
+// protected resources.
+func (c *Config) dpopProof(method, url, accessToken string) httpclient.DPoPProofFunc {
+	return func(nonce string) (string, error) {
+		builder := crypto.NewDPoPProofBuilder().
+			PublicKey(c.PublicKey).
+			PrivateKey(c.PrivateKey).
+			Method(method).
+			URL(url).
+			Nonce(nonce)
+		if accessToken != "" {
+			builder.AccessToken(accessToken)
+		}
+		proof, err := builder.Build()
+		if err != nil {
+			return "", fmt.Errorf("failed to create DPoP proof: %w", err)
+		}
+		return proof.String(), nil
+	}
+}
@@ -0,0 +1,70 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jentz/oidc-cli/crypto"
+	"github.com/jentz/oidc-cli/httpclient"
+	"github.com/jentz/oidc-cli/log"
+)
+
+type EndSessionFlow struct {
+	Config     *Config
+	FlowConfig *EndSessionFlowConfig
+}
+
+type EndSessionFlowConfig struct {
+	IDTokenHint           string
+	LogoutHint            string
+	CallbackURI           string // where the post-logout redirect is caught, empty to not request one
+	PostLogoutRedirectURI string // defaults to the callback URI
+	State                 string
+	UILocales             string
+	CustomArgs            *httpclient.CustomArgs
+}
+
+func (c *EndSessionFlow) Run(ctx context.Context) error {
+	if c.Config.EndSessionEndpoint == "" {
+		return errors.New("end session endpoint is not available, use --end-session-url to set it")
+	}
+
+	req := &httpclient.EndSessionRequest{
+		IDTokenHint:           c.FlowConfig.IDTokenHint,
+		LogoutHint:            c.FlowConfig.LogoutHint,
+		ClientID:              c.Config.ClientID,
+		PostLogoutRedirectURI: c.FlowConfig.PostLogoutRedirectURI,
+		State:                 c.FlowConfig.State,
+		UILocales:             c.FlowConfig.UILocales,
+		CustomArgs:            c.FlowConfig.CustomArgs,
+	}
+	// If the user has not explicitly set a post-logout redirect URI, use the callback URI
+	if req.PostLogoutRedirectURI == "" {
+		req.PostLogoutRedirectURI = c.FlowConfig.CallbackURI
+	}
+	// The state is only returned with the post-logout redirect, so generate one to verify it
+	if req.State == "" && req.PostLogoutRedirectURI != "" {
+		state, err := crypto.GenerateRandomValue()
+		if err != nil {
+			return fmt.Errorf("failed to generate state: %w", err)
+		}
+		req.State = state
+	}
+
+	resp, err := c.Config.Client.ExecuteEndSessionRequest(ctx, c.Config.EndSessionEndpoint, c.FlowConfig.CallbackURI, req)
+	if err != nil {
+		return fmt.Errorf("logout request failed: %w", err)
+	}
+	if resp == nil {
+		log.Errorf("Logout request opened, no post-logout redirect is awaited\n")
+		return nil
+	}
+
+	if resp.State != req.State {
+		return fmt.Errorf("state mismatch: sent %q but post-logout redirect returned %q", req.State, resp.State)
+	}
+	log.Printf("state verified\n")
+	log.Errorf("Logged out, the post-logout redirect was received\n")
+	return nil
+}
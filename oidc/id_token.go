@@ -0,0 +1,128 @@
+package oidc
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jentz/oidc-cli/crypto"
+)
+
+// idTokenExpectations holds the values from the authorization and token requests
+// that an ID token is validated against
+type idTokenExpectations struct {
+	Nonce        string        // nonce sent in the authorization request
+	AccessToken  string        // access token issued alongside the ID token, for at_hash
+	Code         string        // authorization code issued alongside the ID token, for c_hash
+	MaxAge       string        // max_age sent in the authorization request, for auth_time
+	ClockSkew    time.Duration // tolerance for exp, iat and auth_time
+	FrontChannel bool          // issued from the authorization endpoint, where at_hash and c_hash are required
+}
+
+// validateIDToken validates an ID token as described in OIDC Core section 3.1.3.7 and reports each check.
+// The claims are returned when the token could be parsed, even if some checks failed.
+func (c *Config) validateIDToken(ctx context.Context, idToken string, expect *idTokenExpectations) (*ValidationReport, jwt.MapClaims) {
+	report := &ValidationReport{Subject: "ID token"}
+	if expect.FrontChannel {
+		report.Subject = "front-channel ID token"
+	}
+
+	token, parsed := c.parseForValidation(report, idToken)
+	if parsed == nil {
+		return report, nil
+	}
+	claims := parsed.Claims.(jwt.MapClaims)
+	alg, _ := parsed.Header["alg"].(string)
+
+	c.checkSignature(ctx, report, token, alg)
+	checkIssuer(report, claims, c.IssuerURL)
+	checkAudience(report, claims, c.ClientID)
+	checkAuthorizedParty(report, claims, c.ClientID)
+	checkExpiry(report, claims, expect.ClockSkew, true)
+	checkIssuedAt(report, claims, expect.ClockSkew, true)
+	checkNonce(report, claims, expect.Nonce)
+	// OIDC Core sections 3.2.2.10 and 3.3.2.11 require the hashes of the tokens issued with a front-channel ID token
+	checkTokenHash(report, claims, "at_hash", expect.AccessToken, alg, expect.FrontChannel && expect.AccessToken != "")
+	checkTokenHash(report, claims, "c_hash", expect.Code, alg, expect.FrontChannel && expect.Code != "")
+	checkAuthTime(report, claims, expect.MaxAge, expect.ClockSkew)
+
+	return report, claims
+}
+
+func checkAuthorizedParty(report *ValidationReport, claims jwt.MapClaims, clientID string) {
+	azp, hasAzp := claims["azp"].(string)
+	aud, _ := claims["aud"].([]interface{})
+	switch {
+	case hasAzp && azp != clientID:
+		report.fail("azp", "%q does not match client id %q", azp, clientID)
+	case hasAzp:
+		report.pass("azp", "%q", azp)
+	case len(aud) > 1:
+		report.fail("azp", "missing although the token has multiple audiences")
+	default:
+		report.skip("azp", "not present, single audience")
+	}
+}
+
+func checkNonce(report *ValidationReport, claims jwt.MapClaims, nonce string) {
+	got, _ := claims["nonce"].(string)
+	switch {
+	case nonce == "" && got == "":
+		report.skip("nonce", "no nonce was sent")
+	case got != nonce:
+		report.fail("nonce", "%q does not match the nonce sent %q", got, nonce)
+	default:
+		report.pass("nonce", "matches the nonce sent")
+	}
+}
+
+// checkTokenHash validates a left-half hash claim (at_hash, c_hash) against the token it covers
+func checkTokenHash(report *ValidationReport, claims jwt.MapClaims, name, value, alg string, required bool) {
+	got, ok := claims[name].(string)
+	if !ok && required {
+		report.fail(name, "missing")
+		return
+	}
+	if !ok {
+		report.skip(name, "not present")
+		return
+	}
+	if value == "" {
+		report.skip(name, "present but there is no token to compare against")
+		return
+	}
+	want, err := crypto.TokenHash(value, alg)
+	if err != nil {
+		report.fail(name, "%v", err)
+		return
+	}
+	if got != want {
+		report.fail(name, "%q does not match the computed hash %q", got, want)
+		return
+	}
+	report.pass(name, "matches")
+}
+
+func checkAuthTime(report *ValidationReport, claims jwt.MapClaims, maxAge string, skew time.Duration) {
+	if maxAge == "" {
+		report.skip("auth_time", "max_age was not sent")
+		return
+	}
+	seconds, err := strconv.Atoi(maxAge)
+	if err != nil {
+		report.fail("auth_time", "invalid max_age %q", maxAge)
+		return
+	}
+	authTime, ok := numericClaim(claims, "auth_time")
+	if !ok {
+		report.fail("auth_time", "missing although max_age was sent")
+		return
+	}
+	deadline := authTime.Add(time.Duration(seconds)*time.Second + skew)
+	if timeNow().After(deadline) {
+		report.fail("auth_time", "authenticated at %s, more than max_age %ds ago", formatTime(authTime), seconds)
+		return
+	}
+	report.pass("auth_time", "authenticated at %s, within max_age %ds", formatTime(authTime), seconds)
+}
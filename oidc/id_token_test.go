@@ -0,0 +1,203 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jentz/oidc-cli/crypto"
+)
+
+func TestValidateIDToken(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	otherKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	jwk, _ := crypto.NewJWK(&key.PublicKey, "k1")
+	atHash, _ := crypto.TokenHash("access-token", "ES256")
+
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":       "https://example.com",
+			"sub":       "alice",
+			"aud":       "client-id",
+			"exp":       now.Add(time.Hour).Unix(),
+			"iat":       now.Unix(),
+			"nonce":     "nonce-123",
+			"at_hash":   atHash,
+			"auth_time": now.Add(-time.Minute).Unix(),
+		}
+	}
+	sign := func(claims jwt.MapClaims, signingKey *ecdsa.PrivateKey) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+		token.Header["kid"] = "k1"
+		signed, err := token.SignedString(signingKey)
+		if err != nil {
+			t.Fatalf("failed to sign token: %v", err)
+		}
+		return signed
+	}
+	with := func(name string, value any) jwt.MapClaims {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name       string
+		token      string
+		maxAge     string
+		wantFailed []string
+	}{
+		{"valid token", sign(validClaims(), key), "300", nil},
+		{"wrong signing key", sign(validClaims(), otherKey), "", []string{"signature"}},
+		{"wrong issuer", sign(with("iss", "https://evil.example.com"), key), "", []string{"iss"}},
+		{"wrong audience", sign(with("aud", "other"), key), "", []string{"aud"}},
+		{"multiple audiences without azp", sign(with("aud", []string{"client-id", "other"}), key), "", []string{"azp"}},
+		{"wrong azp", sign(with("azp", "other"), key), "", []string{"azp"}},
+		{"expired", sign(with("exp", now.Add(-2*time.Minute).Unix()), key), "", []string{"exp"}},
+		{"expired within clock skew", sign(with("exp", now.Add(-30*time.Second).Unix()), key), "", nil},
+		{"issued in the future", sign(with("iat", now.Add(5*time.Minute).Unix()), key), "", []string{"iat"}},
+		{"nonce mismatch", sign(with("nonce", "other"), key), "", []string{"nonce"}},
+		{"missing nonce", sign(with("nonce", nil), key), "", []string{"nonce"}},
+		{"at_hash mismatch", sign(with("at_hash", "invalid"), key), "", []string{"at_hash"}},
+		{"auth_time too old", sign(with("auth_time", now.Add(-time.Hour).Unix()), key), "300", []string{"auth_time"}},
+		{"auth_time missing with max_age", sign(with("auth_time", nil), key), "300", []string{"auth_time"}},
+		{"multiple failures", sign(with("iss", "https://evil.example.com"), otherKey), "", []string{"signature", "iss"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				IssuerURL: "https://example.com",
+				ClientID:  "client-id",
+				jwks:      &crypto.JWKSet{Keys: []crypto.JWK{*jwk}},
+			}
+			report, claims := cfg.validateIDToken(context.Background(), tt.token, &idTokenExpectations{
+				Nonce:       "nonce-123",
+				AccessToken: "access-token",
+				MaxAge:      tt.maxAge,
+				ClockSkew:   DefaultClockSkew,
+			})
+			if claims == nil {
+				t.Fatal("validateIDToken() claims = nil, want claims")
+			}
+			failed := report.Failed()
+			if len(failed) != len(tt.wantFailed) {
+				t.Fatalf("validateIDToken() failed checks = %v, want %v", failed, tt.wantFailed)
+			}
+			for i := range failed {
+				if failed[i] != tt.wantFailed[i] {
+					t.Errorf("validateIDToken() failed checks = %v, want %v", failed, tt.wantFailed)
+				}
+			}
+			if (report.Err() != nil) != (len(tt.wantFailed) > 0) {
+				t.Errorf("Err() = %v, want error %v", report.Err(), len(tt.wantFailed) > 0)
+			}
+		})
+	}
+}
+
+func TestValidateIDTokenEncrypted(t *testing.T) {
+	signingKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	encryptionKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	jwk, _ := crypto.NewJWK(&signingKey.PublicKey, "k1")
+
+	signed, _ := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": "https://example.com",
+		"aud": "client-id",
+		"exp": time.Now().Add(time.Hour).Unix(),
+		"iat": time.Now().Unix(),
+	}).SignedString(signingKey)
+	encrypted, err := crypto.EncryptJWE([]byte(signed), &encryptionKey.PublicKey, crypto.JWEAlgECDHES, crypto.JWEEncA128GCM, map[string]any{"cty": "JWT"})
+	if err != nil {
+		t.Fatalf("EncryptJWE() error = %v", err)
+	}
+
+	cfg := &Config{
+		IssuerURL: "https://example.com",
+		ClientID:  "client-id",
+		jwks:      &crypto.JWKSet{Keys: []crypto.JWK{*jwk}},
+	}
+	report, _ := cfg.validateIDToken(context.Background(), encrypted, &idTokenExpectations{ClockSkew: DefaultClockSkew})
+	if failed := report.Failed(); len(failed) != 1 || failed[0] != "decryption" {
+		t.Errorf("validateIDToken() without private key failed checks = %v, want [decryption]", failed)
+	}
+
+	cfg.PrivateKey = encryptionKey
+	report, _ = cfg.validateIDToken(context.Background(), encrypted, &idTokenExpectations{ClockSkew: DefaultClockSkew})
+	if err := report.Err(); err != nil {
+		t.Errorf("validateIDToken() error = %v, want nil", err)
+	}
+}
+
+func TestValidateIDTokenFrontChannel(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	jwk, _ := crypto.NewJWK(&key.PublicKey, "k1")
+	cHash, _ := crypto.TokenHash("code", "ES256")
+	atHash, _ := crypto.TokenHash("access-token", "ES256")
+
+	sign := func(hashes map[string]string) string {
+		claims := jwt.MapClaims{
+			"iss":   "https://example.com",
+			"aud":   "client-id",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"iat":   time.Now().Unix(),
+			"nonce": "nonce-123",
+		}
+		for name, value := range hashes {
+			claims[name] = value
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+		token.Header["kid"] = "k1"
+		signed, _ := token.SignedString(key)
+		return signed
+	}
+
+	tests := []struct {
+		name        string
+		token       string
+		code        string
+		accessToken string
+		wantFailed  []string
+	}{
+		{"code id_token", sign(map[string]string{"c_hash": cHash}), "code", "", nil},
+		{"code id_token token", sign(map[string]string{"c_hash": cHash, "at_hash": atHash}), "code", "access-token", nil},
+		{"id_token token", sign(map[string]string{"at_hash": atHash}), "", "access-token", nil},
+		{"id_token", sign(nil), "", "", nil},
+		{"missing c_hash", sign(nil), "code", "", []string{"c_hash"}},
+		{"missing at_hash", sign(nil), "", "access-token", []string{"at_hash"}},
+		{"c_hash mismatch", sign(map[string]string{"c_hash": atHash}), "code", "", []string{"c_hash"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				IssuerURL: "https://example.com",
+				ClientID:  "client-id",
+				jwks:      &crypto.JWKSet{Keys: []crypto.JWK{*jwk}},
+			}
+			report, _ := cfg.validateIDToken(context.Background(), tt.token, &idTokenExpectations{
+				Nonce:        "nonce-123",
+				Code:         tt.code,
+				AccessToken:  tt.accessToken,
+				ClockSkew:    DefaultClockSkew,
+				FrontChannel: true,
+			})
+			failed := report.Failed()
+			if len(failed) != len(tt.wantFailed) || (len(failed) > 0 && failed[0] != tt.wantFailed[0]) {
+				t.Errorf("validateIDToken() failed checks = %v, want %v", failed, tt.wantFailed)
+			}
+		})
+	}
+}
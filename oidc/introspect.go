@@ -27,6 +27,7 @@ func (c *IntrospectFlow) Run(ctx context.Context) error {
 
 	req := &httpclient.IntrospectionRequest{
 		AuthMethod:      c.Config.AuthMethod,
+		ClientAssertion: c.Config.clientAssertion(),
 		ClientID:        c.Config.ClientID,
 		ClientSecret:    c.Config.ClientSecret,
 		BearerToken:     c.FlowConfig.BearerToken,
@@ -0,0 +1,102 @@
+package oidc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jentz/oidc-cli/crypto"
+	"github.com/jentz/oidc-cli/httpclient"
+	"github.com/jentz/oidc-cli/log"
+)
+
+// JWKSCacheTTL is how long a key set cached on disk is used before it is fetched again
+const JWKSCacheTTL = time.Hour
+
+// JWKS returns the issuer's JSON Web Key Set. The set is fetched from the jwks_uri once
+// and reused for the lifetime of the configuration. If JWKSCacheDir is set, the set is
+// also cached on disk across invocations.
+func (c *Config) JWKS(ctx context.Context) (*crypto.JWKSet, error) {
+	if c.jwks != nil {
+		return c.jwks, nil
+	}
+	if c.JWKSEndpoint == "" {
+		return nil, errors.New("jwks endpoint is not available, use --jwks-url to set it")
+	}
+
+	if keys := c.readJWKSCache(); keys != nil {
+		c.jwks = keys
+		c.jwksFromCache = true
+		return keys, nil
+	}
+	return c.refreshJWKS(ctx)
+}
+
+// refreshJWKS fetches the key set from the jwks_uri, bypassing any cached copy
+func (c *Config) refreshJWKS(ctx context.Context) (*crypto.JWKSet, error) {
+	resp, err := c.Client.ExecuteJWKSRequest(ctx, c.JWKSEndpoint)
+	if err != nil {
+		return nil, httpclient.WrapError(err, "jwks")
+	}
+	keys, err := httpclient.ParseJWKSResponse(resp)
+	if err != nil {
+		return nil, httpclient.WrapError(err, "jwks")
+	}
+	c.jwks = keys
+	c.jwksFromCache = false
+	c.writeJWKSCache(keys)
+	return keys, nil
+}
+
+// jwksCacheFile returns the cache file for the jwks_uri, or an empty string if caching is disabled
+func (c *Config) jwksCacheFile() string {
+	if c.JWKSCacheDir == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(c.JWKSEndpoint))
+	return filepath.Join(c.JWKSCacheDir, hex.EncodeToString(sum[:16])+".json")
+}
+
+func (c *Config) readJWKSCache() *crypto.JWKSet {
+	file := c.jwksCacheFile()
+	if file == "" {
+		return nil
+	}
+	info, err := os.Stat(file)
+	if err != nil || time.Since(info.ModTime()) > JWKSCacheTTL {
+		return nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil
+	}
+	keys, err := crypto.ParseJWKSet(data)
+	if err != nil {
+		log.Printf("ignoring invalid jwks cache %s: %v\n", file, err)
+		return nil
+	}
+	log.Printf("using jwks cached in %s\n", file)
+	return keys
+}
+
+func (c *Config) writeJWKSCache(keys *crypto.JWKSet) {
+	file := c.jwksCacheFile()
+	if file == "" {
+		return
+	}
+	data, err := json.Marshal(keys)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(file), 0o700)
+	}
+	if err == nil {
+		err = os.WriteFile(file, data, 0o600)
+	}
+	if err != nil {
+		log.Printf("failed to cache jwks: %v\n", err)
+	}
+}
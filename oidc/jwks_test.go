@@ -0,0 +1,76 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jentz/oidc-cli/crypto"
+	"github.com/jentz/oidc-cli/httpclient"
+)
+
+func TestJWKSCache(t *testing.T) {
+	oldKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	newKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	oldJWK, _ := crypto.NewJWK(&oldKey.PublicKey, "old")
+	newJWK, _ := crypto.NewJWK(&newKey.PublicKey, "new")
+
+	requests := 0
+	current := oldJWK
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		_ = json.NewEncoder(w).Encode(crypto.JWKSet{Keys: []crypto.JWK{*current}})
+	}))
+	defer ts.Close()
+
+	cacheDir := t.TempDir()
+	newConfig := func() *Config {
+		return &Config{
+			JWKSEndpoint: ts.URL,
+			JWKSCacheDir: cacheDir,
+			Client:       httpclient.NewClient(nil),
+		}
+	}
+
+	// The first invocation fetches the keys and caches them on disk
+	if _, err := newConfig().JWKS(context.Background()); err != nil {
+		t.Fatalf("JWKS() error = %v", err)
+	}
+	// A second invocation uses the cache
+	keys, err := newConfig().JWKS(context.Background())
+	if err != nil {
+		t.Fatalf("JWKS() error = %v", err)
+	}
+	if requests != 1 || keys.Keys[0].Kid != "old" {
+		t.Fatalf("JWKS() requests = %d kid = %q, want 1 request and kid old", requests, keys.Keys[0].Kid)
+	}
+
+	// After key rotation, a token signed with an unknown key triggers a refresh
+	current = newJWK
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{"sub": "alice"})
+	token.Header["kid"] = "new"
+	signed, _ := token.SignedString(newKey)
+
+	cfg := newConfig()
+	report := &ValidationReport{Subject: "JWT"}
+	cfg.checkSignature(context.Background(), report, signed, "ES256")
+	if err := report.Err(); err != nil {
+		t.Errorf("checkSignature() error = %v, want nil", err)
+	}
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2", requests)
+	}
+}
+
+func TestJWKSWithoutEndpoint(t *testing.T) {
+	cfg := &Config{}
+	if _, err := cfg.JWKS(context.Background()); err == nil {
+		t.Error("JWKS() error = nil, want error")
+	}
+}
@@ -0,0 +1,136 @@
+package oidc
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jentz/oidc-cli/crypto"
+	"github.com/jentz/oidc-cli/httpclient"
+	"github.com/jentz/oidc-cli/log"
+)
+
+// DefaultAssertionLifetime is the default lifetime of assertions signed for the JWT bearer grant
+const DefaultAssertionLifetime = 5 * time.Minute
+
+type JWTBearerFlow struct {
+	Config     *Config
+	FlowConfig *JWTBearerFlowConfig
+}
+
+type JWTBearerFlowConfig struct {
+	Assertion string // an existing assertion, otherwise one is signed with the private key
+	Issuer    string // defaults to the client ID
+	Subject   string // defaults to the issuer
+	Audience  string // defaults to the token endpoint
+	Lifetime  time.Duration
+	Algorithm string
+	Claims    map[string]interface{}
+	Scopes    string
+	Resources []string
+}
+
+func (c *JWTBearerFlow) Run(ctx context.Context) error {
+	client := c.Config.Client
+	// Client authentication is optional for the JWT bearer grant (RFC 7523 section 3.1)
+	if !c.Config.hasClientCredentials() {
+		c.Config.AuthMethod = httpclient.AuthMethodNone
+	}
+
+	assertion := c.FlowConfig.Assertion
+	if assertion == "" {
+		var err error
+		assertion, err = c.signAssertion()
+		if err != nil {
+			return err
+		}
+		// The signed assertion is a credential, only its header and claims are logged
+		if parts := strings.Split(assertion, "."); len(parts) == 3 {
+			header, _ := base64.RawURLEncoding.DecodeString(parts[0])
+			claims, _ := base64.RawURLEncoding.DecodeString(parts[1])
+			log.Printf("signed assertion header: %s\n", header)
+			log.Printf("signed assertion claims: %s\n", claims)
+		}
+	}
+
+	req := httpclient.CreateJWTBearerRequest(
+		c.Config.ClientID,
+		c.Config.ClientSecret,
+		c.Config.AuthMethod,
+		assertion,
+		c.FlowConfig.Scopes,
+	)
+	req.ClientAssertion = c.Config.clientAssertion()
+	req.Resources = c.FlowConfig.Resources
+
+	resp, err := client.ExecuteTokenRequest(ctx, c.Config.TokenEndpoint, req, nil /* no custom headers */)
+	if err != nil {
+		return fmt.Errorf("token request failed: %w", err)
+	}
+
+	tokenData, err := httpclient.ParseTokenResponse(resp)
+	if err != nil {
+		return httpclient.WrapError(err, "token")
+	}
+
+	// Print available response data
+	prettyJSON, err := json.MarshalIndent(tokenData, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to format token response: %w", err)
+	}
+	log.Outputf("%s\n", string(prettyJSON))
+	warnAudienceMismatch(c.FlowConfig.Resources, tokenData)
+	return c.Config.checkCertificateBinding(tokenData)
+}
+
+// signAssertion creates an assertion for the JWT bearer grant (RFC 7523 section 3) signed with the private key
+func (c *JWTBearerFlow) signAssertion() (string, error) {
+	if c.Config.PrivateKey == nil {
+		return "", errors.New("a private key is required to sign the assertion")
+	}
+
+	issuer := c.FlowConfig.Issuer
+	if issuer == "" {
+		issuer = c.Config.ClientID
+	}
+	subject := c.FlowConfig.Subject
+	if subject == "" {
+		subject = issuer
+	}
+	audience := c.FlowConfig.Audience
+	if audience == "" {
+		audience = c.Config.TokenEndpoint
+	}
+	lifetime := c.FlowConfig.Lifetime
+	if lifetime <= 0 {
+		lifetime = DefaultAssertionLifetime
+	}
+	jti, err := crypto.GenerateRandomValue()
+	if err != nil {
+		return "", err
+	}
+
+	// Extra claims are set first, so that they cannot override the registered claims
+	claims := jwt.MapClaims{}
+	for name, value := range c.FlowConfig.Claims {
+		claims[name] = value
+	}
+	now := timeNow()
+	claims["iss"] = issuer
+	claims["sub"] = subject
+	claims["aud"] = audience
+	claims["jti"] = jti
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(lifetime).Unix()
+
+	signed, err := crypto.SignJWT(claims, c.Config.PrivateKey, c.Config.KeyID, c.FlowConfig.Algorithm)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign assertion: %w", err)
+	}
+	return signed, nil
+}
@@ -0,0 +1,60 @@
+package oidc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestSignAssertion(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	flow := &JWTBearerFlow{
+		Config: &Config{
+			ClientID:      "client-id",
+			TokenEndpoint: "https://example.com/token",
+			PrivateKey:    key,
+			KeyID:         "key-1",
+		},
+		FlowConfig: &JWTBearerFlowConfig{
+			Claims: map[string]interface{}{"tenant": "acme", "iss": "ignored"},
+		},
+	}
+
+	assertion, err := flow.signAssertion()
+	if err != nil {
+		t.Fatalf("signAssertion() error = %v", err)
+	}
+	token, _, err := jwt.NewParser().ParseUnverified(assertion, jwt.MapClaims{})
+	if err != nil {
+		t.Fatalf("failed to parse assertion: %v", err)
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	want := map[string]interface{}{
+		"iss":    "client-id",
+		"sub":    "client-id",
+		"aud":    "https://example.com/token",
+		"tenant": "acme",
+		"exp":    float64(now.Add(DefaultAssertionLifetime).Unix()),
+	}
+	for name, value := range want {
+		if claims[name] != value {
+			t.Errorf("claim %s = %v, want %v", name, claims[name], value)
+		}
+	}
+	if token.Header["kid"] != "key-1" {
+		t.Errorf("kid = %v, want key-1", token.Header["kid"])
+	}
+
+	flow.Config.PrivateKey = nil
+	if _, err := flow.signAssertion(); err == nil {
+		t.Error("signAssertion() without private key error = nil, want error")
+	}
+}
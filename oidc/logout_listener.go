@@ -0,0 +1,200 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jentz/oidc-cli/log"
+	"github.com/jentz/oidc-cli/webflow"
+)
+
+const (
+	// LogoutTokenType is the typ header of logout tokens (OpenID Connect Back-Channel Logout section 2.4)
+	LogoutTokenType = "logout+jwt"
+	// BackChannelLogoutEvent is the member of the events claim that identifies a logout token
+	BackChannelLogoutEvent = "http://schemas.openid.net/event/backchannel-logout"
+)
+
+type LogoutListenerFlow struct {
+	Config     *Config
+	FlowConfig *LogoutListenerFlowConfig
+
+	mu   sync.Mutex          // serializes the validation and printing of concurrent logout tokens
+	jtis map[string]struct{} // jti of the logout tokens received, to detect replays
+}
+
+type LogoutListenerFlowConfig struct {
+	BackChannelURI  string // back-channel logout URI registered for the client, empty to not listen for logout tokens
+	FrontChannelURI string // front-channel logout URI registered for the client, empty to not listen for front-channel requests
+	ListenAddr      string // local address to listen on, defaults to the host of the URIs
+	Count           int    // number of notifications to wait for, 0 to listen until interrupted
+	ClockSkew       time.Duration
+}
+
+func (c *LogoutListenerFlow) Run(ctx context.Context) error {
+	server, err := webflow.NewLogoutServer(c.FlowConfig.BackChannelURI, c.FlowConfig.FrontChannelURI, c.FlowConfig.ListenAddr, c.checkLogoutToken)
+	if err != nil {
+		return fmt.Errorf("failed to create logout server: %w", err)
+	}
+
+	serverErrChan := make(chan error, 1)
+	go func() {
+		if err := server.Start(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErrChan <- err
+		}
+	}()
+
+	// Give the server a moment to start or fail
+	select {
+	case err := <-serverErrChan:
+		return fmt.Errorf("logout server failed to start: %w", err)
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(100 * time.Millisecond):
+		// Server started successfully
+	}
+	if c.FlowConfig.BackChannelURI != "" {
+		log.Errorf("Listening for back-channel logout tokens on %s\n", c.FlowConfig.BackChannelURI)
+	}
+	if c.FlowConfig.FrontChannelURI != "" {
+		log.Errorf("Listening for front-channel logout requests on %s\n", c.FlowConfig.FrontChannelURI)
+	}
+
+	for received := 0; c.FlowConfig.Count == 0 || received < c.FlowConfig.Count; received++ {
+		n, err := server.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		switch {
+		case n.Channel == webflow.LogoutFrontChannel:
+			c.printFrontChannelLogout(n)
+		case n.LogoutToken == "":
+			// Logout tokens are reported as they are validated, only requests without one are left
+			log.Errorf("Rejected back-channel logout request: %v\n", n.Err)
+		}
+	}
+	return nil
+}
+
+// checkLogoutToken validates a logout token received on the back-channel logout URI and prints it
+// together with the validation report. It is called by the logout server before answering the OP.
+func (c *LogoutListenerFlow) checkLogoutToken(ctx context.Context, logoutToken string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	report, claims := c.validateLogoutToken(ctx, logoutToken)
+	log.Errorf("Back-channel logout notification:\n")
+	if claims != nil {
+		prettyJSON, err := json.MarshalIndent(claims, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format claims: %w", err)
+		}
+		log.Outputf("%s\n", string(prettyJSON))
+	}
+	report.Print()
+	return report.Err()
+}
+
+// validateLogoutToken validates a logout token as described in OpenID Connect Back-Channel Logout section 2.6.
+// The claims are returned when the token could be parsed, even if some checks failed.
+func (c *LogoutListenerFlow) validateLogoutToken(ctx context.Context, logoutToken string) (*ValidationReport, jwt.MapClaims) {
+	report := &ValidationReport{Subject: "logout token"}
+
+	token, parsed := c.Config.parseForValidation(report, logoutToken)
+	if parsed == nil {
+		return report, nil
+	}
+	claims := parsed.Claims.(jwt.MapClaims)
+	alg, _ := parsed.Header["alg"].(string)
+
+	c.Config.checkSignature(ctx, report, token, alg)
+	// Explicit typing is only recommended, so only an explicit type other than logout+jwt fails
+	if typ, _ := parsed.Header["typ"].(string); typ == "" || strings.EqualFold(typ, "JWT") {
+		report.skip("typ", "%q, not explicitly typed", typ)
+	} else {
+		checkType(report, parsed.Header, LogoutTokenType)
+	}
+	checkIssuer(report, claims, c.Config.IssuerURL)
+	checkAudience(report, claims, c.Config.ClientID)
+	checkExpiry(report, claims, c.FlowConfig.ClockSkew, true)
+	checkIssuedAt(report, claims, c.FlowConfig.ClockSkew, true)
+	checkLogoutEvent(report, claims)
+	checkLogoutSubject(report, claims)
+	c.checkLogoutTokenID(report, claims)
+	if _, ok := claims["nonce"]; ok {
+		report.fail("nonce", "present, logout tokens must not contain a nonce")
+	} else {
+		report.pass("nonce", "not present")
+	}
+
+	return report, claims
+}
+
+// checkLogoutEvent checks that the events claim contains the back-channel logout event with a JSON object value
+func checkLogoutEvent(report *ValidationReport, claims jwt.MapClaims) {
+	events, ok := claims["events"].(map[string]interface{})
+	if !ok {
+		report.fail("events", "missing or not a JSON object")
+		return
+	}
+	if _, ok := events[BackChannelLogoutEvent].(map[string]interface{}); !ok {
+		report.fail("events", "does not contain %s with a JSON object value", BackChannelLogoutEvent)
+		return
+	}
+	report.pass("events", "contains %s", BackChannelLogoutEvent)
+}
+
+// checkLogoutSubject checks that the token identifies the session or the user being logged out
+func checkLogoutSubject(report *ValidationReport, claims jwt.MapClaims) {
+	sub, _ := claims["sub"].(string)
+	sid, _ := claims["sid"].(string)
+	switch {
+	case sub == "" && sid == "":
+		report.fail("sub/sid", "neither sub nor sid is present")
+	case sid == "":
+		report.pass("sub/sid", "all sessions of sub %q", sub)
+	case sub == "":
+		report.pass("sub/sid", "session %q", sid)
+	default:
+		report.pass("sub/sid", "session %q of sub %q", sid, sub)
+	}
+}
+
+// checkLogoutTokenID checks that the token has a jti that was not received before
+func (c *LogoutListenerFlow) checkLogoutTokenID(report *ValidationReport, claims jwt.MapClaims) {
+	jti, _ := claims["jti"].(string)
+	if jti == "" {
+		report.fail("jti", "missing")
+		return
+	}
+	if _, ok := c.jtis[jti]; ok {
+		report.fail("jti", "%q was already received, the token is replayed", jti)
+		return
+	}
+	if c.jtis == nil {
+		c.jtis = make(map[string]struct{})
+	}
+	c.jtis[jti] = struct{}{}
+	report.pass("jti", "%q not seen before", jti)
+}
+
+// printFrontChannelLogout prints the iss and sid parameters of a front-channel logout request
+func (c *LogoutListenerFlow) printFrontChannelLogout(n *webflow.LogoutNotification) {
+	log.Errorf("Front-channel logout notification:\n")
+	prettyJSON, _ := json.MarshalIndent(map[string]string{"iss": n.Issuer, "sid": n.SessionID}, "", "  ")
+	log.Outputf("%s\n", string(prettyJSON))
+
+	switch {
+	case n.Issuer == "" && n.SessionID == "":
+		log.Errorf("  no iss and sid, the OP does not identify the session\n")
+	case n.Issuer != c.Config.IssuerURL:
+		log.Errorf("  WARNING: iss %q does not match issuer %q\n", n.Issuer, c.Config.IssuerURL)
+	}
+}
@@ -0,0 +1,103 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jentz/oidc-cli/crypto"
+)
+
+func TestValidateLogoutToken(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	jwk, _ := crypto.NewJWK(&key.PublicKey, "k1")
+
+	issued := 0
+	// logoutClaims returns valid logout token claims with a unique jti, with the given claims replaced or removed
+	logoutClaims := func(replace map[string]any) jwt.MapClaims {
+		issued++
+		claims := jwt.MapClaims{
+			"iss":    "https://example.com",
+			"aud":    "client-id",
+			"sub":    "alice",
+			"sid":    "session-1",
+			"iat":    now.Unix(),
+			"exp":    now.Add(2 * time.Minute).Unix(),
+			"jti":    fmt.Sprintf("jti-%d", issued),
+			"events": map[string]any{BackChannelLogoutEvent: map[string]any{}},
+		}
+		for name, value := range replace {
+			if value == nil {
+				delete(claims, name)
+			} else {
+				claims[name] = value
+			}
+		}
+		return claims
+	}
+	sign := func(typ string, claims jwt.MapClaims) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+		token.Header["kid"] = "k1"
+		if typ == "" {
+			delete(token.Header, "typ")
+		} else {
+			token.Header["typ"] = typ
+		}
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatalf("failed to sign token: %v", err)
+		}
+		return signed
+	}
+
+	flow := &LogoutListenerFlow{
+		Config: &Config{
+			IssuerURL: "https://example.com",
+			ClientID:  "client-id",
+			jwks:      &crypto.JWKSet{Keys: []crypto.JWK{*jwk}},
+		},
+		FlowConfig: &LogoutListenerFlowConfig{ClockSkew: DefaultClockSkew},
+	}
+	replayed := sign(LogoutTokenType, logoutClaims(nil))
+
+	tests := []struct {
+		name       string
+		token      string
+		wantFailed []string
+	}{
+		{"valid logout token", sign(LogoutTokenType, logoutClaims(nil)), nil},
+		{"untyped logout token", sign("", logoutClaims(nil)), nil},
+		{"generic typ", sign("JWT", logoutClaims(nil)), nil},
+		{"only sid", sign(LogoutTokenType, logoutClaims(map[string]any{"sub": nil})), nil},
+		{"only sub", sign(LogoutTokenType, logoutClaims(map[string]any{"sid": nil})), nil},
+		{"wrong typ", sign("at+jwt", logoutClaims(nil)), []string{"typ"}},
+		{"wrong audience", sign(LogoutTokenType, logoutClaims(map[string]any{"aud": "other-client"})), []string{"aud"}},
+		{"expired", sign(LogoutTokenType, logoutClaims(map[string]any{"exp": now.Add(-time.Hour).Unix()})), []string{"exp"}},
+		{"missing events", sign(LogoutTokenType, logoutClaims(map[string]any{"events": nil})), []string{"events"}},
+		{"other event", sign(LogoutTokenType, logoutClaims(map[string]any{"events": map[string]any{"urn:example:event": map[string]any{}}})), []string{"events"}},
+		{"missing sub and sid", sign(LogoutTokenType, logoutClaims(map[string]any{"sub": nil, "sid": nil})), []string{"sub/sid"}},
+		{"missing jti", sign(LogoutTokenType, logoutClaims(map[string]any{"jti": nil})), []string{"jti"}},
+		{"nonce present", sign(LogoutTokenType, logoutClaims(map[string]any{"nonce": "n-1"})), []string{"nonce"}},
+		{"first delivery", replayed, nil},
+		{"replayed", replayed, []string{"jti"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, _ := flow.validateLogoutToken(context.Background(), tt.token)
+			if failed := report.Failed(); !slices.Equal(failed, tt.wantFailed) {
+				t.Errorf("validateLogoutToken() failed checks = %v, want %v", failed, tt.wantFailed)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package oidc
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 
 	"github.com/jentz/oidc-cli/crypto"
@@ -17,15 +18,35 @@ type Config struct {
 	PushedAuthorizationRequestEndpoint string
 	TokenEndpoint                      string
 	IntrospectionEndpoint              string
+	RevocationEndpoint                 string
 	UserinfoEndpoint                   string
+	DeviceAuthorizationEndpoint        string
+	BackchannelAuthenticationEndpoint  string
+	EndSessionEndpoint                 string
+	RegistrationEndpoint               string
 	JWKSEndpoint                       string
+	JWKSCacheDir                       string
+	AuthorizationResponseIssSupported  bool
+	TLSClientCertificateBoundTokens    bool
 	SkipTLSVerify                      bool
 	AuthMethod                         httpclient.AuthMethod
+	KeyID                              string
+	ClientAssertionAlg                 string
+	ClientAssertionAudience            string
+	ClientAssertionFile                string
+	ClientAssertionCmd                 string
 	PrivateKeyFile                     string
+	PrivateKeyForDPoP                  bool // the private key is the DPoP key, a client credential only with auth-method private_key_jwt
 	PublicKeyFile                      string
 	PrivateKey                         any
 	PublicKey                          any
+	ClientCertificateFile              string
+	ClientKeyFile                      string
+	ClientCertificate                  *tls.Certificate
 	Client                             *httpclient.Client
+
+	jwks          *crypto.JWKSet // key set in use, see JWKS
+	jwksFromCache bool           // whether jwks was read from the disk cache
 }
 
 func (c *Config) DiscoverEndpoints(ctx context.Context) error {
@@ -36,43 +57,122 @@ func (c *Config) DiscoverEndpoints(ctx context.Context) error {
 		return fmt.Errorf("endpoint discovery failed: %w", err)
 	}
 
-	// Set endpoints from discovery config if not already set by user
+	// Set endpoints from discovery config if not already set by user.
+	// Clients with a certificate use the mutual TLS aliases where the server has them.
+	mtls := c.ClientCertificateFile != ""
+
 	if c.AuthorizationEndpoint == "" {
 		c.AuthorizationEndpoint = discoveryConfig.AuthorizationEndpoint
 	}
 
 	if c.PushedAuthorizationRequestEndpoint == "" {
-		c.PushedAuthorizationRequestEndpoint = discoveryConfig.PushedAuthorizationRequestEndpoint
+		c.PushedAuthorizationRequestEndpoint = discoveryConfig.endpoint("pushed_authorization_request_endpoint", discoveryConfig.PushedAuthorizationRequestEndpoint, mtls)
 	}
 
 	if c.TokenEndpoint == "" {
-		c.TokenEndpoint = discoveryConfig.TokenEndpoint
+		c.TokenEndpoint = discoveryConfig.endpoint("token_endpoint", discoveryConfig.TokenEndpoint, mtls)
 	}
 
 	if c.IntrospectionEndpoint == "" {
-		c.IntrospectionEndpoint = discoveryConfig.IntrospectionEndpoint
+		c.IntrospectionEndpoint = discoveryConfig.endpoint("introspection_endpoint", discoveryConfig.IntrospectionEndpoint, mtls)
+	}
+
+	if c.RevocationEndpoint == "" {
+		c.RevocationEndpoint = discoveryConfig.endpoint("revocation_endpoint", discoveryConfig.RevocationEndpoint, mtls)
 	}
 
 	if c.UserinfoEndpoint == "" {
-		c.UserinfoEndpoint = discoveryConfig.UserinfoEndpoint
+		c.UserinfoEndpoint = discoveryConfig.endpoint("userinfo_endpoint", discoveryConfig.UserinfoEndpoint, mtls)
+	}
+
+	if c.DeviceAuthorizationEndpoint == "" {
+		c.DeviceAuthorizationEndpoint = discoveryConfig.endpoint("device_authorization_endpoint", discoveryConfig.DeviceAuthorizationEndpoint, mtls)
+	}
+
+	if c.BackchannelAuthenticationEndpoint == "" {
+		c.BackchannelAuthenticationEndpoint = discoveryConfig.endpoint("backchannel_authentication_endpoint", discoveryConfig.BackchannelAuthenticationEndpoint, mtls)
+	}
+
+	if c.EndSessionEndpoint == "" {
+		c.EndSessionEndpoint = discoveryConfig.EndSessionEndpoint
+	}
+
+	if c.RegistrationEndpoint == "" {
+		c.RegistrationEndpoint = discoveryConfig.RegistrationEndpoint
 	}
 
 	if c.JWKSEndpoint == "" {
 		c.JWKSEndpoint = discoveryConfig.JwksURI
 	}
 
+	c.AuthorizationResponseIssSupported = discoveryConfig.AuthorizationResponseIssSupported
+	c.TLSClientCertificateBoundTokens = discoveryConfig.TLSClientCertificateBoundTokens
+
 	// set default auth method if not set by user
 	if c.AuthMethod == "" {
-		for _, method := range discoveryConfig.TokenEndpointAuthMethods {
-			authMethodValue := httpclient.AuthMethod(method)
-			if authMethodValue.IsValid() {
-				c.AuthMethod = authMethodValue
-				break
+		c.AuthMethod = c.selectAuthMethod(discoveryConfig.TokenEndpointAuthMethods)
+	}
+
+	return nil
+}
+
+// selectAuthMethod picks an auth method advertised by the server that the client has credentials for.
+// Clients without a secret prefer private_key_jwt if they have a private key that is not their DPoP
+// key, or mutual TLS if they have a client certificate, in the order advertised. Client assertions
+// from a file or command are always sent with private_key_jwt.
+func (c *Config) selectAuthMethod(supported []string) httpclient.AuthMethod {
+	if c.hasClientAssertionSource() {
+		return httpclient.AuthMethodPrivateKeyJWT
+	}
+	var selected, preferred httpclient.AuthMethod
+	for _, method := range supported {
+		authMethodValue := httpclient.AuthMethod(method)
+		if !authMethodValue.IsValid() {
+			continue
+		}
+		switch {
+		case authMethodValue == httpclient.AuthMethodPrivateKeyJWT:
+			if c.ClientSecret == "" && c.PrivateKeyFile != "" && !c.PrivateKeyForDPoP && preferred == "" {
+				preferred = authMethodValue
+			}
+			continue
+		case authMethodValue.IsMutualTLS():
+			if c.ClientSecret == "" && c.ClientCertificateFile != "" && preferred == "" {
+				preferred = authMethodValue
 			}
+			continue
+		case authMethodValue == httpclient.AuthMethodSecretJWT && c.ClientSecret == "":
+			continue
 		}
+		if selected == "" {
+			selected = authMethodValue
+		}
+	}
+	if preferred != "" {
+		return preferred
 	}
+	return selected
+}
 
-	return nil
+// hasClientCredentials reports whether the client can authenticate itself,
+// clients without credentials fall back to the none auth method
+func (c *Config) hasClientCredentials() bool {
+	return c.ClientSecret != "" || c.AuthMethod == httpclient.AuthMethodPrivateKeyJWT || c.AuthMethod.IsMutualTLS()
+}
+
+// clientAssertion returns the parameters for signing private_key_jwt client assertions,
+// or for reading them from a file or command
+func (c *Config) clientAssertion() *httpclient.ClientAssertion {
+	assertion := &httpclient.ClientAssertion{
+		PrivateKey: c.PrivateKey,
+		KeyID:      c.KeyID,
+		Algorithm:  c.ClientAssertionAlg,
+		Audience:   c.ClientAssertionAudience,
+	}
+	if c.hasClientAssertionSource() {
+		assertion.Provider = c.readClientAssertion
+	}
+	return assertion
 }
 
 func (c *Config) ReadKeyFiles() error {
@@ -99,5 +199,21 @@ func (c *Config) ReadKeyFiles() error {
 			return fmt.Errorf("failed to parse public key: %v", err)
 		}
 	}
+
+	// Load the client certificate for mutual TLS if provided, the key may be in the same file
+	if c.ClientCertificateFile != "" {
+		keyFile := c.ClientKeyFile
+		if keyFile == "" {
+			keyFile = c.ClientCertificateFile
+		}
+		cert, err := tls.LoadX509KeyPair(c.ClientCertificateFile, keyFile)
+		if err != nil {
+			return fmt.Errorf("could not load client certificate: %w", err)
+		}
+		c.ClientCertificate = &cert
+		if c.Client != nil {
+			c.Client.SetClientCertificate(&cert)
+		}
+	}
 	return nil
 }
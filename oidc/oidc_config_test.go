@@ -0,0 +1,97 @@
+package oidc
+
+import (
+	"testing"
+
+	"github.com/jentz/oidc-cli/httpclient"
+)
+
+func TestSelectAuthMethod(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    Config
+		supported []string
+		want      httpclient.AuthMethod
+	}{
+		{
+			"first supported method",
+			Config{ClientSecret: "secret"},
+			[]string{"client_secret_post", "client_secret_basic"},
+			httpclient.AuthMethodPost,
+		},
+		{
+			"private key without secret",
+			Config{PrivateKeyFile: "private.pem"},
+			[]string{"client_secret_basic", "private_key_jwt"},
+			httpclient.AuthMethodPrivateKeyJWT,
+		},
+		{
+			"dpop key without secret",
+			Config{PrivateKeyFile: "private.pem", PrivateKeyForDPoP: true},
+			[]string{"private_key_jwt", "none"},
+			httpclient.AuthMethodNone,
+		},
+		{
+			"private key with secret",
+			Config{ClientSecret: "secret", PrivateKeyFile: "private.pem"},
+			[]string{"private_key_jwt", "client_secret_basic"},
+			httpclient.AuthMethodBasic,
+		},
+		{
+			"private_key_jwt without private key",
+			Config{ClientSecret: "secret"},
+			[]string{"private_key_jwt", "client_secret_basic"},
+			httpclient.AuthMethodBasic,
+		},
+		{
+			"private_key_jwt not advertised",
+			Config{PrivateKeyFile: "private.pem"},
+			[]string{"client_secret_basic"},
+			httpclient.AuthMethodBasic,
+		},
+		{
+			"client_secret_jwt with secret",
+			Config{ClientSecret: "secret"},
+			[]string{"client_secret_jwt", "client_secret_basic"},
+			httpclient.AuthMethodSecretJWT,
+		},
+		{
+			"client_secret_jwt without secret",
+			Config{},
+			[]string{"client_secret_jwt", "none"},
+			httpclient.AuthMethodNone,
+		},
+		{
+			"client certificate without secret",
+			Config{ClientCertificateFile: "client.pem"},
+			[]string{"client_secret_basic", "self_signed_tls_client_auth", "tls_client_auth"},
+			httpclient.AuthMethodSelfSignedTLSClientAuth,
+		},
+		{
+			"client certificate with secret",
+			Config{ClientSecret: "secret", ClientCertificateFile: "client.pem"},
+			[]string{"tls_client_auth", "client_secret_basic"},
+			httpclient.AuthMethodBasic,
+		},
+		{
+			"client assertion file",
+			Config{ClientAssertionFile: "token"},
+			[]string{"client_secret_basic"},
+			httpclient.AuthMethodPrivateKeyJWT,
+		},
+		{
+			"unknown methods only",
+			Config{ClientSecret: "secret"},
+			[]string{"unknown_method"},
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.selectAuthMethod(tt.supported); got != tt.want {
+				t.Errorf("selectAuthMethod() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
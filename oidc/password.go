@@ -0,0 +1,81 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jentz/oidc-cli/httpclient"
+	"github.com/jentz/oidc-cli/log"
+)
+
+// passwordDeprecationWarning is printed on every use of the password grant
+const passwordDeprecationWarning = `WARNING: the resource owner password credentials grant is deprecated.
+WARNING: it exposes the user's credentials to the client and must not be used (RFC 9700 section 2.4).
+WARNING: it is omitted from OAuth 2.1; use it only to test legacy identity providers.
+`
+
+type PasswordFlow struct {
+	Config     *Config
+	FlowConfig *PasswordFlowConfig
+}
+
+type PasswordFlowConfig struct {
+	Username       string
+	Password       string
+	PromptPassword bool // prompt for the password on the terminal when the flow runs
+	Scopes         string
+	Resources      []string
+	CustomArgs     *httpclient.CustomArgs
+}
+
+func (c *PasswordFlow) Run(ctx context.Context) error {
+	log.Errorf("%s", passwordDeprecationWarning)
+
+	if c.FlowConfig.PromptPassword {
+		password, err := readPasswordPrompt(fmt.Sprintf("Password for %s: ", c.FlowConfig.Username))
+		if err != nil {
+			return err
+		}
+		c.FlowConfig.Password = password
+	}
+
+	if !c.Config.hasClientCredentials() {
+		c.Config.AuthMethod = httpclient.AuthMethodNone
+	}
+
+	req := httpclient.CreatePasswordRequest(
+		c.Config.ClientID,
+		c.Config.ClientSecret,
+		c.Config.AuthMethod,
+		c.FlowConfig.Username,
+		c.FlowConfig.Password,
+		c.FlowConfig.Scopes,
+	)
+	req.ClientAssertion = c.Config.clientAssertion()
+	req.Resources = c.FlowConfig.Resources
+	if c.FlowConfig.CustomArgs != nil {
+		for k, v := range *c.FlowConfig.CustomArgs {
+			req.Params.Set(k, v)
+		}
+	}
+
+	resp, err := c.Config.Client.ExecuteTokenRequest(ctx, c.Config.TokenEndpoint, req, nil /* no custom headers */)
+	if err != nil {
+		return fmt.Errorf("token request failed: %w", err)
+	}
+
+	tokenData, err := httpclient.ParseTokenResponse(resp)
+	if err != nil {
+		return httpclient.WrapError(err, "token")
+	}
+
+	// Print available response data
+	prettyJSON, err := json.MarshalIndent(tokenData, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to format token response: %w", err)
+	}
+	log.Outputf("%s\n", string(prettyJSON))
+	warnAudienceMismatch(c.FlowConfig.Resources, tokenData)
+	return c.Config.checkCertificateBinding(tokenData)
+}
@@ -0,0 +1,51 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jentz/oidc-cli/crypto"
+	"github.com/jentz/oidc-cli/httpclient"
+	"github.com/jentz/oidc-cli/log"
+)
+
+// createRequestObject packs the parameters of the authorization request into a request object
+// signed with the private key (RFC 9101), and encrypts it to the issuer's encryption key if requested
+func (c *AuthorizationCodeFlow) createRequestObject(ctx context.Context, req *httpclient.AuthorizationCodeRequest) (string, error) {
+	if c.Config.PrivateKey == nil {
+		return "", errors.New("a private key is required to sign the request object")
+	}
+	params, err := httpclient.CreateAuthorizationCodeRequestValues(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to create authorization code request values: %w", err)
+	}
+	requestObject, err := crypto.NewRequestObject(*params, c.Config.ClientID, c.Config.IssuerURL, c.Config.PrivateKey, c.Config.KeyID, "")
+	if err != nil {
+		return "", err
+	}
+	log.Printf("request object: %s\n", requestObject)
+
+	if !c.FlowConfig.EncryptRequestObject {
+		return requestObject, nil
+	}
+	keys, err := c.Config.JWKS(ctx)
+	if err != nil {
+		return "", err
+	}
+	key, err := encryptionKey(keys)
+	if err != nil {
+		return "", err
+	}
+	return crypto.EncryptRequestObject(requestObject, key)
+}
+
+// encryptionKey returns the first key of the set that is meant for encryption
+func encryptionKey(keys *crypto.JWKSet) (*crypto.JWK, error) {
+	for i := range keys.Keys {
+		if keys.Keys[i].Use == "enc" {
+			return &keys.Keys[i], nil
+		}
+	}
+	return nil, errors.New("the issuer's key set has no encryption key (use enc)")
+}
@@ -0,0 +1,125 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jentz/oidc-cli/crypto"
+	"github.com/jentz/oidc-cli/httpclient"
+)
+
+func TestCreateAuthCodeRequestWithRequestObject(t *testing.T) {
+	signingKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	encryptionKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	encJWK, _ := crypto.NewJWK(&encryptionKey.PublicKey, "enc-1")
+	encJWK.Use = "enc"
+	sigJWK, _ := crypto.NewJWK(&signingKey.PublicKey, "sig-1")
+
+	var pushed string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(crypto.JWKSet{Keys: []crypto.JWK{*sigJWK, *encJWK}})
+	})
+	mux.HandleFunc("/par", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		pushed = r.PostForm.Get("request")
+		if r.PostForm.Has("state") || r.PostForm.Has("redirect_uri") {
+			t.Errorf("pushed request carries parameters outside of the request object: %v", r.PostForm)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"request_uri":"urn:example:request","expires_in":60}`))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	// verify decrypts the request object if needed and returns its verified claims
+	verify := func(t *testing.T, requestObject string) jwt.MapClaims {
+		t.Helper()
+		if crypto.IsJWE(requestObject) {
+			plaintext, _, err := crypto.DecryptJWE(requestObject, encryptionKey)
+			if err != nil {
+				t.Fatalf("failed to decrypt request object: %v", err)
+			}
+			requestObject = string(plaintext)
+		}
+		token, err := jwt.Parse(requestObject, func(*jwt.Token) (any, error) { return &signingKey.PublicKey, nil })
+		if err != nil {
+			t.Fatalf("failed to verify request object: %v", err)
+		}
+		return token.Claims.(jwt.MapClaims)
+	}
+
+	tests := []struct {
+		name    string
+		par     bool
+		encrypt bool
+	}{
+		{"front channel", false, false},
+		{"pushed and encrypted", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pushed = ""
+			flow := &AuthorizationCodeFlow{
+				Config: &Config{
+					ClientID:                           "client-id",
+					ClientSecret:                       "client-secret",
+					IssuerURL:                          "https://example.com",
+					AuthMethod:                         httpclient.AuthMethodBasic,
+					PushedAuthorizationRequestEndpoint: ts.URL + "/par",
+					JWKSEndpoint:                       ts.URL + "/jwks",
+					PrivateKey:                         signingKey,
+					Client:                             httpclient.NewClient(nil),
+				},
+				FlowConfig: &AuthorizationCodeFlowConfig{
+					Scopes:               "openid",
+					CallbackURI:          "http://localhost:9555/callback",
+					State:                "state-1",
+					PAR:                  tt.par,
+					RequestObject:        true,
+					EncryptRequestObject: tt.encrypt,
+				},
+			}
+
+			req, err := flow.createAuthCodeRequest(context.Background(), "")
+			if err != nil {
+				t.Fatalf("createAuthCodeRequest() error = %v", err)
+			}
+
+			requestObject := req.Request
+			if tt.par {
+				if req.Request != "" || req.RequestURI != "urn:example:request" {
+					t.Fatalf("request = %q, request_uri = %q, want only the request_uri after PAR", req.Request, req.RequestURI)
+				}
+				requestObject = pushed
+			}
+			if crypto.IsJWE(requestObject) != tt.encrypt {
+				t.Fatalf("request object encrypted = %v, want %v", crypto.IsJWE(requestObject), tt.encrypt)
+			}
+
+			claims := verify(t, requestObject)
+			for name, want := range map[string]string{
+				"iss":          "client-id",
+				"aud":          "https://example.com",
+				"client_id":    "client-id",
+				"state":        "state-1",
+				"redirect_uri": "http://localhost:9555/callback",
+			} {
+				if claims[name] != want {
+					t.Errorf("%s = %v, want %v", name, claims[name], want)
+				}
+			}
+		})
+	}
+}
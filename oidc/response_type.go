@@ -0,0 +1,64 @@
+package oidc
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Response modes (OAuth 2.0 Multiple Response Type Encoding Practices section 2.1, OAuth 2.0 Form Post Response Mode,
+// JWT Secured Authorization Response Mode section 2.3)
+const (
+	ResponseModeQuery       = "query"
+	ResponseModeFragment    = "fragment"
+	ResponseModeFormPost    = "form_post"
+	ResponseModeJWT         = "jwt"
+	ResponseModeQueryJWT    = "query.jwt"
+	ResponseModeFragmentJWT = "fragment.jwt"
+	ResponseModeFormPostJWT = "form_post.jwt"
+)
+
+// responseTypeValues are the values a response type can be combined from (OIDC Core section 3)
+var responseTypeValues = []string{"code", "id_token", "token"}
+
+// ResponseTypeIncludes reports whether a response type includes value. An empty response type is code.
+func ResponseTypeIncludes(responseType, value string) bool {
+	if responseType == "" {
+		responseType = "code"
+	}
+	return slices.Contains(strings.Fields(responseType), value)
+}
+
+// ValidateResponseType checks that a response type is a combination of code, id_token and token
+func ValidateResponseType(responseType string) error {
+	values := strings.Fields(responseType)
+	if responseType != "" && len(values) == 0 {
+		return fmt.Errorf("invalid response type %q", responseType)
+	}
+	for i, value := range values {
+		if !slices.Contains(responseTypeValues, value) {
+			return fmt.Errorf("invalid response type %q, must be a combination of %s", responseType, strings.Join(responseTypeValues, ", "))
+		}
+		if slices.Contains(values[:i], value) {
+			return fmt.Errorf("invalid response type %q, %s is repeated", responseType, value)
+		}
+	}
+	return nil
+}
+
+// ValidateResponseMode checks that a response mode is supported and suits the response type.
+// Tokens must not be returned in the query (OAuth 2.0 Multiple Response Type Encoding Practices section 2.1).
+func ValidateResponseMode(responseMode, responseType string) error {
+	switch responseMode {
+	case "", ResponseModeFragment, ResponseModeFormPost, ResponseModeJWT, ResponseModeFragmentJWT, ResponseModeFormPostJWT:
+		return nil
+	case ResponseModeQuery, ResponseModeQueryJWT:
+		// query.jwt may only carry tokens if the response is encrypted, which the client cannot enforce
+		if ResponseTypeIncludes(responseType, "id_token") || ResponseTypeIncludes(responseType, "token") {
+			return fmt.Errorf("response mode %s cannot be used with response type %q, which returns tokens", responseMode, responseType)
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid response mode %q, must be one of query, fragment, form_post, jwt, query.jwt, fragment.jwt or form_post.jwt", responseMode)
+	}
+}
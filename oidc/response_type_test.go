@@ -0,0 +1,56 @@
+package oidc
+
+import "testing"
+
+func TestValidateResponseType(t *testing.T) {
+	tests := []struct {
+		responseType string
+		wantErr      bool
+	}{
+		{"", false},
+		{"code", false},
+		{"code id_token", false},
+		{"id_token token", false},
+		{"code id_token token", false},
+		{"code code", true},
+		{"code token_id", true},
+		{" ", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.responseType, func(t *testing.T) {
+			if err := ValidateResponseType(tt.responseType); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateResponseType(%q) error = %v, want error %v", tt.responseType, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateResponseMode(t *testing.T) {
+	tests := []struct {
+		responseMode string
+		responseType string
+		wantErr      bool
+	}{
+		{"", "code id_token", false},
+		{"query", "code", false},
+		{"query", "", false},
+		{"query", "code id_token", true},
+		{"query", "token", true},
+		{"fragment", "code id_token", false},
+		{"form_post", "id_token token", false},
+		{"jwt", "code id_token", false},
+		{"form_post.jwt", "code", false},
+		{"query.jwt", "code", false},
+		{"query.jwt", "code token", true},
+		{"web_message", "code", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.responseMode+" "+tt.responseType, func(t *testing.T) {
+			if err := ValidateResponseMode(tt.responseMode, tt.responseType); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateResponseMode(%q, %q) error = %v, want error %v", tt.responseMode, tt.responseType, err, tt.wantErr)
+			}
+		})
+	}
+}
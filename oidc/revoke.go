@@ -0,0 +1,58 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jentz/oidc-cli/httpclient"
+	"github.com/jentz/oidc-cli/log"
+)
+
+type RevokeFlow struct {
+	Config     *Config
+	FlowConfig *RevokeFlowConfig
+}
+
+type RevokeFlowConfig struct {
+	Token         string
+	TokenTypeHint string
+	CustomArgs    *httpclient.CustomArgs
+}
+
+func (c *RevokeFlow) Run(ctx context.Context) error {
+	if err := c.revoke(ctx); err != nil {
+		return err
+	}
+	log.Outputln("token revoked")
+	return nil
+}
+
+func (c *RevokeFlow) revoke(ctx context.Context) error {
+	if c.Config.RevocationEndpoint == "" {
+		return errors.New("revocation endpoint is not available, use --revocation-url to set it")
+	}
+	if !c.Config.hasClientCredentials() {
+		c.Config.AuthMethod = httpclient.AuthMethodNone
+	}
+
+	req := &httpclient.RevocationRequest{
+		AuthMethod:      c.Config.AuthMethod,
+		ClientAssertion: c.Config.clientAssertion(),
+		ClientID:        c.Config.ClientID,
+		ClientSecret:    c.Config.ClientSecret,
+		Token:           c.FlowConfig.Token,
+		TokenTypeHint:   c.FlowConfig.TokenTypeHint,
+		CustomArgs:      c.FlowConfig.CustomArgs,
+	}
+
+	resp, err := c.Config.Client.ExecuteRevocationRequest(ctx, c.Config.RevocationEndpoint, req, nil /* no custom headers */)
+	if err != nil {
+		return fmt.Errorf("revocation request failed: %w", err)
+	}
+
+	if err := httpclient.ParseRevocationResponse(resp); err != nil {
+		return httpclient.WrapError(err, "revocation")
+	}
+	return nil
+}
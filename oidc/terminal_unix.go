@@ -0,0 +1,58 @@
+//go:build !windows
+
+package oidc
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+)
+
+// readPasswordPrompt prompts for a password on the controlling terminal with echo disabled
+func readPasswordPrompt(prompt string) (string, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return "", fmt.Errorf("no terminal to prompt for the password: %w", err)
+	}
+	defer tty.Close()
+
+	fmt.Fprint(tty, prompt)
+	if err := stty(tty, "-echo"); err != nil {
+		return "", fmt.Errorf("failed to disable terminal echo: %w", err)
+	}
+
+	// Restore echo if the prompt is interrupted, the terminal is left unusable otherwise
+	interrupt := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		select {
+		case <-interrupt:
+			_ = stty(tty, "echo")
+			fmt.Fprintln(tty)
+			os.Exit(130)
+		case <-done:
+		}
+	}()
+	defer func() {
+		signal.Stop(interrupt)
+		close(done)
+		_ = stty(tty, "echo")
+		fmt.Fprintln(tty)
+	}()
+
+	line, err := bufio.NewReader(tty).ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func stty(tty *os.File, args ...string) error {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = tty
+	return cmd.Run()
+}
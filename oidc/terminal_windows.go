@@ -0,0 +1,47 @@
+//go:build windows
+
+package oidc
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+)
+
+// enableEchoInput is the console mode flag that echoes typed characters
+const enableEchoInput = 0x0004
+
+var procSetConsoleMode = syscall.NewLazyDLL("kernel32.dll").NewProc("SetConsoleMode")
+
+// readPasswordPrompt prompts for a password on the console with echo disabled
+func readPasswordPrompt(prompt string) (string, error) {
+	handle := syscall.Handle(os.Stdin.Fd())
+	var mode uint32
+	if err := syscall.GetConsoleMode(handle, &mode); err != nil {
+		return "", fmt.Errorf("no console to prompt for the password: %w", err)
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	if err := setConsoleMode(handle, mode&^enableEchoInput); err != nil {
+		return "", fmt.Errorf("failed to disable console echo: %w", err)
+	}
+	defer func() {
+		_ = setConsoleMode(handle, mode)
+		fmt.Fprintln(os.Stderr)
+	}()
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func setConsoleMode(handle syscall.Handle, mode uint32) error {
+	if r, _, err := procSetConsoleMode.Call(uintptr(handle), uintptr(mode)); r == 0 {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jentz/oidc-cli/httpclient"
+	"github.com/jentz/oidc-cli/log"
+)
+
+type TokenExchangeFlow struct {
+	Config     *Config
+	FlowConfig *TokenExchangeFlowConfig
+}
+
+type TokenExchangeFlowConfig struct {
+	SubjectToken       string
+	SubjectTokenType   string
+	ActorToken         string
+	ActorTokenType     string
+	RequestedTokenType string
+	Audiences          []string
+	Resources          []string
+	Scopes             string
+}
+
+func (c *TokenExchangeFlow) Run(ctx context.Context) error {
+	client := c.Config.Client
+
+	if !c.Config.hasClientCredentials() {
+		c.Config.AuthMethod = httpclient.AuthMethodNone
+	}
+
+	req := httpclient.CreateTokenExchangeRequest(
+		c.Config.ClientID,
+		c.Config.ClientSecret,
+		c.Config.AuthMethod,
+		&httpclient.TokenExchange{
+			SubjectToken:       c.FlowConfig.SubjectToken,
+			SubjectTokenType:   httpclient.ExpandTokenType(c.FlowConfig.SubjectTokenType),
+			ActorToken:         c.FlowConfig.ActorToken,
+			ActorTokenType:     httpclient.ExpandTokenType(c.FlowConfig.ActorTokenType),
+			RequestedTokenType: httpclient.ExpandTokenType(c.FlowConfig.RequestedTokenType),
+			Audiences:          c.FlowConfig.Audiences,
+			Resources:          c.FlowConfig.Resources,
+			Scope:              c.FlowConfig.Scopes,
+		},
+	)
+	req.ClientAssertion = c.Config.clientAssertion()
+
+	resp, err := client.ExecuteTokenRequest(ctx, c.Config.TokenEndpoint, req, nil /* no custom headers */)
+	if err != nil {
+		return fmt.Errorf("token request failed: %w", err)
+	}
+
+	tokenData, err := httpclient.ParseTokenResponse(resp)
+	if err != nil {
+		return httpclient.WrapError(err, "token")
+	}
+
+	// Print available response data
+	prettyJSON, err := json.MarshalIndent(tokenData, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to format token response: %w", err)
+	}
+	log.Outputf("%s\n", string(prettyJSON))
+
+	c.printIssuedToken(tokenData)
+	warnAudienceMismatch(c.FlowConfig.Resources, tokenData)
+	return c.Config.checkCertificateBinding(tokenData)
+}
+
+// printIssuedToken prints the type of the issued token on stderr, and the token decoded if it is a JWT.
+// The issued token is returned in the access_token parameter whatever its type (RFC 8693 section 2.2.1).
+func (c *TokenExchangeFlow) printIssuedToken(tokenData map[string]interface{}) {
+	issuedTokenType, _ := tokenData["issued_token_type"].(string)
+	switch {
+	case issuedTokenType == "":
+		log.Errorf("Issued token type: missing, it is required by RFC 8693\n")
+	case c.FlowConfig.RequestedTokenType != "" && issuedTokenType != httpclient.ExpandTokenType(c.FlowConfig.RequestedTokenType):
+		log.Errorf("Issued token type: %s (requested %s)\n", issuedTokenType, httpclient.ExpandTokenType(c.FlowConfig.RequestedTokenType))
+	default:
+		log.Errorf("Issued token type: %s\n", issuedTokenType)
+	}
+
+	issuedToken, _ := tokenData["access_token"].(string)
+	if !isCompactJWT(issuedToken) {
+		return
+	}
+	decoded, err := c.Config.decodeJWT(issuedToken, 0)
+	if err != nil {
+		log.Errorf("Failed to decode issued token: %v\n", err)
+		return
+	}
+	prettyJSON, err := json.MarshalIndent(decoded, "", "  ")
+	if err != nil {
+		return
+	}
+	log.Errorf("Issued token:\n%s\n", string(prettyJSON))
+}
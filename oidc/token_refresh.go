@@ -15,14 +15,21 @@ type TokenRefreshFlow struct {
 }
 
 type TokenRefreshFlowConfig struct {
-	Scopes       string
-	RefreshToken string
+	Scopes               string
+	RefreshToken         string
+	Resources            []string // downscopes the refreshed access token (RFC 8707 section 2.2)
+	AuthorizationDetails string   // compact JSON, see ParseAuthorizationDetails
 }
 
 func (c *TokenRefreshFlow) Run(ctx context.Context) error {
 	client := c.Config.Client
 
 	req := httpclient.CreateRefreshTokenRequest(c.Config.ClientID, c.Config.ClientSecret, c.Config.AuthMethod, c.FlowConfig.RefreshToken, c.FlowConfig.Scopes)
+	req.ClientAssertion = c.Config.clientAssertion()
+	req.Resources = c.FlowConfig.Resources
+	if c.FlowConfig.AuthorizationDetails != "" {
+		req.Params.Set("authorization_details", c.FlowConfig.AuthorizationDetails)
+	}
 
 	resp, err := client.ExecuteTokenRequest(ctx, c.Config.TokenEndpoint, req, nil /* no custom headers */)
 	if err != nil {
@@ -40,5 +47,7 @@ func (c *TokenRefreshFlow) Run(ctx context.Context) error {
 		return fmt.Errorf("failed to format token response: %w", err)
 	}
 	log.Outputf("%s\n", string(prettyJSON))
-	return nil
+	reportAuthorizationDetails(c.FlowConfig.AuthorizationDetails, tokenData)
+	warnAudienceMismatch(c.FlowConfig.Resources, tokenData)
+	return c.Config.checkCertificateBinding(tokenData)
 }
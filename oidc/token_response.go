@@ -0,0 +1,68 @@
+package oidc
+
+import (
+	"slices"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jentz/oidc-cli/crypto"
+	"github.com/jentz/oidc-cli/log"
+)
+
+// checkCertificateBinding checks that the access token in a token response is bound to the
+// client certificate (RFC 8705 section 3) and prints a report of the check. Nothing is checked
+// when no client certificate is used.
+func (c *Config) checkCertificateBinding(tokenData map[string]interface{}) error {
+	if c.ClientCertificate == nil || len(c.ClientCertificate.Certificate) == 0 {
+		return nil
+	}
+	report := &ValidationReport{Subject: "Access token certificate binding"}
+	accessToken, _ := tokenData["access_token"].(string)
+	thumbprint := crypto.CertificateThumbprint(c.ClientCertificate.Certificate[0])
+	checkCertificateThumbprint(report, accessToken, thumbprint, c.TLSClientCertificateBoundTokens)
+	report.Print()
+	return report.Err()
+}
+
+// checkCertificateThumbprint compares the cnf x5t#S256 claim of a JWT access token with the
+// thumbprint of the client certificate. A missing claim only fails if the server advertises
+// certificate-bound access tokens.
+func checkCertificateThumbprint(report *ValidationReport, accessToken, thumbprint string, required bool) {
+	const name = "cnf.x5t#S256"
+	parsed, _, err := jwt.NewParser().ParseUnverified(accessToken, jwt.MapClaims{})
+	if err != nil {
+		report.skip(name, "access token is not a JWT, use introspect to see its binding")
+		return
+	}
+	cnf, _ := parsed.Claims.(jwt.MapClaims)["cnf"].(map[string]interface{})
+	got, ok := cnf["x5t#S256"].(string)
+	switch {
+	case !ok && required:
+		report.fail(name, "missing although the server issues certificate-bound access tokens")
+	case !ok:
+		report.skip(name, "access token is not bound to a certificate")
+	case got != thumbprint:
+		report.fail(name, "%q does not match the client certificate thumbprint %q", got, thumbprint)
+	default:
+		report.pass(name, "matches the client certificate")
+	}
+}
+
+// warnAudienceMismatch warns when the access token in a token response is a JWT whose aud does not
+// include a resource it was requested for (RFC 8707 section 2). Opaque access tokens are not checked.
+func warnAudienceMismatch(resources []string, tokenData map[string]interface{}) {
+	if len(resources) == 0 {
+		return
+	}
+	accessToken, _ := tokenData["access_token"].(string)
+	parsed, _, err := jwt.NewParser().ParseUnverified(accessToken, jwt.MapClaims{})
+	if err != nil {
+		log.Printf("access token is not a JWT, its aud is not checked against the requested resources\n")
+		return
+	}
+	aud, _ := parsed.Claims.GetAudience()
+	for _, resource := range resources {
+		if !slices.Contains(aud, resource) {
+			log.Errorf("WARNING: access token aud %v does not include the requested resource %s\n", []string(aud), resource)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package oidc
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jentz/oidc-cli/log"
+)
+
+func TestCheckCertificateThumbprint(t *testing.T) {
+	token := func(cnf map[string]any) string {
+		claims := jwt.MapClaims{"sub": "alice"}
+		if cnf != nil {
+			claims["cnf"] = cnf
+		}
+		signed, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+		return signed
+	}
+
+	tests := []struct {
+		name        string
+		accessToken string
+		required    bool
+		want        CheckStatus
+	}{
+		{"matching thumbprint", token(map[string]any{"x5t#S256": "thumbprint"}), false, CheckPassed},
+		{"other thumbprint", token(map[string]any{"x5t#S256": "other"}), false, CheckFailed},
+		{"not bound", token(nil), false, CheckSkipped},
+		{"not bound but required", token(map[string]any{"jkt": "thumbprint"}), true, CheckFailed},
+		{"opaque token", "opaque", true, CheckSkipped},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := &ValidationReport{}
+			checkCertificateThumbprint(report, tt.accessToken, "thumbprint", tt.required)
+			if len(report.Checks) != 1 || report.Checks[0].Status != tt.want {
+				t.Errorf("checkCertificateThumbprint() = %+v, want status %s", report.Checks, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiscoveryEndpointAlias(t *testing.T) {
+	d := &DiscoveryConfiguration{
+		TokenEndpoint:       "https://example.com/token",
+		MTLSEndpointAliases: map[string]string{"token_endpoint": "https://mtls.example.com/token"},
+	}
+	if got := d.endpoint("token_endpoint", d.TokenEndpoint, false); got != "https://example.com/token" {
+		t.Errorf("endpoint() without mtls = %q", got)
+	}
+	if got := d.endpoint("token_endpoint", d.TokenEndpoint, true); got != "https://mtls.example.com/token" {
+		t.Errorf("endpoint() with mtls = %q", got)
+	}
+	if got := d.endpoint("revocation_endpoint", "https://example.com/revoke", true); got != "https://example.com/revoke" {
+		t.Errorf("endpoint() without alias = %q", got)
+	}
+}
+
+func TestWarnAudienceMismatch(t *testing.T) {
+	token := func(aud any) string {
+		signed, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "alice", "aud": aud}).SignedString([]byte("secret"))
+		return signed
+	}
+
+	tests := []struct {
+		name        string
+		resources   []string
+		accessToken string
+		wantWarning bool
+	}{
+		{"matching aud", []string{"https://api.example.com"}, token("https://api.example.com"), false},
+		{"aud includes all resources", []string{"https://a.example.com", "https://b.example.com"}, token([]string{"https://a.example.com", "https://b.example.com"}), false},
+		{"other aud", []string{"https://api.example.com"}, token("https://other.example.com"), true},
+		{"resource missing from aud", []string{"https://a.example.com", "https://b.example.com"}, token([]string{"https://a.example.com"}), true},
+		{"opaque token", []string{"https://api.example.com"}, "opaque", false},
+		{"no resources requested", nil, token("https://other.example.com"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stderr bytes.Buffer
+			log.SetDefaultLogger(log.WithStderr(&stderr), log.WithStdout(io.Discard))
+			defer log.SetDefaultLogger(log.WithVerbose(true), log.WithStderr(os.Stderr), log.WithStdout(os.Stdout))
+
+			warnAudienceMismatch(tt.resources, map[string]interface{}{"access_token": tt.accessToken})
+			if got := strings.Contains(stderr.String(), "WARNING"); got != tt.wantWarning {
+				t.Errorf("warning = %v, want %v, stderr %q", got, tt.wantWarning, stderr.String())
+			}
+		})
+	}
+}
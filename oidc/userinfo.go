@@ -0,0 +1,127 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jentz/oidc-cli/crypto"
+	"github.com/jentz/oidc-cli/httpclient"
+	"github.com/jentz/oidc-cli/log"
+)
+
+type UserinfoFlow struct {
+	Config     *Config
+	FlowConfig *UserinfoFlowConfig
+}
+
+type UserinfoFlowConfig struct {
+	AccessToken string
+	DPoP        bool
+}
+
+func (c *UserinfoFlow) Run(ctx context.Context) error {
+	claims, err := c.Config.fetchUserinfo(ctx, c.FlowConfig.AccessToken, c.FlowConfig.DPoP)
+	if err != nil {
+		return err
+	}
+
+	// Print available response data
+	prettyJSON, err := json.MarshalIndent(claims, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to format userinfo response: %w", err)
+	}
+	log.Outputf("%s\n", string(prettyJSON))
+	return nil
+}
+
+// fetchUserinfo calls the UserInfo endpoint with the access token and returns the claims,
+// decrypting and verifying JWT responses as needed.
+func (c *Config) fetchUserinfo(ctx context.Context, accessToken string, dpop bool) (map[string]interface{}, error) {
+	if c.UserinfoEndpoint == "" {
+		return nil, errors.New("userinfo endpoint is not available, use --userinfo-url to set it")
+	}
+
+	req := &httpclient.UserinfoRequest{
+		AccessToken: accessToken,
+		TokenType:   httpclient.TokenTypeBearer,
+	}
+	var proof httpclient.DPoPProofFunc
+	if dpop {
+		req.TokenType = httpclient.TokenTypeDPoP
+		proof = c.dpopProof(http.MethodGet, c.UserinfoEndpoint, accessToken)
+	}
+
+	resp, err := c.Client.ExecuteWithDPoP(c.UserinfoEndpoint, proof, func(headers map[string]string) (*httpclient.Response, error) {
+		return c.Client.ExecuteUserinfoRequest(ctx, c.UserinfoEndpoint, req, headers)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("userinfo request failed: %w", err)
+	}
+
+	userinfo, err := httpclient.ParseUserinfoResponse(resp)
+	if err != nil {
+		var challengeErr *httpclient.ChallengeError
+		if errors.As(err, &challengeErr) {
+			if challenges, jsonErr := json.MarshalIndent(challengeErr.Challenges, "", "  "); jsonErr == nil {
+				log.Errorf("%s\n", string(challenges))
+			}
+		}
+		return nil, httpclient.WrapError(err, "userinfo")
+	}
+
+	if userinfo.JWT == "" {
+		return userinfo.Claims, nil
+	}
+	return c.parseUserinfoJWT(ctx, userinfo.JWT)
+}
+
+// parseUserinfoJWT decrypts and verifies a signed and/or encrypted UserInfo response (OIDC Core section 5.3.2)
+func (c *Config) parseUserinfoJWT(ctx context.Context, token string) (map[string]interface{}, error) {
+	if crypto.IsJWE(token) {
+		if c.PrivateKey == nil {
+			return nil, errors.New("userinfo response is encrypted, use --private-key to decrypt it")
+		}
+		plaintext, cty, err := crypto.DecryptJWE(token, c.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt userinfo response: %w", err)
+		}
+		// An encrypted response is either a nested JWT or the plain JSON claims
+		if !strings.EqualFold(cty, "JWT") && strings.Count(string(plaintext), ".") != 2 {
+			var claims map[string]interface{}
+			if err := json.Unmarshal(plaintext, &claims); err != nil {
+				return nil, fmt.Errorf("failed to parse decrypted userinfo response: %w", err)
+			}
+			return claims, nil
+		}
+		token = strings.TrimSpace(string(plaintext))
+	}
+
+	keys, err := c.JWKS(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch keys to verify userinfo response: %w", err)
+	}
+	parsed, _, err := crypto.VerifyJWTSignature(token, keys)
+	if err != nil {
+		return nil, fmt.Errorf("invalid userinfo response: %w", err)
+	}
+	claims, ok := parsed.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errors.New("invalid userinfo response: unexpected claims type")
+	}
+
+	// Signed responses must be issued by the issuer to this client
+	if iss, _ := claims["iss"].(string); iss != c.IssuerURL {
+		return nil, fmt.Errorf("invalid userinfo response: iss %q does not match issuer %q", iss, c.IssuerURL)
+	}
+	if c.ClientID == "" {
+		log.Printf("no client-id set, skipping aud check of userinfo response\n")
+	} else if !audienceContains(claims["aud"], c.ClientID) {
+		return nil, fmt.Errorf("invalid userinfo response: aud %v does not contain client id %q", claims["aud"], c.ClientID)
+	}
+	return claims, nil
+}
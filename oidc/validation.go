@@ -0,0 +1,255 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jentz/oidc-cli/crypto"
+	"github.com/jentz/oidc-cli/log"
+)
+
+// DefaultClockSkew is the default tolerance applied to time based claims
+const DefaultClockSkew = 60 * time.Second
+
+// timeNow is used for time based checks and can be replaced in tests
+var timeNow = time.Now
+
+// CheckStatus is the outcome of a single validation check
+type CheckStatus string
+
+const (
+	CheckPassed  CheckStatus = "PASS"
+	CheckFailed  CheckStatus = "FAIL"
+	CheckSkipped CheckStatus = "SKIP"
+)
+
+// Check is the result of validating one aspect of a token
+type Check struct {
+	Name   string
+	Status CheckStatus
+	Detail string
+}
+
+// ValidationReport collects the individual checks performed when validating a token,
+// so that every check is reported rather than stopping at the first failure.
+type ValidationReport struct {
+	Subject string
+	Checks  []Check
+}
+
+func (r *ValidationReport) pass(name, format string, a ...any) {
+	r.Checks = append(r.Checks, Check{Name: name, Status: CheckPassed, Detail: fmt.Sprintf(format, a...)})
+}
+
+func (r *ValidationReport) fail(name, format string, a ...any) {
+	r.Checks = append(r.Checks, Check{Name: name, Status: CheckFailed, Detail: fmt.Sprintf(format, a...)})
+}
+
+func (r *ValidationReport) skip(name, format string, a ...any) {
+	r.Checks = append(r.Checks, Check{Name: name, Status: CheckSkipped, Detail: fmt.Sprintf(format, a...)})
+}
+
+// Failed returns the names of the failed checks
+func (r *ValidationReport) Failed() []string {
+	var failed []string
+	for _, c := range r.Checks {
+		if c.Status == CheckFailed {
+			failed = append(failed, c.Name)
+		}
+	}
+	return failed
+}
+
+// Print writes the report to stderr, one line per check
+func (r *ValidationReport) Print() {
+	log.Errorf("%s validation:\n", r.Subject)
+	for _, c := range r.Checks {
+		log.Errorf("  [%s] %s: %s\n", c.Status, c.Name, c.Detail)
+	}
+}
+
+// Err returns an error listing the failed checks, or nil if all checks passed or were skipped
+func (r *ValidationReport) Err() error {
+	if failed := r.Failed(); len(failed) > 0 {
+		return fmt.Errorf("%s validation failed: %s", r.Subject, strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// parseForValidation decrypts the token if it is a JWE and parses it without verifying the signature,
+// so that the claim checks are reported even when the signature cannot be verified.
+// The parsed token is nil if the token could not be decrypted or parsed.
+func (c *Config) parseForValidation(report *ValidationReport, token string) (string, *jwt.Token) {
+	if crypto.IsJWE(token) {
+		if c.PrivateKey == nil {
+			report.fail("decryption", "%s is encrypted but no private key is configured", report.Subject)
+			return "", nil
+		}
+		plaintext, _, err := crypto.DecryptJWE(token, c.PrivateKey)
+		if err != nil {
+			report.fail("decryption", "%v", err)
+			return "", nil
+		}
+		report.pass("decryption", "decrypted with the configured private key")
+		token = strings.TrimSpace(string(plaintext))
+	}
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		report.fail("format", "%v", err)
+		return "", nil
+	}
+	return token, parsed
+}
+
+func (c *Config) checkSignature(ctx context.Context, report *ValidationReport, token, alg string) {
+	if strings.HasPrefix(alg, "HS") {
+		if _, err := crypto.VerifyJWTHMAC(token, []byte(c.ClientSecret)); err != nil {
+			report.fail("signature", "%v", err)
+			return
+		}
+		report.pass("signature", "verified with the client secret (%s)", alg)
+		return
+	}
+
+	keys, err := c.JWKS(ctx)
+	if err != nil {
+		report.fail("signature", "%v", err)
+		return
+	}
+	_, key, err := crypto.VerifyJWTSignature(token, keys)
+	if err != nil && c.jwksFromCache {
+		// The keys may have been rotated since they were cached
+		log.Printf("verification with cached keys failed, refreshing jwks\n")
+		if keys, err = c.refreshJWKS(ctx); err == nil {
+			_, key, err = crypto.VerifyJWTSignature(token, keys)
+		}
+	}
+	if err != nil {
+		report.fail("signature", "%v", err)
+		return
+	}
+	report.pass("signature", "verified with key %q (%s)", key.Kid, alg)
+}
+
+func checkIssuer(report *ValidationReport, claims jwt.MapClaims, issuer string) {
+	iss, _ := claims["iss"].(string)
+	if iss != issuer {
+		report.fail("iss", "%q does not match issuer %q", iss, issuer)
+		return
+	}
+	report.pass("iss", "%q", iss)
+}
+
+func checkAudience(report *ValidationReport, claims jwt.MapClaims, audience string) {
+	if audience == "" {
+		report.skip("aud", "%v, no expected audience given", claims["aud"])
+		return
+	}
+	if !audienceContains(claims["aud"], audience) {
+		report.fail("aud", "%v does not contain %q", claims["aud"], audience)
+		return
+	}
+	report.pass("aud", "contains %q", audience)
+}
+
+func checkExpiry(report *ValidationReport, claims jwt.MapClaims, skew time.Duration, required bool) {
+	exp, err := claims.GetExpirationTime()
+	switch {
+	case err != nil:
+		report.fail("exp", "%v", err)
+	case exp == nil && required:
+		report.fail("exp", "missing")
+	case exp == nil:
+		report.skip("exp", "not present")
+	case !timeNow().Add(-skew).Before(exp.Time):
+		report.fail("exp", "expired at %s", formatTime(exp.Time))
+	default:
+		report.pass("exp", "expires at %s", formatTime(exp.Time))
+	}
+}
+
+func checkIssuedAt(report *ValidationReport, claims jwt.MapClaims, skew time.Duration, required bool) {
+	iat, err := claims.GetIssuedAt()
+	switch {
+	case err != nil:
+		report.fail("iat", "%v", err)
+	case iat == nil && required:
+		report.fail("iat", "missing")
+	case iat == nil:
+		report.skip("iat", "not present")
+	case iat.After(timeNow().Add(skew)):
+		report.fail("iat", "issued in the future at %s", formatTime(iat.Time))
+	default:
+		report.pass("iat", "issued at %s", formatTime(iat.Time))
+	}
+}
+
+func checkNotBefore(report *ValidationReport, claims jwt.MapClaims, skew time.Duration) {
+	nbf, err := claims.GetNotBefore()
+	switch {
+	case err != nil:
+		report.fail("nbf", "%v", err)
+	case nbf == nil:
+		report.skip("nbf", "not present")
+	case nbf.After(timeNow().Add(skew)):
+		report.fail("nbf", "not valid before %s", formatTime(nbf.Time))
+	default:
+		report.pass("nbf", "valid since %s", formatTime(nbf.Time))
+	}
+}
+
+// checkType compares the typ header with the expected media type. The "application/"
+// prefix is optional and the comparison is case insensitive (RFC 7515 section 4.1.9).
+func checkType(report *ValidationReport, header map[string]interface{}, expected string) {
+	typ, _ := header["typ"].(string)
+	if expected == "" {
+		report.skip("typ", "%q, no expected type given", typ)
+		return
+	}
+	normalize := func(s string) string {
+		s = strings.ToLower(s)
+		return strings.TrimPrefix(s, "application/")
+	}
+	if normalize(typ) != normalize(expected) {
+		report.fail("typ", "%q does not match %q", typ, expected)
+		return
+	}
+	report.pass("typ", "%q", typ)
+}
+
+// checkRequiredClaims checks that all named claims are present
+func checkRequiredClaims(report *ValidationReport, claims jwt.MapClaims, names ...string) {
+	var missing []string
+	for _, name := range names {
+		if _, ok := claims[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		report.fail("required claims", "missing %s", strings.Join(missing, ", "))
+		return
+	}
+	report.pass("required claims", "%s present", strings.Join(names, ", "))
+}
+
+// numericClaim returns a NumericDate claim as a time
+func numericClaim(claims jwt.MapClaims, name string) (time.Time, bool) {
+	switch v := claims[name].(type) {
+	case float64:
+		return time.Unix(int64(v), 0), true
+	case json.Number:
+		if f, err := v.Float64(); err == nil {
+			return time.Unix(int64(f), 0), true
+		}
+	}
+	return time.Time{}, false
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
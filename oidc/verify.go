@@ -0,0 +1,70 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jentz/oidc-cli/log"
+)
+
+// TokenTypeAccessToken is the typ header of JWT access tokens (RFC 9068)
+const TokenTypeAccessToken = "at+jwt"
+
+type VerifyFlow struct {
+	Config     *Config
+	FlowConfig *VerifyFlowConfig
+}
+
+type VerifyFlowConfig struct {
+	Token     string
+	Audience  string
+	TokenType string
+	ClockSkew time.Duration
+}
+
+func (c *VerifyFlow) Run(ctx context.Context) error {
+	report, claims := c.Config.validateJWT(ctx, c.FlowConfig.Token, c.FlowConfig)
+
+	// Print the claims even if validation fails, so they can be inspected
+	if claims != nil {
+		prettyJSON, err := json.MarshalIndent(claims, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format claims: %w", err)
+		}
+		log.Outputf("%s\n", string(prettyJSON))
+	}
+
+	report.Print()
+	return report.Err()
+}
+
+// validateJWT verifies the signature of a JWT against the issuer's keys and checks its registered claims.
+// Tokens of type at+jwt are additionally checked for the claims required by RFC 9068.
+func (c *Config) validateJWT(ctx context.Context, token string, expect *VerifyFlowConfig) (*ValidationReport, jwt.MapClaims) {
+	report := &ValidationReport{Subject: "JWT"}
+
+	token, parsed := c.parseForValidation(report, token)
+	if parsed == nil {
+		return report, nil
+	}
+	claims := parsed.Claims.(jwt.MapClaims)
+	alg, _ := parsed.Header["alg"].(string)
+	accessToken := strings.TrimPrefix(strings.ToLower(expect.TokenType), "application/") == TokenTypeAccessToken
+
+	c.checkSignature(ctx, report, token, alg)
+	checkType(report, parsed.Header, expect.TokenType)
+	checkIssuer(report, claims, c.IssuerURL)
+	checkAudience(report, claims, expect.Audience)
+	checkExpiry(report, claims, expect.ClockSkew, accessToken)
+	checkNotBefore(report, claims, expect.ClockSkew)
+	checkIssuedAt(report, claims, expect.ClockSkew, accessToken)
+	if accessToken {
+		checkRequiredClaims(report, claims, "iss", "exp", "aud", "sub", "client_id", "iat", "jti")
+	}
+
+	return report, claims
+}
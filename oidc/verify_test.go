@@ -0,0 +1,141 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jentz/oidc-cli/crypto"
+)
+
+func TestValidateJWT(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	jwk, _ := crypto.NewJWK(&key.PublicKey, "k1")
+
+	accessTokenClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":       "https://example.com",
+			"sub":       "alice",
+			"aud":       "https://api.example.com",
+			"client_id": "client-id",
+			"exp":       now.Add(time.Hour).Unix(),
+			"iat":       now.Unix(),
+			"jti":       "jti-1",
+		}
+	}
+	sign := func(typ string, claims jwt.MapClaims) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+		token.Header["kid"] = "k1"
+		if typ != "" {
+			token.Header["typ"] = typ
+		}
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatalf("failed to sign token: %v", err)
+		}
+		return signed
+	}
+	with := func(name string, value any) jwt.MapClaims {
+		claims := accessTokenClaims()
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name       string
+		token      string
+		expect     VerifyFlowConfig
+		wantFailed []string
+	}{
+		{
+			name:   "valid access token",
+			token:  sign("at+jwt", accessTokenClaims()),
+			expect: VerifyFlowConfig{Audience: "https://api.example.com", TokenType: "at+jwt"},
+		},
+		{
+			name:   "application prefix in typ",
+			token:  sign("application/at+jwt", accessTokenClaims()),
+			expect: VerifyFlowConfig{TokenType: "at+jwt"},
+		},
+		{
+			name:       "wrong typ",
+			token:      sign("JWT", accessTokenClaims()),
+			expect:     VerifyFlowConfig{TokenType: "at+jwt"},
+			wantFailed: []string{"typ"},
+		},
+		{
+			name:       "wrong audience",
+			token:      sign("at+jwt", accessTokenClaims()),
+			expect:     VerifyFlowConfig{Audience: "https://other.example.com"},
+			wantFailed: []string{"aud"},
+		},
+		{
+			name:       "not yet valid",
+			token:      sign("", with("nbf", now.Add(time.Hour).Unix())),
+			wantFailed: []string{"nbf"},
+		},
+		{
+			name:       "expired",
+			token:      sign("", with("exp", now.Add(-time.Hour).Unix())),
+			wantFailed: []string{"exp"},
+		},
+		{
+			name:  "generic jwt without exp and iat",
+			token: sign("", with("exp", nil)),
+		},
+		{
+			name:       "access token missing required claims",
+			token:      sign("at+jwt", with("client_id", nil)),
+			expect:     VerifyFlowConfig{TokenType: "at+jwt"},
+			wantFailed: []string{"required claims"},
+		},
+		{
+			name:       "access token missing exp",
+			token:      sign("at+jwt", with("exp", nil)),
+			expect:     VerifyFlowConfig{TokenType: "at+jwt"},
+			wantFailed: []string{"exp", "required claims"},
+		},
+		{
+			name:       "wrong issuer",
+			token:      sign("", with("iss", "https://evil.example.com")),
+			wantFailed: []string{"iss"},
+		},
+		{
+			name:       "not a jwt",
+			token:      "not-a-jwt",
+			wantFailed: []string{"format"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				IssuerURL: "https://example.com",
+				jwks:      &crypto.JWKSet{Keys: []crypto.JWK{*jwk}},
+			}
+			tt.expect.ClockSkew = DefaultClockSkew
+			report, _ := cfg.validateJWT(context.Background(), tt.token, &tt.expect)
+			failed := report.Failed()
+			if len(failed) != len(tt.wantFailed) {
+				t.Fatalf("validateJWT() failed checks = %v, want %v", failed, tt.wantFailed)
+			}
+			for i := range failed {
+				if failed[i] != tt.wantFailed[i] {
+					t.Errorf("validateJWT() failed checks = %v, want %v", failed, tt.wantFailed)
+				}
+			}
+		})
+	}
+}
@@ -24,13 +24,23 @@ type CallbackServer struct {
 	response chan *CallbackResponse
 	// listen is the function to create a network listener. If nil, defaults to net.Listen.
 	// This field allows for dependency injection in tests.
-	listen      func(network, addr string) (net.Listener, error)
-	successTmpl *template.Template
-	errorTmpl   *template.Template
+	listen       func(network, addr string) (net.Listener, error)
+	successTmpl  *template.Template
+	errorTmpl    *template.Template
+	fragmentPage []byte
+	postLogout   bool // catch a post-logout redirect instead of an authorization response
 }
 
 type CallbackResponse struct {
 	Code             string
+	State            string
+	Issuer           string // RFC 9207 iss parameter, if sent by the authorization server
+	IDToken          string // tokens issued from the authorization endpoint in the implicit and hybrid flows
+	AccessToken      string
+	TokenType        string
+	ExpiresIn        string
+	Scope            string
+	Response         string // JWT carrying the parameters above in JARM response modes, verified by the caller
 	ErrorMsg         string
 	ErrorDescription string
 }
@@ -51,49 +61,51 @@ func NewCallbackServer(callbackURI string) (*CallbackServer, error) {
 		return nil, fmt.Errorf("failed to parse error template: %w", err)
 	}
 
+	fragmentPage, err := content.ReadFile("html/callback-fragment.html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fragment page: %w", err)
+	}
+
 	return &CallbackServer{
-		host:        u.Host,
-		path:        u.Path,
-		response:    make(chan *CallbackResponse, 1),
-		listen:      net.Listen,
-		successTmpl: successTmpl,
-		errorTmpl:   errorTmpl,
+		host:         u.Host,
+		path:         u.Path,
+		response:     make(chan *CallbackResponse, 1),
+		listen:       net.Listen,
+		successTmpl:  successTmpl,
+		errorTmpl:    errorTmpl,
+		fragmentPage: fragmentPage,
 	}, nil
 }
 
+// NewPostLogoutCallbackServer creates a callback server for the post-logout redirect of
+// RP-initiated logout (OpenID Connect RP-Initiated Logout section 3), which only carries the state.
+func NewPostLogoutCallbackServer(callbackURI string) (*CallbackServer, error) {
+	s, err := NewCallbackServer(callbackURI)
+	if err != nil {
+		return nil, err
+	}
+	s.successTmpl, err = template.ParseFS(content, "html/logout-success.html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse logout template: %w", err)
+	}
+	s.postLogout = true
+	return s, nil
+}
+
 func (s *CallbackServer) Start(ctx context.Context) error {
 	mux := http.NewServeMux()
-	mux.HandleFunc(s.path, s.handleCallback)
+	if s.postLogout {
+		mux.HandleFunc(s.path, s.handlePostLogout)
+	} else {
+		mux.HandleFunc(s.path, s.handleCallback)
+	}
 
 	s.server = &http.Server{
 		Addr:        s.host,
 		Handler:     mux,
 		ReadTimeout: 10 * time.Second,
 	}
-
-	// Create a listener first to ensure we can bind to the port
-	listener, err := s.listen("tcp", s.host)
-	if err != nil {
-		return fmt.Errorf("failed to listen on %s: %w", s.host, err)
-	}
-
-	// Channel to catch server errors
-	errChan := make(chan error, 1)
-	go func() {
-		errChan <- s.server.Serve(listener)
-	}()
-
-	// Wait for context cancellation or server error
-	select {
-	case <-ctx.Done():
-		// Initiate graceful shutdown
-		return s.server.Shutdown(context.Background())
-	case err := <-errChan:
-		if errors.Is(err, http.ErrServerClosed) {
-			return nil
-		}
-		return err
-	}
+	return serve(ctx, s.server, s.listen)
 }
 
 func (s *CallbackServer) WaitForCallback(ctx context.Context) (*CallbackResponse, error) {
@@ -107,15 +119,43 @@ func (s *CallbackServer) WaitForCallback(ctx context.Context) (*CallbackResponse
 	}
 }
 
+// handleCallback reads the authorization response from the query or from a form post.
+// JARM responses arrive in the response parameter. A fragment never reaches the server,
+// so a page is served that posts the fragment back to the callback.
 func (s *CallbackServer) handleCallback(w http.ResponseWriter, r *http.Request) {
 	var resp CallbackResponse
 	var tmpl *template.Template
 
-	resp.Code = r.URL.Query().Get("code")
-	resp.ErrorMsg = r.URL.Query().Get("error")
-	resp.ErrorDescription = r.URL.Query().Get("error_description")
+	params := r.URL.Query()
+	if r.Method == http.MethodPost {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "invalid form body", http.StatusBadRequest)
+			return
+		}
+		params = r.PostForm
+	} else if len(params) == 0 {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(s.fragmentPage)
+		return
+	}
 
-	if resp.Code == "" {
+	resp.Code = params.Get("code")
+	resp.State = params.Get("state")
+	resp.Issuer = params.Get("iss")
+	resp.IDToken = params.Get("id_token")
+	resp.AccessToken = params.Get("access_token")
+	resp.TokenType = params.Get("token_type")
+	resp.ExpiresIn = params.Get("expires_in")
+	resp.Scope = params.Get("scope")
+	resp.Response = params.Get("response")
+	resp.ErrorMsg = params.Get("error")
+	resp.ErrorDescription = params.Get("error_description")
+
+	if resp.Code == "" && resp.IDToken == "" && resp.AccessToken == "" && resp.Response == "" {
+		if resp.ErrorMsg == "" {
+			resp.ErrorMsg = "no authorization response"
+			resp.ErrorDescription = "The callback carried neither a code, a token nor an error."
+		}
 		tmpl = s.errorTmpl
 		w.WriteHeader(http.StatusBadRequest)
 	} else {
@@ -137,3 +177,22 @@ func (s *CallbackServer) handleCallback(w http.ResponseWriter, r *http.Request)
 		log.Errorf("callback response channel is full, dropping response")
 	}
 }
+
+// handlePostLogout reads the state from the post-logout redirect. The redirect carries no other
+// parameters, so any request to the callback completes the logout.
+func (s *CallbackServer) handlePostLogout(w http.ResponseWriter, r *http.Request) {
+	resp := CallbackResponse{State: r.URL.Query().Get("state")}
+
+	w.WriteHeader(http.StatusOK)
+	if err := s.successTmpl.Execute(w, resp); err != nil {
+		log.Errorf("failed to execute template: %v", err)
+		return
+	}
+
+	select {
+	case s.response <- &resp:
+		// Successfully sent the response
+	default:
+		log.Errorf("callback response channel is full, dropping response")
+	}
+}
@@ -170,6 +170,15 @@ func TestCallbackServerHandleCallback(t *testing.T) {
 			wantBody:     "<p>Success: abc123</p>",
 			wantResponse: &CallbackResponse{Code: "abc123"},
 		},
+		{
+			name:         "Success callback with state and iss",
+			query:        "code=abc123&state=xyz&iss=https%3A%2F%2Fexample.com",
+			successTmpl:  template.Must(template.New("success").Parse("<p>Success: {{.Code}}</p>")),
+			errorTmpl:    template.Must(template.New("error").Parse("<p>Error: {{.ErrorMsg}} - {{.ErrorDescription}}</p>")),
+			wantStatus:   http.StatusOK,
+			wantBody:     "<p>Success: abc123</p>",
+			wantResponse: &CallbackResponse{Code: "abc123", State: "xyz", Issuer: "https://example.com"},
+		},
 		{
 			name:         "Error callback",
 			query:        "error=invalid_grant&error_description=Bad+request",
@@ -231,6 +240,8 @@ func TestCallbackServerHandleCallback(t *testing.T) {
 				select {
 				case got := <-s.response:
 					if got.Code != tt.wantResponse.Code ||
+						got.State != tt.wantResponse.State ||
+						got.Issuer != tt.wantResponse.Issuer ||
 						got.ErrorMsg != tt.wantResponse.ErrorMsg ||
 						got.ErrorDescription != tt.wantResponse.ErrorDescription {
 						t.Errorf("expected response %v, got %v", tt.wantResponse, got)
@@ -251,3 +262,87 @@ func TestCallbackServerHandleCallback(t *testing.T) {
 		})
 	}
 }
+
+func TestCallbackServerHandleCallbackResponseModes(t *testing.T) {
+	s, err := NewCallbackServer("http://localhost:8080/callback")
+	if err != nil {
+		t.Skipf("Skipping due to template parsing error: %v", err)
+	}
+
+	// Fragment: without query parameters the page relaying the fragment is served
+	w := httptest.NewRecorder()
+	s.handleCallback(w, httptest.NewRequest("GET", "/callback", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "window.location.hash") {
+		t.Errorf("expected fragment page, got status %d body %q", w.Code, w.Body.String())
+	}
+	select {
+	case got := <-s.response:
+		t.Errorf("expected no response for fragment page, got %v", got)
+	default:
+	}
+
+	// Form post: the response is read from the body, as sent by the fragment page or the authorization server
+	body := "code=abc123&state=xyz&id_token=header.payload.signature&access_token=at&token_type=Bearer&expires_in=3600"
+	r := httptest.NewRequest("POST", "/callback", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w = httptest.NewRecorder()
+	s.handleCallback(w, r)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	want := CallbackResponse{Code: "abc123", State: "xyz", IDToken: "header.payload.signature", AccessToken: "at", TokenType: "Bearer", ExpiresIn: "3600"}
+	select {
+	case got := <-s.response:
+		if *got != want {
+			t.Errorf("expected response %+v, got %+v", want, *got)
+		}
+	default:
+		t.Error("expected response in channel, got none")
+	}
+
+	// JARM: the response parameter is passed on to be verified by the caller
+	w = httptest.NewRecorder()
+	s.handleCallback(w, httptest.NewRequest("GET", "/callback?response=header.payload.signature", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if got := <-s.response; got.Response != "header.payload.signature" {
+		t.Errorf("expected response parameter, got %+v", *got)
+	}
+
+	// An empty fragment posted back is an error
+	r = httptest.NewRequest("POST", "/callback", strings.NewReader(""))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w = httptest.NewRecorder()
+	s.handleCallback(w, r)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if got := <-s.response; got.ErrorMsg == "" {
+		t.Error("expected error in response, got none")
+	}
+}
+
+func TestCallbackServerHandlePostLogout(t *testing.T) {
+	s, err := NewPostLogoutCallbackServer("http://localhost:8080/logout")
+	if err != nil {
+		t.Skipf("Skipping due to template parsing error: %v", err)
+	}
+	if !s.postLogout || s.path != "/logout" {
+		t.Fatalf("expected post-logout server on /logout, got postLogout=%v path=%q", s.postLogout, s.path)
+	}
+
+	w := httptest.NewRecorder()
+	s.handlePostLogout(w, httptest.NewRequest("GET", "/logout?state=xyz", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "Logged out") {
+		t.Errorf("expected logout page, got status %d body %q", w.Code, w.Body.String())
+	}
+	select {
+	case got := <-s.response:
+		if got.State != "xyz" {
+			t.Errorf("expected state xyz, got %q", got.State)
+		}
+	default:
+		t.Error("expected response in channel, got none")
+	}
+}
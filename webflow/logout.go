@@ -0,0 +1,176 @@
+package webflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/jentz/oidc-cli/log"
+)
+
+// Channels a logout notification can arrive on
+const (
+	LogoutBackChannel  = "back-channel"
+	LogoutFrontChannel = "front-channel"
+)
+
+// LogoutServer receives logout notifications on the back-channel logout URI
+// (OpenID Connect Back-Channel Logout section 2.5) and the front-channel logout URI
+// (OpenID Connect Front-Channel Logout section 2). Both are served from one address.
+type LogoutServer struct {
+	addr             string
+	backChannelPath  string
+	frontChannelPath string
+	validate         func(ctx context.Context, logoutToken string) error
+	server           *http.Server
+	notifications    chan *LogoutNotification
+	// listen is the function to create a network listener. If nil, defaults to net.Listen.
+	// This field allows for dependency injection in tests.
+	listen func(network, addr string) (net.Listener, error)
+}
+
+type LogoutNotification struct {
+	Channel     string
+	LogoutToken string // back-channel logout token
+	Err         error  // validation error of the logout token
+	Issuer      string // front-channel iss parameter, if sent
+	SessionID   string // front-channel sid parameter, if sent
+}
+
+// frontChannelPage is returned to the front-channel request, which the OP typically loads in a hidden iframe
+const frontChannelPage = "<html><body><p>Logged out</p></body></html>"
+
+// NewLogoutServer creates a server for the back-channel and front-channel logout URIs, either of
+// which may be empty. Logout tokens are checked with validate before the request is answered, so
+// that the OP learns whether the logout succeeded. The server listens on the host of the URIs
+// unless listenAddr is set, e.g. when the URIs are reached through a proxy or tunnel.
+func NewLogoutServer(backChannelURI, frontChannelURI, listenAddr string, validate func(ctx context.Context, logoutToken string) error) (*LogoutServer, error) {
+	s := &LogoutServer{
+		addr:          listenAddr,
+		validate:      validate,
+		notifications: make(chan *LogoutNotification, 16), // room for logouts of several sessions at once
+		listen:        net.Listen,
+	}
+
+	for _, uri := range []struct {
+		value string
+		path  *string
+	}{
+		{backChannelURI, &s.backChannelPath},
+		{frontChannelURI, &s.frontChannelPath},
+	} {
+		if uri.value == "" {
+			continue
+		}
+		u, err := url.Parse(uri.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid logout URI: %w", err)
+		}
+		if listenAddr == "" && s.addr != "" && s.addr != u.Host {
+			return nil, fmt.Errorf("logout URIs are on different hosts %s and %s, set a listen address", s.addr, u.Host)
+		}
+		if listenAddr == "" {
+			s.addr = u.Host
+		}
+		*uri.path = u.Path
+		if *uri.path == "" {
+			*uri.path = "/"
+		}
+	}
+
+	if s.backChannelPath == "" && s.frontChannelPath == "" {
+		return nil, errors.New("a back-channel or front-channel logout URI is required")
+	}
+	if s.backChannelPath == s.frontChannelPath {
+		return nil, errors.New("back-channel and front-channel logout URIs must have different paths")
+	}
+	return s, nil
+}
+
+func (s *LogoutServer) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	if s.backChannelPath != "" {
+		mux.HandleFunc(s.backChannelPath, s.handleBackChannelLogout)
+	}
+	if s.frontChannelPath != "" {
+		mux.HandleFunc(s.frontChannelPath, s.handleFrontChannelLogout)
+	}
+
+	s.server = &http.Server{
+		Addr:        s.addr,
+		Handler:     mux,
+		ReadTimeout: 10 * time.Second,
+	}
+	return serve(ctx, s.server, s.listen)
+}
+
+// WaitForNotification waits for a logout notification until the context is done
+func (s *LogoutServer) WaitForNotification(ctx context.Context) (*LogoutNotification, error) {
+	select {
+	case n := <-s.notifications:
+		return n, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// handleBackChannelLogout answers a logout token POST with 200 if the token is valid
+// and with 400 otherwise (OpenID Connect Back-Channel Logout section 2.8)
+func (s *LogoutServer) handleBackChannelLogout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<16)
+	n := &LogoutNotification{Channel: LogoutBackChannel, LogoutToken: r.PostFormValue("logout_token")}
+	if n.LogoutToken == "" {
+		n.Err = errors.New("logout_token is required")
+	} else {
+		n.Err = s.validate(r.Context(), n.LogoutToken)
+	}
+
+	if n.Err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"error":             "invalid_request",
+			"error_description": n.Err.Error(),
+		})
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+	s.send(n)
+}
+
+// handleFrontChannelLogout reads the iss and sid parameters of a front-channel logout request
+func (s *LogoutServer) handleFrontChannelLogout(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	n := &LogoutNotification{
+		Channel:   LogoutFrontChannel,
+		Issuer:    query.Get("iss"),
+		SessionID: query.Get("sid"),
+	}
+
+	w.Header().Set("Cache-Control", "no-cache, no-store")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(frontChannelPage))
+	s.send(n)
+}
+
+func (s *LogoutServer) send(n *LogoutNotification) {
+	select {
+	case s.notifications <- n:
+		// Successfully sent the notification
+	default:
+		log.Errorf("logout notification channel is full, dropping notification\n")
+	}
+}
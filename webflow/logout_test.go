@@ -0,0 +1,124 @@
+package webflow
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewLogoutServer(t *testing.T) {
+	validate := func(context.Context, string) error { return nil }
+
+	s, err := NewLogoutServer("http://localhost:9557/backchannel", "http://localhost:9557/frontchannel", "", validate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.addr != "localhost:9557" || s.backChannelPath != "/backchannel" || s.frontChannelPath != "/frontchannel" {
+		t.Errorf("got addr=%q back=%q front=%q", s.addr, s.backChannelPath, s.frontChannelPath)
+	}
+
+	s, err = NewLogoutServer("https://rp.example.com/backchannel", "", "localhost:9558", validate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.addr != "localhost:9558" || s.frontChannelPath != "" {
+		t.Errorf("got addr=%q front=%q, want addr=localhost:9558 and no front-channel path", s.addr, s.frontChannelPath)
+	}
+
+	for _, uris := range [][2]string{
+		{"", ""},
+		{"http://localhost:9557/logout", "http://localhost:9558/logout"},
+		{"http://localhost:9557/logout", "http://localhost:9557/logout"},
+	} {
+		if _, err := NewLogoutServer(uris[0], uris[1], "", validate); err == nil {
+			t.Errorf("NewLogoutServer(%q, %q) succeeded, want error", uris[0], uris[1])
+		}
+	}
+}
+
+func TestLogoutServerHandleBackChannelLogout(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		wantStatus int
+		wantErr    bool
+	}{
+		{"valid logout token", http.MethodPost, "logout_token=valid", http.StatusOK, false},
+		{"invalid logout token", http.MethodPost, "logout_token=invalid", http.StatusBadRequest, true},
+		{"missing logout token", http.MethodPost, "", http.StatusBadRequest, true},
+		{"wrong method", http.MethodGet, "", http.StatusMethodNotAllowed, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewLogoutServer("http://localhost:9557/backchannel", "", "", func(_ context.Context, token string) error {
+				if token != "valid" {
+					return errors.New("invalid signature")
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			req := httptest.NewRequest(tt.method, "/backchannel", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			rec := httptest.NewRecorder()
+			s.handleBackChannelLogout(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("got status %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantErr && !strings.Contains(rec.Body.String(), "invalid_request") {
+				t.Errorf("got body %q, want an invalid_request error", rec.Body.String())
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			n, err := s.WaitForNotification(ctx)
+			if tt.method != http.MethodPost {
+				if err == nil {
+					t.Errorf("got notification %+v, want none", n)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WaitForNotification() error = %v", err)
+			}
+			if n.Channel != LogoutBackChannel || (n.Err != nil) != tt.wantErr {
+				t.Errorf("got notification %+v, want back-channel with error %v", n, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLogoutServerHandleFrontChannelLogout(t *testing.T) {
+	s, err := NewLogoutServer("", "http://localhost:9557/frontchannel", "", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	query := url.Values{"iss": {"https://example.com"}, "sid": {"session-1"}}
+	rec := httptest.NewRecorder()
+	s.handleFrontChannelLogout(rec, httptest.NewRequest(http.MethodGet, "/frontchannel?"+query.Encode(), nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("got status %d, want %d", rec.Code, http.StatusOK)
+	}
+	if got := rec.Header().Get("Cache-Control"); !strings.Contains(got, "no-store") {
+		t.Errorf("got Cache-Control %q, want no-store", got)
+	}
+
+	n, err := s.WaitForNotification(context.Background())
+	if err != nil {
+		t.Fatalf("WaitForNotification() error = %v", err)
+	}
+	if n.Channel != LogoutFrontChannel || n.Issuer != "https://example.com" || n.SessionID != "session-1" {
+		t.Errorf("got notification %+v", n)
+	}
+}
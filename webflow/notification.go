@@ -0,0 +1,109 @@
+package webflow
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jentz/oidc-cli/log"
+)
+
+// NotificationServer receives CIBA ping callbacks on the client notification endpoint
+// (OpenID Connect CIBA Core section 10.2)
+type NotificationServer struct {
+	addr          string
+	path          string
+	token         string
+	server        *http.Server
+	notifications chan *Notification
+	// listen is the function to create a network listener. If nil, defaults to net.Listen.
+	// This field allows for dependency injection in tests.
+	listen func(network, addr string) (net.Listener, error)
+}
+
+type Notification struct {
+	AuthReqID string `json:"auth_req_id"`
+}
+
+// NewNotificationServer creates a server for the notification endpoint URI that only accepts
+// callbacks carrying the client notification token. The server listens on the host of the URI
+// unless listenAddr is set, e.g. when the URI is reached through a proxy or tunnel.
+func NewNotificationServer(notificationURI, listenAddr, token string) (*NotificationServer, error) {
+	u, err := url.Parse(notificationURI)
+	if err != nil {
+		return nil, fmt.Errorf("invalid notification URI: %w", err)
+	}
+	if listenAddr == "" {
+		listenAddr = u.Host
+	}
+	path := u.Path
+	if path == "" {
+		path = "/"
+	}
+
+	return &NotificationServer{
+		addr:          listenAddr,
+		path:          path,
+		token:         token,
+		notifications: make(chan *Notification, 4), // room for pings about other requests
+		listen:        net.Listen,
+	}, nil
+}
+
+func (s *NotificationServer) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc(s.path, s.handleNotification)
+
+	s.server = &http.Server{
+		Addr:        s.addr,
+		Handler:     mux,
+		ReadTimeout: 10 * time.Second,
+	}
+	return serve(ctx, s.server, s.listen)
+}
+
+// WaitForNotification waits for a ping callback until the context is done
+func (s *NotificationServer) WaitForNotification(ctx context.Context) (*Notification, error) {
+	select {
+	case n := <-s.notifications:
+		return n, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (s *NotificationServer) handleNotification(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+		log.Errorf("rejected notification without a valid client notification token\n")
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var n Notification
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16)).Decode(&n); err != nil || n.AuthReqID == "" {
+		http.Error(w, "auth_req_id is required", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+
+	select {
+	case s.notifications <- &n:
+		// Successfully sent the notification
+	default:
+		log.Errorf("notification channel is full, dropping notification\n")
+	}
+}
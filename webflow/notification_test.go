@@ -0,0 +1,81 @@
+package webflow
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewNotificationServer(t *testing.T) {
+	s, err := NewNotificationServer("https://client.example.com/ciba", "", "token")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.addr != "client.example.com" || s.path != "/ciba" {
+		t.Errorf("got addr=%q path=%q, want addr=client.example.com path=/ciba", s.addr, s.path)
+	}
+
+	s, err = NewNotificationServer("https://client.example.com/ciba", "localhost:9556", "token")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.addr != "localhost:9556" {
+		t.Errorf("got addr=%q, want localhost:9556", s.addr)
+	}
+}
+
+func TestNotificationServerHandleNotification(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		auth       string
+		body       string
+		wantStatus int
+		wantID     string
+	}{
+		{"valid notification", http.MethodPost, "Bearer token", `{"auth_req_id":"1c266114"}`, http.StatusNoContent, "1c266114"},
+		{"wrong token", http.MethodPost, "Bearer other", `{"auth_req_id":"1c266114"}`, http.StatusUnauthorized, ""},
+		{"missing token", http.MethodPost, "", `{"auth_req_id":"1c266114"}`, http.StatusUnauthorized, ""},
+		{"missing auth_req_id", http.MethodPost, "Bearer token", `{}`, http.StatusBadRequest, ""},
+		{"wrong method", http.MethodGet, "Bearer token", "", http.StatusMethodNotAllowed, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewNotificationServer("http://localhost:9556/ciba", "", "token")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			req := httptest.NewRequest(tt.method, "/ciba", strings.NewReader(tt.body))
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			rec := httptest.NewRecorder()
+			s.handleNotification(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("got status %d, want %d", rec.Code, tt.wantStatus)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			n, err := s.WaitForNotification(ctx)
+			if tt.wantID == "" {
+				if err == nil {
+					t.Errorf("got notification %+v, want none", n)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WaitForNotification() error = %v", err)
+			}
+			if n.AuthReqID != tt.wantID {
+				t.Errorf("got auth_req_id %q, want %q", n.AuthReqID, tt.wantID)
+			}
+		})
+	}
+}
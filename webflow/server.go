@@ -0,0 +1,37 @@
+package webflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// serve runs server on a listener created with listen until the context is cancelled,
+// then shuts it down gracefully
+func serve(ctx context.Context, server *http.Server, listen func(network, addr string) (net.Listener, error)) error {
+	// Create a listener first to ensure we can bind to the port
+	listener, err := listen("tcp", server.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", server.Addr, err)
+	}
+
+	// Channel to catch server errors
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- server.Serve(listener)
+	}()
+
+	// Wait for context cancellation or server error
+	select {
+	case <-ctx.Done():
+		// Initiate graceful shutdown
+		return server.Shutdown(context.Background())
+	case err := <-errChan:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	}
+}